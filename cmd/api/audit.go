@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/godsent-code/midtools/configs"
+	"github.com/godsent-code/midtools/internal/adapters/postgres"
+	"github.com/godsent-code/midtools/internal/application/audit"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+const auditUsage = "usage: api audit verify [-checkpoint FILE]"
+
+// runAudit implements `api audit verify`, which walks the audit log's hash
+// chain and fails if any entry was altered or removed. The chain alone cannot
+// show that entries were cut off the end, so every run prints and logs the
+// last seq and hash, and with -checkpoint also checks the log against the
+// last checkpoint in the file and appends a new one.
+func runAudit(args []string) error {
+	if len(args) == 0 {
+		return errors.New(auditUsage)
+	}
+	if args[0] != "verify" {
+		return fmt.Errorf("unknown audit action: %s\n%s", args[0], auditUsage)
+	}
+
+	fs := flag.NewFlagSet("audit verify", flag.ExitOnError)
+	checkpointFile := fs.String("checkpoint", "", "file of checkpoints from earlier runs, kept off the database host")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	var checkpoint *domain.AuditCheckpoint
+	if *checkpointFile != "" {
+		var err error
+		if checkpoint, err = readAuditCheckpoint(*checkpointFile); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+	config, err := configs.LoadConfig("./")
+	if err != nil {
+		return err
+	}
+	conn, err := pgxpool.New(ctx, config.DBSource)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	service := audit.NewAuditService(postgres.NewAuditRepository(conn))
+
+	result, err := service.Verify(ctx, checkpoint)
+	if err != nil {
+		return err
+	}
+	if !result.Valid {
+		log.Error().Int64("brokenAt", result.BrokenAt).Str("problem", result.Problem).Int64("lastSeq", result.LastSeq).Str("lastHash", result.LastHash).Msg("Audit log verification failed")
+		return fmt.Errorf("audit log is broken at entry %d: %s (%d entries before it verified)", result.BrokenAt, result.Problem, result.Entries)
+	}
+	log.Info().Int64("entries", result.Entries).Int64("lastSeq", result.LastSeq).Str("lastHash", result.LastHash).Msg("Audit log verified")
+	fmt.Printf("%d entries verified; last seq %d, last hash %s\n", result.Entries, result.LastSeq, result.LastHash)
+
+	if *checkpointFile != "" {
+		return appendAuditCheckpoint(*checkpointFile, domain.AuditCheckpoint{
+			Seq:        result.LastSeq,
+			Hash:       result.LastHash,
+			VerifiedAt: time.Now().UTC(),
+		})
+	}
+	return nil
+}
+
+// readAuditCheckpoint returns the last checkpoint in the file, one JSON
+// object per line, or nil when the file does not exist yet.
+func readAuditCheckpoint(name string) (*domain.AuditCheckpoint, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var checkpoint *domain.AuditCheckpoint
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var c domain.AuditCheckpoint
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", name, line, err)
+		}
+		checkpoint = &c
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+func appendAuditCheckpoint(name string, checkpoint domain.AuditCheckpoint) error {
+	line, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/godsent-code/midtools/configs"
+	"github.com/godsent-code/midtools/internal/adapters/postgres"
+	"github.com/godsent-code/midtools/internal/application/audit"
+	"github.com/godsent-code/midtools/internal/application/product"
+	"github.com/godsent-code/midtools/internal/application/risk_type"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const catalogUsage = "usage: api catalog import|export -products FILE -risk-types FILE [-format json|csv] [-dry-run] [-force]"
+
+// runCatalog implements `api catalog import` and `api catalog export`, which
+// seed the product and risk type catalogs from fixture files in the NIC shape
+// or write the current catalogs out to them. It talks to the database
+// directly, so staging and recovery environments need neither NIC nor a
+// running API.
+func runCatalog(args []string) error {
+	if len(args) == 0 {
+		return errors.New(catalogUsage)
+	}
+	action := args[0]
+	if action != "import" && action != "export" {
+		return fmt.Errorf("unknown catalog action: %s\n%s", action, catalogUsage)
+	}
+
+	fs := flag.NewFlagSet("catalog "+action, flag.ExitOnError)
+	productsFile := fs.String("products", "", "products file")
+	riskTypesFile := fs.String("risk-types", "", "risk types file")
+	format := fs.String("format", "", "file format: json or csv; defaults to each file's extension")
+	dryRun := fs.Bool("dry-run", false, "report what an import would change without applying it")
+	force := fs.Bool("force", false, "import a file without usable records, deactivating the whole catalog")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *productsFile == "" && *riskTypesFile == "" {
+		return errors.New("at least one of -products or -risk-types is required")
+	}
+
+	ctx := context.Background()
+	config, err := configs.LoadConfig("./")
+	if err != nil {
+		return err
+	}
+	conn, err := pgxpool.New(ctx, config.DBSource)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	syncRunRepo := postgres.NewSyncRunRepository(conn)
+	products := product.NewProductService(postgres.NewProductRepository(conn, config), syncRunRepo)
+	riskTypes := risk_type.NewRiskTypeService(postgres.NewRiskTypeRepository(conn, config), syncRunRepo)
+
+	if action == "export" {
+		return exportCatalogs(ctx, products, riskTypes, *productsFile, *riskTypesFile, *format)
+	}
+	audits := audit.NewAuditService(postgres.NewAuditRepository(conn))
+	return importCatalogs(ctx, products, riskTypes, audits, *productsFile, *riskTypesFile, *format, *dryRun, *force)
+}
+
+// importCatalogs loads risk types before products, since product mappings to
+// risk types that are not in the catalog yet are dropped.
+func importCatalogs(ctx context.Context, products product.ProductService, riskTypes risk_type.RiskTypeService, audits audit.AuditService, productsFile, riskTypesFile, format string, dryRun, force bool) error {
+	if riskTypesFile != "" {
+		data, err := os.ReadFile(riskTypesFile)
+		if err != nil {
+			return err
+		}
+		input := risk_type.ImportInput{
+			TriggerSource: "cli:" + filepath.Base(riskTypesFile),
+			DryRun:        dryRun,
+			Force:         force,
+			Format:        fileFormat(riskTypesFile, format),
+			Data:          data,
+		}
+		if err := input.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", riskTypesFile, err)
+		}
+		result, err := riskTypes.ImportRiskTypes(ctx, input)
+		if err != nil {
+			auditImport(ctx, audits, domain.AuditRiskTypeImport, dryRun, "", nil, err)
+			return fmt.Errorf("%s: %w", riskTypesFile, err)
+		}
+		auditImport(ctx, audits, domain.AuditRiskTypeImport, result.DryRun, result.RunID.String(), result, nil)
+		printImport("risk types", result.DryRun, result.Added, result.Updated, result.Deactivated, result.Unchanged, result.Rejected)
+	}
+
+	if productsFile != "" {
+		data, err := os.ReadFile(productsFile)
+		if err != nil {
+			return err
+		}
+		input := product.ImportInput{
+			TriggerSource: "cli:" + filepath.Base(productsFile),
+			DryRun:        dryRun,
+			Force:         force,
+			Format:        fileFormat(productsFile, format),
+			Data:          data,
+		}
+		if err := input.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", productsFile, err)
+		}
+		result, err := products.ImportProducts(ctx, input)
+		if err != nil {
+			auditImport(ctx, audits, domain.AuditProductImport, dryRun, "", nil, err)
+			return fmt.Errorf("%s: %w", productsFile, err)
+		}
+		auditImport(ctx, audits, domain.AuditProductImport, result.DryRun, result.RunID.String(), result, nil)
+		printImport("products", result.DryRun, result.Added, result.Updated, result.Deactivated, result.Unchanged, result.Rejected)
+	}
+	return nil
+}
+
+// auditImport records an import that was not a dry run, with its summary or
+// the error that stopped it.
+func auditImport(ctx context.Context, audits audit.AuditService, action domain.AuditAction, dryRun bool, runID string, result any, err error) {
+	if dryRun {
+		return
+	}
+	record := audit.AuditRecord{
+		Action:  action,
+		Target:  runID,
+		Outcome: domain.AuditSucceeded,
+		Detail:  result,
+	}
+	if err != nil {
+		record.Outcome = domain.AuditFailed
+		record.Detail = map[string]string{"error": err.Error()}
+	}
+	audits.Record(ctx, record)
+}
+
+func exportCatalogs(ctx context.Context, products product.ProductService, riskTypes risk_type.RiskTypeService, productsFile, riskTypesFile, format string) error {
+	if productsFile != "" {
+		format, err := domain.ParseCatalogFileFormat(fileFormat(productsFile, format))
+		if err != nil {
+			return err
+		}
+		data, err := products.ExportProducts(ctx, format)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(productsFile, data, 0o644); err != nil {
+			return err
+		}
+	}
+	if riskTypesFile != "" {
+		format, err := domain.ParseCatalogFileFormat(fileFormat(riskTypesFile, format))
+		if err != nil {
+			return err
+		}
+		data, err := riskTypes.ExportRiskTypes(ctx, format)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(riskTypesFile, data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileFormat returns the format flag if one was given, and otherwise guesses
+// the format from the file extension.
+func fileFormat(path, format string) string {
+	if format != "" {
+		return format
+	}
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return string(domain.CatalogFileCSV)
+	}
+	return string(domain.CatalogFileJSON)
+}
+
+func printImport(catalog string, dryRun bool, added, updated, deactivated, unchanged int, rejected []domain.SyncRejection) {
+	prefix := ""
+	if dryRun {
+		prefix = "dry run: "
+	}
+	fmt.Printf("%s%s: %d added, %d updated, %d deactivated, %d unchanged, %d rejected\n",
+		prefix, catalog, added, updated, deactivated, unchanged, len(rejected))
+	for _, r := range rejected {
+		fmt.Printf("  rejected %s %q: %s\n", r.ExternalID, r.Name, r.Reason)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres"
+	"github.com/godsent-code/midtools/internal/application/product"
+	"github.com/godsent-code/midtools/internal/application/risk_type"
+	"github.com/rs/zerolog/log"
+)
+
+const catalogSyncTrigger = "scheduler"
+
+// startCatalogSync refreshes the product and risk type catalogs from NIC every
+// interval, plus a random delay of up to jitter so replicas started together
+// do not call NIC at the same moment. Only the replica holding lock syncs; the
+// others keep trying in case it goes away. A non-positive interval leaves the
+// refresh off. The returned function stops the loop.
+func startCatalogSync(ctx context.Context, interval, jitter time.Duration, lock *postgres.AdvisoryLock, products product.ProductService, riskTypes risk_type.RiskTypeService) func() {
+	if interval <= 0 {
+		log.Info().Msg("Catalog sync disabled")
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer lock.Release(context.Background())
+
+		wait := randomDelay(jitter)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+			syncCatalogs(ctx, lock, products, riskTypes)
+			wait = interval + randomDelay(jitter)
+		}
+	}()
+
+	log.Info().Dur("interval", interval).Dur("jitter", jitter).Msg("Catalog sync scheduled")
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func syncCatalogs(ctx context.Context, lock *postgres.AdvisoryLock, products product.ProductService, riskTypes risk_type.RiskTypeService) {
+	leader, err := lock.TryAcquire(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Catalog sync could not check leadership")
+		return
+	}
+	if !leader {
+		log.Debug().Msg("Catalog sync skipped, another replica is leader")
+		return
+	}
+
+	productRun, err := products.CreateProducts(ctx, product.SyncInput{
+		TriggerSource:   catalogSyncTrigger,
+		SkipIfUnchanged: true,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Product catalog sync failed")
+	} else {
+		log.Info().
+			Str("run", productRun.RunID.String()).
+			Bool("skipped", productRun.Skipped).
+			Int("added", productRun.Added).
+			Int("updated", productRun.Updated).
+			Int("deactivated", productRun.Deactivated).
+			Msg("Product catalog sync finished")
+	}
+
+	riskRun, err := riskTypes.CreateRiskType(ctx, risk_type.SyncInput{
+		TriggerSource:   catalogSyncTrigger,
+		SkipIfUnchanged: true,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Risk type catalog sync failed")
+	} else {
+		log.Info().
+			Str("run", riskRun.RunID.String()).
+			Bool("skipped", riskRun.Skipped).
+			Int("added", riskRun.Added).
+			Int("updated", riskRun.Updated).
+			Int("deactivated", riskRun.Deactivated).
+			Msg("Risk type catalog sync finished")
+	}
+}
+
+func randomDelay(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return rand.N(max)
+}
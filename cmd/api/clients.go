@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/godsent-code/midtools/configs"
+	"github.com/godsent-code/midtools/internal/adapters/postgres"
+	"github.com/godsent-code/midtools/internal/application/api_client"
+	"github.com/godsent-code/midtools/internal/application/audit"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const clientsUsage = "usage: api clients create -name NAME -roles viewer,issuer,catalog-admin,approver,admin [-tenant ID] | api clients set-roles -id ID -roles ROLES | api clients list"
+
+// runClients implements `api clients`, which manages API clients straight
+// against the database. It is how the first admin key is issued, since the
+// HTTP endpoints for clients need one.
+func runClients(args []string) error {
+	if len(args) == 0 {
+		return errors.New(clientsUsage)
+	}
+
+	fs := flag.NewFlagSet("clients "+args[0], flag.ExitOnError)
+	name := fs.String("name", "", "name of the application the key is for")
+	roles := fs.String("roles", "", "comma separated roles: viewer, issuer, catalog-admin, approver, admin")
+	tenantID := fs.String("tenant", "", "id of the tenant whose NIC account the client uses")
+	clientID := fs.String("id", "", "set-roles only: id of the client whose roles are replaced")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	config, err := configs.LoadConfig("./")
+	if err != nil {
+		return err
+	}
+	conn, err := pgxpool.New(ctx, config.DBSource)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	tenantRepo, err := newTenantRepository(conn, config)
+	if err != nil {
+		return err
+	}
+	service := api_client.NewAPIClientService(postgres.NewAPIClientRepository(conn), tenantRepo)
+	audits := audit.NewAuditService(postgres.NewAuditRepository(conn))
+
+	switch args[0] {
+	case "create":
+		input := api_client.APIClientInput{Name: *name, TenantID: *tenantID}
+		if *roles != "" {
+			input.Roles = strings.Split(*roles, ",")
+		}
+		if err := input.Validate(); err != nil {
+			return err
+		}
+		result, err := service.CreateAPIClient(ctx, input)
+		if err != nil {
+			return err
+		}
+		audits.Record(ctx, audit.AuditRecord{
+			Action:  domain.AuditAPIClientCreate,
+			Target:  result.ID.String(),
+			Outcome: domain.AuditSucceeded,
+			Detail:  result.APIClientOutput,
+		})
+		fmt.Printf("client %s (%s) created with roles %v\n", result.Name, result.ID, result.Roles)
+		fmt.Printf("key: %s\n", result.Key)
+		fmt.Println("The key is not stored and cannot be shown again.")
+		return nil
+	case "set-roles":
+		id, err := uuid.Parse(*clientID)
+		if err != nil {
+			return errors.New("-id must be an api client id")
+		}
+		input := api_client.APIClientUpdateInput{}
+		if *roles != "" {
+			input.Roles = strings.Split(*roles, ",")
+		}
+		if err := input.Validate(); err != nil {
+			return err
+		}
+		result, err := service.UpdateAPIClient(ctx, id, input)
+		record := audit.AuditRecord{
+			Action:  domain.AuditAPIClientSetRoles,
+			Target:  id.String(),
+			Outcome: domain.AuditSucceeded,
+			Detail:  result,
+		}
+		if err != nil {
+			record.Outcome = domain.AuditFailed
+			record.Detail = map[string]string{"error": err.Error()}
+		}
+		audits.Record(ctx, record)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("client %s (%s) now has roles %v\n", result.Name, result.ID, result.Roles)
+		return nil
+	case "list":
+		results, err := service.GetAPIClients(ctx)
+		if err != nil {
+			return err
+		}
+		for _, c := range results {
+			status := "active"
+			if c.RevokedAt != nil {
+				status = "revoked"
+			}
+			tenant := "-"
+			if c.TenantID != nil {
+				tenant = c.TenantID.String()
+			}
+			fmt.Printf("%s\t%s\tmt_%s_...\t%v\t%s\t%s\n", c.ID, c.Name, c.KeyPrefix, c.Roles, tenant, status)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown clients action: %s\n%s", args[0], clientsUsage)
+	}
+}
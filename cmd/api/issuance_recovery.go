@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/adapters/http"
+	"github.com/rs/zerolog/log"
+)
+
+const issuanceRecoveryInterval = 10 * time.Minute
+
+// startIssuanceRecovery executes approved issuance requests left behind by a
+// restart, at startup and then every issuanceRecoveryInterval. Every replica
+// runs it; requests are claimed before they are executed, so each runs once.
+// The returned function stops the loop.
+func startIssuanceRecovery(ctx context.Context, runner *http.IssuanceRunner) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			runner.Recover(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(issuanceRecoveryInterval):
+			}
+		}
+	}()
+
+	log.Info().Dur("interval", issuanceRecoveryInterval).Msg("Issuance recovery scheduled")
+	return func() {
+		cancel()
+		<-done
+	}
+}
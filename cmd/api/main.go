@@ -2,22 +2,47 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	http2 "net/http"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/godsent-code/midtools/configs"
 	"github.com/godsent-code/midtools/internal/adapters/http"
 	"github.com/godsent-code/midtools/internal/adapters/postgres"
+	"github.com/godsent-code/midtools/internal/application/api_client"
+	"github.com/godsent-code/midtools/internal/application/audit"
 	"github.com/godsent-code/midtools/internal/application/brown_card_service"
+	"github.com/godsent-code/midtools/internal/application/fleet"
+	"github.com/godsent-code/midtools/internal/application/fleet_monitor"
+	"github.com/godsent-code/midtools/internal/application/idempotency"
+	"github.com/godsent-code/midtools/internal/application/issuance"
+	"github.com/godsent-code/midtools/internal/application/metering"
+	"github.com/godsent-code/midtools/internal/application/plate_rule"
 	"github.com/godsent-code/midtools/internal/application/policy_verification"
 	"github.com/godsent-code/midtools/internal/application/product"
+	"github.com/godsent-code/midtools/internal/application/quota"
+	"github.com/godsent-code/midtools/internal/application/quote"
 	"github.com/godsent-code/midtools/internal/application/risk_type"
 	"github.com/godsent-code/midtools/internal/application/sticker"
+	"github.com/godsent-code/midtools/internal/application/sticker_registry"
+	"github.com/godsent-code/midtools/internal/application/tenant"
 	"github.com/godsent-code/midtools/internal/application/ussd_check"
+	"github.com/godsent-code/midtools/internal/application/vehicle_profile"
+	"github.com/godsent-code/midtools/pkg/secretbox"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	ctx := context.Background()
 	config, err := configs.LoadConfig("./")
 	if err != nil {
@@ -32,7 +57,6 @@ func main() {
 	brownCardService := brown_card_service.NewBrownCard(brownCardRepo)
 
 	stickerRepo := postgres.NewStickerRepository(config)
-	stickerService := sticker.NewStickerService(stickerRepo)
 
 	ussdRepo := postgres.NewUSSDCheckerRepository(config)
 	ussdService := ussd_check.NewUSSDCheckService(ussdRepo)
@@ -40,13 +64,75 @@ func main() {
 	policyVerificationRepo := postgres.NewPolicyVerificationRepository(config)
 	policyVerificationService := policy_verification.NewPolicyVerificationService(policyVerificationRepo)
 
+	syncRunRepo := postgres.NewSyncRunRepository(conn)
+
 	productRepo := postgres.NewProductRepository(conn, config)
-	productService := product.NewProductService(productRepo)
+	productService := product.NewProductService(productRepo, syncRunRepo)
 
 	riskRepo := postgres.NewRiskTypeRepository(conn, config)
-	riskService := risk_type.NewRiskTypeService(riskRepo)
+	riskService := risk_type.NewRiskTypeService(riskRepo, syncRunRepo)
+
+	plateRuleRepo := postgres.NewPlateRiskRuleRepository(conn)
+	plateRuleService := plate_rule.NewPlateRuleService(plateRuleRepo, riskRepo, productRepo)
+
+	stickerService := sticker.NewStickerService(stickerRepo, plateRuleRepo, riskRepo)
+
+	stickerRegistryRepo := postgres.NewStickerRegistryRepository(conn)
+	vehicleProfileService := vehicle_profile.NewVehicleProfileService(policyVerificationRepo, ussdRepo, stickerRegistryRepo)
+
+	tenantRepo, err := newTenantRepository(conn, config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	tenantService := tenant.NewTenantService(tenantRepo)
+
+	fleetRepo := postgres.NewFleetRepository(conn)
+	fleetService := fleet.NewFleetService(fleetRepo)
+
+	fleetMonitorRepo := postgres.NewFleetMonitorRepository(conn)
+	fleetMonitorService := fleet_monitor.NewFleetMonitorService(fleetRepo, tenantRepo, policyVerificationRepo, fleetMonitorRepo, config.FleetMonitorExpiryDays)
+
+	quoteRepo := postgres.NewQuoteRepository(conn)
+	quoteService := quote.NewQuoteService(quoteRepo, productRepo, riskRepo, plateRuleRepo)
+
+	apiClientRepo := postgres.NewAPIClientRepository(conn)
+	apiClientService := api_client.NewAPIClientService(apiClientRepo, tenantRepo)
+
+	quotaRepo := postgres.NewQuotaRepository(conn)
+	quotaService := quota.NewQuotaService(quotaRepo)
+	usageRepo := postgres.NewUsageRepository(conn)
+	meteringService := metering.NewMeteringService(usageRepo)
+	auditRepo := postgres.NewAuditRepository(conn)
+	auditService := audit.NewAuditService(auditRepo)
+	idempotencyRepo := postgres.NewIdempotencyRepository(conn)
+	idempotencyService := idempotency.NewIdempotencyService(idempotencyRepo)
+	issuanceRepo := postgres.NewIssuanceRepository(conn)
+	issuanceService := issuance.NewIssuanceService(issuanceRepo, riskRepo, usageRepo, apiClientRepo, tenantRepo, config.BulkApprovalThreshold)
+	stickerRegistryService := sticker_registry.NewStickerRegistryService(stickerRegistryRepo)
+	issuanceRunner := http.NewIssuanceRunner(issuanceService, stickerService, brownCardService, meteringService, auditService, stickerRegistryService)
+	stopIssuanceRecovery := startIssuanceRecovery(ctx, issuanceRunner)
+	defer stopIssuanceRecovery()
+
+	fleetMonitorLock := postgres.NewAdvisoryLock(conn, "midtools:fleet-monitor")
+	stopFleetMonitor, err := startFleetMonitor(ctx, config.FleetMonitorSchedule, fleetMonitorLock, fleetMonitorService)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer stopFleetMonitor()
 
-	router := http.NewRouter(brownCardService, stickerService, ussdService, policyVerificationService, productService, riskService)
+	syncInterval, err := parseDuration(config.CatalogSyncInterval)
+	if err != nil {
+		log.Fatal(fmt.Errorf("CATALOG_SYNC_INTERVAL: %w", err))
+	}
+	syncJitter, err := parseDuration(config.CatalogSyncJitter)
+	if err != nil {
+		log.Fatal(fmt.Errorf("CATALOG_SYNC_JITTER: %w", err))
+	}
+	catalogSyncLock := postgres.NewAdvisoryLock(conn, "midtools:catalog-sync")
+	stopCatalogSync := startCatalogSync(ctx, syncInterval, syncJitter, catalogSyncLock, productService, riskService)
+	defer stopCatalogSync()
+
+	router := http.NewRouter(brownCardService, stickerService, ussdService, policyVerificationService, productService, riskService, vehicleProfileService, fleetService, fleetMonitorService, quoteService, plateRuleService, apiClientService, tenantService, quotaService, meteringService, auditService, idempotencyService, issuanceService, stickerRegistryService, issuanceRunner)
 
 	err = http2.ListenAndServe(":8000", router)
 	if err != nil {
@@ -54,3 +140,40 @@ func main() {
 		return
 	}
 }
+
+func parseDuration(value string) (time.Duration, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(strings.TrimSpace(value))
+}
+
+// newTenantRepository builds the tenant store. TENANT_SECRET_KEY is optional
+// until tenants are used, so a missing key is not an error here.
+func newTenantRepository(conn *pgxpool.Pool, config configs.Config) (*postgres.TenantRepository, error) {
+	if strings.TrimSpace(config.TenantSecretKey) == "" {
+		return postgres.NewTenantRepository(conn, nil), nil
+	}
+	box, err := secretbox.New(config.TenantSecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("TENANT_SECRET_KEY: %w", err)
+	}
+	return postgres.NewTenantRepository(conn, box), nil
+}
+
+func runCommand(name string, args []string) error {
+	switch name {
+	case "plates":
+		return runPlates(args)
+	case "catalog":
+		return runCatalog(args)
+	case "clients":
+		return runClients(args)
+	case "tenants":
+		return runTenants(args)
+	case "audit":
+		return runAudit(args)
+	default:
+		return fmt.Errorf("unknown command: %s", name)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/godsent-code/midtools/pkg/plategen"
+)
+
+// runPlates implements `api plates`, which prints a seeded list of synthetic
+// Ghana plates for load tests and for seeding the fake NIC.
+func runPlates(args []string) error {
+	fs := flag.NewFlagSet("plates", flag.ExitOnError)
+	count := fs.Int("n", 100, "number of plates to generate")
+	seed := fs.Uint64("seed", 1, "random seed; the same seed yields the same plates")
+	invalid := fs.Float64("invalid", 0, "fraction of plates that should fail validation (0-1)")
+	kinds := fs.String("kinds", "", "comma separated plate kinds (old,new,dv,motorcycle,special,personalised); defaults to all")
+	format := fs.String("format", "text", "output format: text, csv or json")
+	out := fs.String("o", "", "output file; defaults to stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	selected, err := plategen.ParseKinds(*kinds)
+	if err != nil {
+		return err
+	}
+
+	plates, err := plategen.Generate(plategen.Options{
+		Count:       *count,
+		Seed:        *seed,
+		InvalidRate: *invalid,
+		Kinds:       selected,
+	})
+	if err != nil {
+		return err
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return plategen.Write(w, plates, plategen.Format(*format))
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres"
+	"github.com/godsent-code/midtools/internal/application/fleet_monitor"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog/log"
+)
+
+// startFleetMonitor schedules fleet compliance checks. An empty schedule
+// leaves monitoring off; a run that is still going when the next one is due
+// is skipped rather than overlapped. Only the replica holding lock checks the
+// fleets, so alerts are not raised and NIC is not called once per replica.
+// The returned function stops the schedule, waiting for a running check.
+func startFleetMonitor(ctx context.Context, schedule string, lock *postgres.AdvisoryLock, service fleet_monitor.FleetMonitorService) (func(), error) {
+	if strings.TrimSpace(schedule) == "" {
+		log.Info().Msg("Fleet monitor disabled")
+		return func() {}, nil
+	}
+
+	c := cron.New(
+		cron.WithLocation(pkg.Accra),
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)),
+	)
+	_, err := c.AddFunc(schedule, func() {
+		leader, err := lock.TryAcquire(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("Fleet monitor could not check leadership")
+			return
+		}
+		if !leader {
+			log.Debug().Msg("Fleet monitor run skipped, another replica is leader")
+			return
+		}
+		log.Info().Msg("Fleet monitor run started")
+		if err := service.RunAll(ctx); err != nil {
+			log.Error().Err(err).Msg("Fleet monitor run finished with errors")
+			return
+		}
+		log.Info().Msg("Fleet monitor run finished")
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.Start()
+	log.Info().Str("schedule", schedule).Msg("Fleet monitor scheduled")
+	return func() {
+		<-c.Stop().Done()
+		lock.Release(context.Background())
+	}, nil
+}
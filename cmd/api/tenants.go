@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/godsent-code/midtools/configs"
+	"github.com/godsent-code/midtools/internal/adapters/postgres"
+	"github.com/godsent-code/midtools/internal/application/audit"
+	"github.com/godsent-code/midtools/internal/application/tenant"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const tenantsUsage = "usage: api tenants create -name NAME -nic-key KEY|- [-environment sandbox|production] [-interval-ms N] [-burst N] [-workers N] | api tenants list"
+
+// runTenants implements `api tenants`, which registers insurers and their NIC
+// accounts straight against the database. Pass -nic-key - to read the key
+// from stdin and keep it out of the shell history.
+func runTenants(args []string) error {
+	if len(args) == 0 {
+		return errors.New(tenantsUsage)
+	}
+
+	fs := flag.NewFlagSet("tenants "+args[0], flag.ExitOnError)
+	name := fs.String("name", "", "name of the insurer")
+	nicKey := fs.String("nic-key", "", "the insurer's NIC API key, or - to read it from stdin")
+	environment := fs.String("environment", "", "NIC environment: sandbox or production (default production)")
+	intervalMs := fs.Int("interval-ms", 0, "milliseconds between NIC calls (default 300)")
+	burst := fs.Int("burst", 0, "NIC calls allowed at once before pacing starts (default 2)")
+	workers := fs.Int("workers", 0, "concurrent NIC calls per request (default 5)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	config, err := configs.LoadConfig("./")
+	if err != nil {
+		return err
+	}
+	conn, err := pgxpool.New(ctx, config.DBSource)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	tenantRepo, err := newTenantRepository(conn, config)
+	if err != nil {
+		return err
+	}
+	service := tenant.NewTenantService(tenantRepo)
+	audits := audit.NewAuditService(postgres.NewAuditRepository(conn))
+
+	switch args[0] {
+	case "create":
+		key := *nicKey
+		if key == "-" {
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
+				return fmt.Errorf("reading NIC key from stdin: %w", err)
+			}
+			key = strings.TrimSpace(line)
+		}
+		input := tenant.TenantInput{
+			Name:              *name,
+			NICEnvironment:    *environment,
+			NICAPIKey:         key,
+			RequestIntervalMs: *intervalMs,
+			Burst:             *burst,
+			Workers:           *workers,
+		}
+		if err := input.Validate(); err != nil {
+			return err
+		}
+		result, err := service.CreateTenant(ctx, input)
+		if err != nil {
+			return err
+		}
+		audits.Record(ctx, audit.AuditRecord{
+			Action:  domain.AuditTenantCreate,
+			Target:  result.ID.String(),
+			Outcome: domain.AuditSucceeded,
+			Detail:  result,
+		})
+		fmt.Printf("tenant %s (%s) created on the NIC %s environment\n", result.Name, result.ID, result.NICEnvironment)
+		return nil
+	case "list":
+		results, err := service.GetTenants(ctx)
+		if err != nil {
+			return err
+		}
+		for _, t := range results {
+			fmt.Printf("%s\t%s\t%s\t%dms/%d burst/%d workers\n", t.ID, t.Name, t.NICEnvironment,
+				t.NICRateLimit.RequestIntervalMs, t.NICRateLimit.Burst, t.NICRateLimit.Workers)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown tenants action: %s\n%s", args[0], tenantsUsage)
+	}
+}
@@ -7,6 +7,18 @@ type Config struct {
 	ApiKey      string `mapstructure:"API_KEY"`
 	ApiEndPoint string `mapstructure:"API_ENDPOINT"`
 	DBSource    string `mapstructure:"DB_SOURCE"`
+
+	NICSandboxEndPoint    string `mapstructure:"NIC_SANDBOX_ENDPOINT"`
+	NICProductionEndPoint string `mapstructure:"NIC_PRODUCTION_ENDPOINT"`
+	TenantSecretKey       string `mapstructure:"TENANT_SECRET_KEY"`
+
+	FleetMonitorSchedule   string `mapstructure:"FLEET_MONITOR_SCHEDULE"`
+	FleetMonitorExpiryDays int    `mapstructure:"FLEET_MONITOR_EXPIRY_DAYS"`
+
+	CatalogSyncInterval string `mapstructure:"CATALOG_SYNC_INTERVAL"`
+	CatalogSyncJitter   string `mapstructure:"CATALOG_SYNC_JITTER"`
+
+	BulkApprovalThreshold int `mapstructure:"BULK_APPROVAL_THRESHOLD"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
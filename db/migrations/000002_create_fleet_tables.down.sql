@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS fleet_vehicles;
+DROP TABLE IF EXISTS fleets;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS fleets(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    name VARCHAR NOT NULL UNIQUE ,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS fleet_vehicles(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    fleet_id UUID NOT NULL REFERENCES fleets(id) ON DELETE CASCADE ,
+    plate VARCHAR NOT NULL ,
+    labels TEXT[] NOT NULL DEFAULT '{}' ,
+    owner_reference VARCHAR,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (fleet_id, plate)
+);
+
+CREATE INDEX IF NOT EXISTS fleet_vehicles_plate_idx ON fleet_vehicles(plate);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS fleet_alerts;
+DROP TABLE IF EXISTS fleet_vehicle_checks;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS fleet_vehicle_checks(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    fleet_id UUID NOT NULL REFERENCES fleets(id) ON DELETE CASCADE ,
+    plate VARCHAR NOT NULL ,
+    success BOOLEAN NOT NULL ,
+    product_name VARCHAR NOT NULL DEFAULT '' ,
+    start_date TIMESTAMPTZ,
+    end_date TIMESTAMPTZ,
+    insurance_status VARCHAR NOT NULL ,
+    message TEXT NOT NULL DEFAULT '' ,
+    checked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS fleet_vehicle_checks_latest_idx
+    ON fleet_vehicle_checks(fleet_id, plate, checked_at DESC);
+
+CREATE TABLE IF NOT EXISTS fleet_alerts(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    fleet_id UUID NOT NULL REFERENCES fleets(id) ON DELETE CASCADE ,
+    plate VARCHAR NOT NULL ,
+    alert_type VARCHAR NOT NULL ,
+    message TEXT NOT NULL ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ
+);
+
+-- At most one open alert of each type per vehicle; re-raising refreshes it.
+CREATE UNIQUE INDEX IF NOT EXISTS fleet_alerts_open_idx
+    ON fleet_alerts(fleet_id, plate, alert_type) WHERE resolved_at IS NULL;
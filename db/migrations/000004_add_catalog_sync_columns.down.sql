@@ -0,0 +1,7 @@
+ALTER TABLE risk_types
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS active;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS active;
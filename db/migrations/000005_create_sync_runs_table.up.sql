@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS sync_runs(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    catalog VARCHAR NOT NULL ,
+    trigger_source VARCHAR NOT NULL ,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE ,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ,
+    outcome VARCHAR NOT NULL DEFAULT 'running' ,
+    error TEXT,
+    upstream_count INT NOT NULL DEFAULT 0 ,
+    added INT NOT NULL DEFAULT 0 ,
+    updated INT NOT NULL DEFAULT 0 ,
+    deactivated INT NOT NULL DEFAULT 0 ,
+    unchanged INT NOT NULL DEFAULT 0 ,
+    changes JSONB NOT NULL DEFAULT '[]' ,
+    rejected JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX IF NOT EXISTS sync_runs_catalog_started_idx ON sync_runs(catalog, started_at DESC);
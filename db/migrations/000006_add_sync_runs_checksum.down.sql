@@ -0,0 +1 @@
+ALTER TABLE sync_runs DROP COLUMN IF EXISTS payload_checksum;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS risk_type_history;
+DROP TABLE IF EXISTS product_history;
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS product_history(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(product_id) ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    product_code VARCHAR NOT NULL,
+    description TEXT,
+    active BOOLEAN NOT NULL,
+    sync_run_id UUID REFERENCES sync_runs(id) ON DELETE SET NULL,
+    valid_from TIMESTAMPTZ NOT NULL,
+    valid_to TIMESTAMPTZ,
+    CHECK (valid_to IS NULL OR valid_to >= valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS product_history_product_id_idx ON product_history(product_id, valid_from);
+CREATE UNIQUE INDEX IF NOT EXISTS product_history_current_idx ON product_history(product_id) WHERE valid_to IS NULL;
+
+CREATE TABLE IF NOT EXISTS risk_type_history(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    risk_type_id INT NOT NULL REFERENCES risk_types(risk_type_id) ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    risk_category VARCHAR NOT NULL,
+    risk_type_code VARCHAR NOT NULL,
+    description TEXT,
+    active BOOLEAN NOT NULL,
+    sync_run_id UUID REFERENCES sync_runs(id) ON DELETE SET NULL,
+    valid_from TIMESTAMPTZ NOT NULL,
+    valid_to TIMESTAMPTZ,
+    CHECK (valid_to IS NULL OR valid_to >= valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS risk_type_history_risk_type_id_idx ON risk_type_history(risk_type_id, valid_from);
+CREATE UNIQUE INDEX IF NOT EXISTS risk_type_history_current_idx ON risk_type_history(risk_type_id) WHERE valid_to IS NULL;
+
+-- Seed each catalog entry's current state as its first version.
+INSERT INTO product_history(product_id, name, product_code, description, active, valid_from)
+SELECT product_id, name, product_code, description, active, COALESCE(updated_at, created_at, NOW())
+FROM products;
+
+INSERT INTO risk_type_history(risk_type_id, name, risk_category, risk_type_code, description, active, valid_from)
+SELECT risk_type_id, name, risk_category, risk_type_code, description, active, COALESCE(updated_at, created_at, NOW())
+FROM risk_types;
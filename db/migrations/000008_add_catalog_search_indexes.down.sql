@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS risk_types_description_trgm_idx;
+DROP INDEX IF EXISTS risk_types_name_trgm_idx;
+DROP INDEX IF EXISTS products_description_trgm_idx;
+DROP INDEX IF EXISTS products_name_trgm_idx;
@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS products_name_trgm_idx ON products USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS products_description_trgm_idx ON products USING gin (description gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS risk_types_name_trgm_idx ON risk_types USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS risk_types_description_trgm_idx ON risk_types USING gin (description gin_trgm_ops);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS product_risk_types;
+ALTER TABLE risk_types DROP COLUMN IF EXISTS risk_category_id;
+DROP TABLE IF EXISTS risk_categories;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS risk_categories(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    name VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS risk_categories_name_idx ON risk_categories(lower(name));
+
+ALTER TABLE risk_types
+    ADD COLUMN IF NOT EXISTS risk_category_id UUID REFERENCES risk_categories(id) ON DELETE SET NULL;
+
+INSERT INTO risk_categories(name)
+SELECT DISTINCT ON (lower(btrim(risk_category))) btrim(risk_category)
+FROM risk_types
+WHERE btrim(risk_category) <> ''
+ON CONFLICT DO NOTHING;
+
+UPDATE risk_types r SET risk_category_id = c.id
+FROM risk_categories c
+WHERE lower(c.name) = lower(btrim(r.risk_category));
+
+-- source is 'nic' for mappings imported with the product catalog and 'admin'
+-- for ones maintained by hand. Syncs only replace their own rows.
+CREATE TABLE IF NOT EXISTS product_risk_types(
+    product_id INT NOT NULL REFERENCES products(product_id) ON DELETE CASCADE,
+    risk_type_id INT NOT NULL REFERENCES risk_types(risk_type_id) ON DELETE CASCADE,
+    source VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (product_id, risk_type_id)
+);
+
+CREATE INDEX IF NOT EXISTS product_risk_types_risk_type_id_idx ON product_risk_types(risk_type_id);
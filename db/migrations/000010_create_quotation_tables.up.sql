@@ -0,0 +1,37 @@
+-- Money is stored in pesewas; rates and loadings are percentages.
+CREATE TABLE IF NOT EXISTS rate_tables(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(product_id) ON DELETE CASCADE,
+    risk_type_id INT NOT NULL REFERENCES risk_types(risk_type_id) ON DELETE CASCADE,
+    currency VARCHAR NOT NULL DEFAULT 'GHS',
+    base_rate DOUBLE PRECISION NOT NULL,
+    sum_insured_bands JSONB NOT NULL DEFAULT '[]',
+    vehicle_class_loadings JSONB NOT NULL DEFAULT '[]',
+    minimum_premium BIGINT NOT NULL DEFAULT 0,
+    levies JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (product_id, risk_type_id)
+);
+
+CREATE TABLE IF NOT EXISTS quotes(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    plate VARCHAR NOT NULL,
+    product_id INT NOT NULL REFERENCES products(product_id),
+    risk_type_id INT NOT NULL REFERENCES risk_types(risk_type_id),
+    rate_table_id UUID REFERENCES rate_tables(id) ON DELETE SET NULL,
+    vehicle_class VARCHAR NOT NULL DEFAULT '',
+    sum_insured BIGINT NOT NULL,
+    cover_start TIMESTAMPTZ NOT NULL,
+    cover_end TIMESTAMPTZ NOT NULL,
+    cover_days INT NOT NULL,
+    currency VARCHAR NOT NULL,
+    rate DOUBLE PRECISION NOT NULL,
+    lines JSONB NOT NULL,
+    premium BIGINT NOT NULL,
+    levies BIGINT NOT NULL,
+    total BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS quotes_plate_idx ON quotes(plate);
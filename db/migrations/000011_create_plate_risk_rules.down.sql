@@ -0,0 +1,2 @@
+ALTER TABLE quotes DROP COLUMN IF EXISTS warnings;
+DROP TABLE IF EXISTS plate_risk_rules;
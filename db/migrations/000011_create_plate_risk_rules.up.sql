@@ -0,0 +1,14 @@
+-- Rules restricting which risk categories a kind of plate may be insured
+-- under. code narrows a rule to one region or special code; '' applies to
+-- every plate of the kind.
+CREATE TABLE IF NOT EXISTS plate_risk_rules(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    plate_kind VARCHAR NOT NULL,
+    code VARCHAR NOT NULL DEFAULT '',
+    risk_category_id UUID NOT NULL REFERENCES risk_categories(id) ON DELETE CASCADE,
+    default_risk_type_id INT REFERENCES risk_types(risk_type_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (plate_kind, code, risk_category_id)
+);
+
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS warnings JSONB NOT NULL DEFAULT '[]';
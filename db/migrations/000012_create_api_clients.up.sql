@@ -0,0 +1,16 @@
+-- Applications allowed to call the API. Only a SHA-256 hash of each client's
+-- key is kept; key_prefix is the non-secret part of the key used to find it.
+CREATE TABLE IF NOT EXISTS api_clients(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    name VARCHAR NOT NULL,
+    key_prefix VARCHAR NOT NULL UNIQUE,
+    key_hash VARCHAR NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ,
+    rotated_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+);
+
+-- A name can be reused once the client holding it has been revoked.
+CREATE UNIQUE INDEX IF NOT EXISTS api_clients_active_name_idx ON api_clients(name) WHERE revoked_at IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS access_denials;
+ALTER TABLE api_clients RENAME COLUMN roles TO scopes;
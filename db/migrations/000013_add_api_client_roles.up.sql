@@ -0,0 +1,18 @@
+-- Clients now hold roles rather than free-form scopes. Scopes that are not
+-- roles are dropped; admin keeps its meaning.
+ALTER TABLE api_clients RENAME COLUMN scopes TO roles;
+UPDATE api_clients
+SET roles = ARRAY(SELECT r FROM unnest(roles) AS r WHERE r IN ('viewer', 'issuer', 'catalog-admin', 'admin'));
+
+-- Requests refused because the client lacked the role the route needs.
+CREATE TABLE IF NOT EXISTS access_denials(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    client_id UUID NOT NULL REFERENCES api_clients(id) ON DELETE CASCADE,
+    method VARCHAR NOT NULL,
+    path VARCHAR NOT NULL,
+    required_role VARCHAR NOT NULL,
+    roles TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS access_denials_client_created_idx ON access_denials(client_id, created_at DESC);
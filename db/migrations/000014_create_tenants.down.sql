@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS api_clients_tenant_idx;
+ALTER TABLE api_clients DROP COLUMN IF EXISTS tenant_id;
+DROP TABLE IF EXISTS tenants;
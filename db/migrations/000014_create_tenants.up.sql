@@ -0,0 +1,17 @@
+-- Insurance companies served by the API. Each has its own NIC account: the
+-- API key is stored encrypted with TENANT_SECRET_KEY, never in plain text.
+CREATE TABLE IF NOT EXISTS tenants(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    name VARCHAR NOT NULL UNIQUE,
+    nic_environment VARCHAR NOT NULL DEFAULT 'production' CHECK (nic_environment IN ('sandbox', 'production')),
+    nic_api_key_encrypted BYTEA NOT NULL,
+    nic_request_interval_ms INTEGER NOT NULL DEFAULT 300 CHECK (nic_request_interval_ms > 0),
+    nic_burst INTEGER NOT NULL DEFAULT 2 CHECK (nic_burst > 0),
+    nic_workers INTEGER NOT NULL DEFAULT 5 CHECK (nic_workers > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Clients without a tenant keep using the NIC account from the config.
+ALTER TABLE api_clients ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id);
+CREATE INDEX IF NOT EXISTS api_clients_tenant_idx ON api_clients(tenant_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS api_client_quota_usage;
+ALTER TABLE api_clients
+    DROP COLUMN IF EXISTS requests_per_minute,
+    DROP COLUMN IF EXISTS plates_per_day,
+    DROP COLUMN IF EXISTS max_batch_size;
@@ -0,0 +1,15 @@
+-- Per-client limits on our own API. Zero means unlimited.
+ALTER TABLE api_clients
+    ADD COLUMN IF NOT EXISTS requests_per_minute INTEGER NOT NULL DEFAULT 120 CHECK (requests_per_minute >= 0),
+    ADD COLUMN IF NOT EXISTS plates_per_day INTEGER NOT NULL DEFAULT 10000 CHECK (plates_per_day >= 0),
+    ADD COLUMN IF NOT EXISTS max_batch_size INTEGER NOT NULL DEFAULT 500 CHECK (max_batch_size >= 0);
+
+-- What each client has used of a quota in its current window. There is one
+-- row per client and quota; it starts over when a new window begins.
+CREATE TABLE IF NOT EXISTS api_client_quota_usage(
+    client_id UUID NOT NULL REFERENCES api_clients(id) ON DELETE CASCADE,
+    quota VARCHAR NOT NULL,
+    window_start TIMESTAMPTZ NOT NULL,
+    used INTEGER NOT NULL,
+    PRIMARY KEY (client_id, quota)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS service_prices;
+DROP TABLE IF EXISTS usage_records;
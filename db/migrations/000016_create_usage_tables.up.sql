@@ -0,0 +1,28 @@
+-- One row per metered request. amount is priced from service_prices when the
+-- row is written, so price changes never alter past statements.
+CREATE TABLE IF NOT EXISTS usage_records(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    client_id UUID NOT NULL REFERENCES api_clients(id),
+    service VARCHAR NOT NULL,
+    plates INTEGER NOT NULL,
+    nic_calls INTEGER NOT NULL,
+    issued INTEGER NOT NULL,
+    amount BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS usage_records_created_client_idx ON usage_records(created_at, client_id);
+
+-- Unit prices in pesewas per plate submitted, NIC call made and document
+-- issued.
+CREATE TABLE IF NOT EXISTS service_prices(
+    service VARCHAR PRIMARY KEY,
+    plate_price BIGINT NOT NULL DEFAULT 0 CHECK (plate_price >= 0),
+    nic_call_price BIGINT NOT NULL DEFAULT 0 CHECK (nic_call_price >= 0),
+    issued_price BIGINT NOT NULL DEFAULT 0 CHECK (issued_price >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO service_prices(service)
+VALUES ('brown_card'), ('sticker'), ('ussd_check'), ('policy_verification'), ('vehicle_profile'), ('fleet_check')
+ON CONFLICT (service) DO NOTHING;
@@ -0,0 +1,36 @@
+-- Append-only record of issuances and admin actions. Every entry carries the
+-- hash of the one before it, so editing or removing an entry breaks the
+-- chain from that point on; see domain.AuditEntry.ComputeHash. seq has no
+-- gaps: entries are numbered by the writer under an advisory lock.
+CREATE TABLE IF NOT EXISTS audit_log(
+    seq BIGINT PRIMARY KEY,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    client_id UUID,
+    actor VARCHAR NOT NULL,
+    action VARCHAR NOT NULL,
+    target VARCHAR NOT NULL DEFAULT '',
+    plates TEXT[] NOT NULL DEFAULT '{}',
+    outcome VARCHAR NOT NULL,
+    -- JSON rather than JSONB so the text that was hashed is kept verbatim.
+    detail JSON NOT NULL,
+    prev_hash VARCHAR NOT NULL,
+    hash VARCHAR NOT NULL UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_occurred_at_idx ON audit_log(occurred_at);
+CREATE INDEX IF NOT EXISTS audit_log_client_idx ON audit_log(client_id, seq);
+CREATE INDEX IF NOT EXISTS audit_log_plates_idx ON audit_log USING GIN(plates);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update_delete
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,17 @@
+-- Results of sticker and brown card requests sent with an Idempotency-Key,
+-- kept so retries are answered without calling NIC again. A key is pending
+-- while its first request runs; response holds what was sent back once it
+-- completes.
+CREATE TABLE IF NOT EXISTS idempotency_keys(
+    client_id UUID NOT NULL REFERENCES api_clients(id),
+    endpoint VARCHAR NOT NULL,
+    key VARCHAR NOT NULL,
+    request_hash VARCHAR NOT NULL,
+    status_code INTEGER,
+    response JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ,
+    PRIMARY KEY (client_id, endpoint, key)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_created_at_idx ON idempotency_keys(created_at);
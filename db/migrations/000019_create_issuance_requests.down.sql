@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS issuance_request_events;
+DROP TABLE IF EXISTS issuance_requests;
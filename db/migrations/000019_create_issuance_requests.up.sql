@@ -0,0 +1,38 @@
+-- Bulk sticker and brown card batches awaiting a second pair of eyes. A maker
+-- submits a batch, an approver from the same tenant approves or rejects it,
+-- and only approved batches are sent to NIC.
+CREATE TABLE IF NOT EXISTS issuance_requests(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    kind VARCHAR NOT NULL CHECK (kind IN ('sticker', 'brown_card')),
+    status VARCHAR NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'rejected', 'approved', 'executed', 'failed')),
+    maker_id UUID NOT NULL REFERENCES api_clients(id),
+    tenant_id UUID REFERENCES tenants(id),
+    plates TEXT[] NOT NULL,
+    risk_type_id INTEGER,
+    note VARCHAR NOT NULL DEFAULT '',
+    estimated_amount BIGINT NOT NULL,
+    approver_id UUID REFERENCES api_clients(id),
+    decision_note VARCHAR NOT NULL DEFAULT '',
+    issued INTEGER NOT NULL DEFAULT 0,
+    results JSONB,
+    error VARCHAR NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMPTZ,
+    executed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS issuance_requests_status_idx ON issuance_requests(status, created_at);
+
+-- Every state a request has been moved into, by whom.
+CREATE TABLE IF NOT EXISTS issuance_request_events(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    request_id UUID NOT NULL REFERENCES issuance_requests(id),
+    from_status VARCHAR NOT NULL DEFAULT '',
+    to_status VARCHAR NOT NULL,
+    client_id UUID REFERENCES api_clients(id),
+    note VARCHAR NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS issuance_request_events_request_idx ON issuance_request_events(request_id, created_at);
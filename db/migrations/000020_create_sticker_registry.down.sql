@@ -0,0 +1,2 @@
+ALTER TABLE issuance_requests DROP COLUMN IF EXISTS branch;
+DROP TABLE IF EXISTS sticker_registry;
@@ -0,0 +1,24 @@
+-- Every sticker NIC has generated through the API, for tracing which
+-- branch issued a sticker number and reconciling numbers against plates.
+-- Plates are kept as submitted and in canonical form for matching.
+CREATE TABLE IF NOT EXISTS sticker_registry(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    plate VARCHAR NOT NULL,
+    canonical_plate VARCHAR NOT NULL,
+    sticker_number VARCHAR NOT NULL,
+    sticker_link VARCHAR NOT NULL DEFAULT '',
+    client_id UUID REFERENCES api_clients(id),
+    tenant_id UUID REFERENCES tenants(id),
+    branch VARCHAR NOT NULL DEFAULT '',
+    status VARCHAR NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'voided', 'lost')),
+    status_note VARCHAR NOT NULL DEFAULT '',
+    issued_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    status_changed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS sticker_registry_plate_idx ON sticker_registry(canonical_plate);
+CREATE INDEX IF NOT EXISTS sticker_registry_number_idx ON sticker_registry(sticker_number);
+CREATE INDEX IF NOT EXISTS sticker_registry_issued_at_idx ON sticker_registry(issued_at);
+
+-- The branch a bulk request was made from, recorded with its stickers.
+ALTER TABLE issuance_requests ADD COLUMN IF NOT EXISTS branch VARCHAR NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+UPDATE issuance_requests SET status = 'failed' WHERE status = 'executing';
+ALTER TABLE issuance_requests DROP COLUMN IF EXISTS started_at;
+ALTER TABLE issuance_requests DROP CONSTRAINT IF EXISTS issuance_requests_status_check;
+ALTER TABLE issuance_requests ADD CONSTRAINT issuance_requests_status_check
+    CHECK (status IN ('pending', 'rejected', 'approved', 'executed', 'failed'));
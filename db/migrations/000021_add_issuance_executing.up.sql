@@ -0,0 +1,7 @@
+-- Approved requests are claimed before they are executed, so a batch runs
+-- once however many replicas pick it up. started_at tells requests whose
+-- execution was interrupted from those still running.
+ALTER TABLE issuance_requests DROP CONSTRAINT IF EXISTS issuance_requests_status_check;
+ALTER TABLE issuance_requests ADD CONSTRAINT issuance_requests_status_check
+    CHECK (status IN ('pending', 'rejected', 'approved', 'executing', 'executed', 'failed'));
+ALTER TABLE issuance_requests ADD COLUMN IF NOT EXISTS started_at TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS fleets_tenant_name_idx;
+ALTER TABLE fleets ADD CONSTRAINT fleets_name_key UNIQUE (name);
+ALTER TABLE fleets DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,6 @@
+-- Fleets belong to the tenant of the client that created them. Names only
+-- need to be unique within a tenant; fleets without one share a namespace.
+ALTER TABLE fleets ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id);
+ALTER TABLE fleets DROP CONSTRAINT IF EXISTS fleets_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS fleets_tenant_name_idx
+    ON fleets(COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'::uuid), name);
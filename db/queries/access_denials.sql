@@ -0,0 +1,12 @@
+-- name: CreateAccessDenial :exec
+INSERT INTO access_denials(client_id, method, path, required_role, roles)
+VALUES (@client_id, @method, @path, @required_role, @roles);
+
+-- name: GetAccessDenials :many
+SELECT d.id, d.client_id, d.method, d.path, d.required_role, d.roles, d.created_at,
+       c.name AS client_name
+FROM access_denials d
+JOIN api_clients c ON c.id = d.client_id
+WHERE sqlc.narg('client_id')::uuid IS NULL OR d.client_id = sqlc.narg('client_id')
+ORDER BY d.created_at DESC
+LIMIT @row_limit;
@@ -0,0 +1,52 @@
+-- name: CreateAPIClient :one
+INSERT INTO api_clients(name, key_prefix, key_hash, roles, tenant_id, requests_per_minute, plates_per_day, max_batch_size)
+VALUES (@name, @key_prefix, @key_hash, @roles, sqlc.narg('tenant_id'), @requests_per_minute, @plates_per_day, @max_batch_size) RETURNING *;
+
+-- name: GetAPIClients :many
+SELECT * FROM api_clients
+ORDER BY created_at;
+
+-- name: GetAPIClient :one
+SELECT * FROM api_clients
+WHERE id = @id;
+
+-- name: GetAPIClientByKeyPrefix :one
+SELECT * FROM api_clients
+WHERE key_prefix = @key_prefix;
+
+-- name: RotateAPIClientKey :one
+UPDATE api_clients
+SET key_prefix = @key_prefix, key_hash = @key_hash, rotated_at = NOW()
+WHERE id = @id AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeAPIClient :one
+UPDATE api_clients
+SET revoked_at = COALESCE(revoked_at, NOW())
+WHERE id = @id
+RETURNING *;
+
+-- name: SetAPIClientTenant :one
+UPDATE api_clients
+SET tenant_id = sqlc.narg('tenant_id')
+WHERE id = @id
+RETURNING *;
+
+-- name: SetAPIClientLimits :one
+UPDATE api_clients
+SET requests_per_minute = @requests_per_minute, plates_per_day = @plates_per_day, max_batch_size = @max_batch_size
+WHERE id = @id
+RETURNING *;
+
+-- name: SetAPIClientRoles :one
+UPDATE api_clients
+SET roles = @roles
+WHERE id = @id
+RETURNING *;
+
+-- name: TouchAPIClient :exec
+-- Keys are checked on every request, so last_used_at is only written about
+-- once a minute per client.
+UPDATE api_clients
+SET last_used_at = NOW()
+WHERE id = @id AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute');
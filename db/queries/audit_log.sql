@@ -0,0 +1,29 @@
+-- name: LockAuditLog :exec
+SELECT pg_advisory_xact_lock(hashtext('audit_log'));
+
+-- name: GetLastAuditEntry :one
+SELECT seq, hash FROM audit_log
+ORDER BY seq DESC
+LIMIT 1;
+
+-- name: CreateAuditEntry :one
+INSERT INTO audit_log(seq, occurred_at, client_id, actor, action, target, plates, outcome, detail, prev_hash, hash)
+VALUES (@seq, @occurred_at, @client_id, @actor, @action, @target, @plates, @outcome, @detail, @prev_hash, @hash)
+RETURNING *;
+
+-- name: GetAuditEntries :many
+SELECT * FROM audit_log
+WHERE (sqlc.narg('client_id')::uuid IS NULL OR client_id = sqlc.narg('client_id'))
+  AND (sqlc.narg('action')::varchar IS NULL OR action = sqlc.narg('action'))
+  AND (sqlc.narg('plate')::text IS NULL OR plates @> ARRAY[sqlc.narg('plate')::text])
+  AND (sqlc.narg('occurred_from')::timestamptz IS NULL OR occurred_at >= sqlc.narg('occurred_from'))
+  AND (sqlc.narg('occurred_to')::timestamptz IS NULL OR occurred_at < sqlc.narg('occurred_to'))
+  AND (sqlc.narg('before_seq')::bigint IS NULL OR seq < sqlc.narg('before_seq'))
+ORDER BY seq DESC
+LIMIT @row_limit;
+
+-- name: GetAuditChain :many
+SELECT * FROM audit_log
+WHERE seq > @after_seq
+ORDER BY seq
+LIMIT @row_limit;
@@ -0,0 +1,44 @@
+-- name: CloseProductVersions :exec
+UPDATE product_history SET valid_to = NOW()
+WHERE product_id = ANY(@product_id::int[]) AND valid_to IS NULL;
+
+-- name: OpenProductVersions :exec
+INSERT INTO product_history(product_id, name, product_code, description, active, sync_run_id, valid_from)
+SELECT product_id, name, product_code, description, active, sqlc.narg('sync_run_id')::uuid, NOW()
+FROM products
+WHERE product_id = ANY(@product_id::int[]);
+
+-- name: GetProductHistory :many
+SELECT * FROM product_history
+WHERE product_id = @product_id
+ORDER BY valid_from;
+
+-- name: GetProductsAsOf :many
+SELECT p.id, h.product_id, h.name, h.product_code, h.description, h.active, p.created_at, h.valid_from
+FROM product_history h
+JOIN products p ON p.product_id = h.product_id
+WHERE h.valid_from <= @as_of AND (h.valid_to IS NULL OR h.valid_to > @as_of)
+ORDER BY h.product_id;
+
+
+-- name: CloseRiskTypeVersions :exec
+UPDATE risk_type_history SET valid_to = NOW()
+WHERE risk_type_id = ANY(@risk_type_id::int[]) AND valid_to IS NULL;
+
+-- name: OpenRiskTypeVersions :exec
+INSERT INTO risk_type_history(risk_type_id, name, risk_category, risk_type_code, description, active, sync_run_id, valid_from)
+SELECT risk_type_id, name, risk_category, risk_type_code, description, active, sqlc.narg('sync_run_id')::uuid, NOW()
+FROM risk_types
+WHERE risk_type_id = ANY(@risk_type_id::int[]);
+
+-- name: GetRiskTypeHistory :many
+SELECT * FROM risk_type_history
+WHERE risk_type_id = @risk_type_id
+ORDER BY valid_from;
+
+-- name: GetRiskTypesAsOf :many
+SELECT r.id, h.risk_type_id, h.name, h.risk_category, h.risk_type_code, h.description, h.active, r.created_at, h.valid_from
+FROM risk_type_history h
+JOIN risk_types r ON r.risk_type_id = h.risk_type_id
+WHERE h.valid_from <= @as_of AND (h.valid_to IS NULL OR h.valid_to > @as_of)
+ORDER BY h.risk_type_id;
@@ -0,0 +1,93 @@
+-- Rows are ordered by a text sort key and the row id so that a page can be
+-- resumed from the last row's (sort_key, id) pair.
+
+-- name: SearchProducts :many
+WITH keyed AS (
+    SELECT p.*,
+           GREATEST(similarity(p.name, COALESCE(sqlc.narg('search')::text, '')),
+                    similarity(COALESCE(p.description, ''), COALESCE(sqlc.narg('search')::text, '')))::real AS score
+    FROM products p
+    WHERE (sqlc.narg('product_code')::text IS NULL OR lower(p.product_code) = lower(sqlc.narg('product_code')::text))
+      AND (sqlc.narg('active')::bool IS NULL OR p.active = sqlc.narg('active')::bool)
+      AND (sqlc.narg('search')::text IS NULL
+           OR p.name % sqlc.narg('search')::text
+           OR p.description % sqlc.narg('search')::text
+           OR p.name ILIKE '%' || sqlc.narg('search')::text || '%')
+), sorted AS (
+    SELECT k.*,
+           (CASE @sort_by::text
+                WHEN 'name' THEN lower(k.name)
+                WHEN 'code' THEN lower(k.product_code)
+                WHEN 'updatedAt' THEN to_char(COALESCE(k.updated_at, k.created_at), 'YYYY-MM-DD"T"HH24:MI:SS.US')
+                WHEN 'relevance' THEN to_char(k.score, 'FM0.000000')
+                ELSE lpad(k.product_id::text, 10, '0')
+            END)::text AS sort_key
+    FROM keyed k
+)
+SELECT s.id, s.product_id, s.name, s.product_code, s.description, s.created_at, s.active, s.updated_at, s.score, s.sort_key
+FROM sorted s
+WHERE sqlc.narg('cursor_key')::text IS NULL
+   OR (@descending::bool AND (s.sort_key, s.id) < (sqlc.narg('cursor_key')::text, sqlc.narg('cursor_id')::uuid))
+   OR (NOT @descending::bool AND (s.sort_key, s.id) > (sqlc.narg('cursor_key')::text, sqlc.narg('cursor_id')::uuid))
+ORDER BY
+    CASE WHEN @descending::bool THEN s.sort_key END DESC,
+    CASE WHEN @descending::bool THEN s.id END DESC,
+    s.sort_key ASC,
+    s.id ASC
+LIMIT sqlc.narg('row_limit')::int;
+
+-- name: CountProducts :one
+SELECT COUNT(*) FROM products p
+WHERE (sqlc.narg('product_code')::text IS NULL OR lower(p.product_code) = lower(sqlc.narg('product_code')::text))
+  AND (sqlc.narg('active')::bool IS NULL OR p.active = sqlc.narg('active')::bool)
+  AND (sqlc.narg('search')::text IS NULL
+       OR p.name % sqlc.narg('search')::text
+       OR p.description % sqlc.narg('search')::text
+       OR p.name ILIKE '%' || sqlc.narg('search')::text || '%');
+
+
+-- name: SearchRiskTypes :many
+WITH keyed AS (
+    SELECT r.*,
+           GREATEST(similarity(r.name, COALESCE(sqlc.narg('search')::text, '')),
+                    similarity(COALESCE(r.description, ''), COALESCE(sqlc.narg('search')::text, '')))::real AS score
+    FROM risk_types r
+    WHERE (sqlc.narg('risk_type_code')::text IS NULL OR lower(r.risk_type_code) = lower(sqlc.narg('risk_type_code')::text))
+      AND (sqlc.narg('risk_category')::text IS NULL OR lower(r.risk_category) = lower(sqlc.narg('risk_category')::text))
+      AND (sqlc.narg('active')::bool IS NULL OR r.active = sqlc.narg('active')::bool)
+      AND (sqlc.narg('search')::text IS NULL
+           OR r.name % sqlc.narg('search')::text
+           OR r.description % sqlc.narg('search')::text
+           OR r.name ILIKE '%' || sqlc.narg('search')::text || '%')
+), sorted AS (
+    SELECT k.*,
+           (CASE @sort_by::text
+                WHEN 'name' THEN lower(k.name)
+                WHEN 'code' THEN lower(k.risk_type_code)
+                WHEN 'updatedAt' THEN to_char(COALESCE(k.updated_at, k.created_at), 'YYYY-MM-DD"T"HH24:MI:SS.US')
+                WHEN 'relevance' THEN to_char(k.score, 'FM0.000000')
+                ELSE lpad(k.risk_type_id::text, 10, '0')
+            END)::text AS sort_key
+    FROM keyed k
+)
+SELECT s.id, s.risk_type_id, s.name, s.risk_category, s.risk_type_code, s.description, s.created_at, s.active, s.updated_at, s.score, s.sort_key
+FROM sorted s
+WHERE sqlc.narg('cursor_key')::text IS NULL
+   OR (@descending::bool AND (s.sort_key, s.id) < (sqlc.narg('cursor_key')::text, sqlc.narg('cursor_id')::uuid))
+   OR (NOT @descending::bool AND (s.sort_key, s.id) > (sqlc.narg('cursor_key')::text, sqlc.narg('cursor_id')::uuid))
+ORDER BY
+    CASE WHEN @descending::bool THEN s.sort_key END DESC,
+    CASE WHEN @descending::bool THEN s.id END DESC,
+    s.sort_key ASC,
+    s.id ASC
+LIMIT sqlc.narg('row_limit')::int;
+
+-- name: CountRiskTypes :one
+SELECT COUNT(*) FROM risk_types r
+WHERE (sqlc.narg('risk_type_code')::text IS NULL OR lower(r.risk_type_code) = lower(sqlc.narg('risk_type_code')::text))
+  AND (sqlc.narg('risk_category')::text IS NULL OR lower(r.risk_category) = lower(sqlc.narg('risk_category')::text))
+  AND (sqlc.narg('active')::bool IS NULL OR r.active = sqlc.narg('active')::bool)
+  AND (sqlc.narg('search')::text IS NULL
+       OR r.name % sqlc.narg('search')::text
+       OR r.description % sqlc.narg('search')::text
+       OR r.name ILIKE '%' || sqlc.narg('search')::text || '%');
@@ -0,0 +1,32 @@
+-- name: CreateFleetVehicleChecks :exec
+INSERT INTO fleet_vehicle_checks(fleet_id, plate, success, product_name, start_date, end_date, insurance_status, message)
+SELECT @fleet_id::uuid, unnest(@plate::text[]), unnest(@success::bool[]), unnest(@product_name::text[]),
+       unnest(@start_date::timestamptz[]), unnest(@end_date::timestamptz[]), unnest(@insurance_status::text[]),
+       unnest(@message::text[]);
+
+-- name: GetLatestFleetVehicleChecks :many
+SELECT DISTINCT ON (plate) * FROM fleet_vehicle_checks
+WHERE fleet_id = @fleet_id
+ORDER BY plate, checked_at DESC;
+
+-- name: OpenFleetAlert :one
+-- Opens an alert, or refreshes the message of the one already open. opened
+-- is false for a refresh: xmax is only zero on a row this statement inserted.
+INSERT INTO fleet_alerts(fleet_id, plate, alert_type, message)
+VALUES (@fleet_id, @plate, @alert_type, @message)
+ON CONFLICT (fleet_id, plate, alert_type) WHERE resolved_at IS NULL
+DO UPDATE SET message = EXCLUDED.message, updated_at = NOW()
+RETURNING (xmax = 0)::boolean AS opened;
+
+-- name: ResolveFleetAlertsByType :exec
+UPDATE fleet_alerts SET resolved_at = NOW(), updated_at = NOW()
+WHERE fleet_id = @fleet_id AND plate = @plate AND alert_type = ANY(@alert_types::text[]) AND resolved_at IS NULL;
+
+-- name: ResolveFleetAlert :execrows
+UPDATE fleet_alerts SET resolved_at = NOW(), updated_at = NOW()
+WHERE id = @id AND fleet_id = @fleet_id AND resolved_at IS NULL;
+
+-- name: GetOpenFleetAlerts :many
+SELECT * FROM fleet_alerts
+WHERE fleet_id = @fleet_id AND resolved_at IS NULL
+ORDER BY created_at DESC;
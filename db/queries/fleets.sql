@@ -0,0 +1,50 @@
+-- name: CreateFleet :one
+INSERT INTO fleets(name, description, tenant_id)
+VALUES (@name, @description, sqlc.narg('tenant_id')) RETURNING *;
+
+-- name: GetFleets :many
+-- all_tenants skips the tenant filter; otherwise only fleets of the given
+-- tenant, or without one when it is null, match. The other fleet queries
+-- filter the same way.
+SELECT * FROM fleets
+WHERE (@all_tenants::boolean OR tenant_id IS NOT DISTINCT FROM sqlc.narg('tenant_id')::uuid)
+ORDER BY name;
+
+-- name: GetFleet :one
+SELECT * FROM fleets
+WHERE id = @id
+  AND (@all_tenants::boolean OR tenant_id IS NOT DISTINCT FROM sqlc.narg('tenant_id')::uuid);
+
+-- name: UpdateFleet :one
+UPDATE fleets SET name = @name, description = @description, updated_at = NOW()
+WHERE id = @id
+  AND (@all_tenants::boolean OR tenant_id IS NOT DISTINCT FROM sqlc.narg('tenant_id')::uuid)
+RETURNING *;
+
+-- name: DeleteFleet :execrows
+DELETE FROM fleets
+WHERE id = @id
+  AND (@all_tenants::boolean OR tenant_id IS NOT DISTINCT FROM sqlc.narg('tenant_id')::uuid);
+
+-- name: UpsertFleetVehicles :execrows
+INSERT INTO fleet_vehicles(fleet_id, plate, labels, owner_reference)
+SELECT f.id, v.plate, string_to_array(v.labels, ','), NULLIF(v.owner_reference, '')
+FROM fleets f, unnest(@plate::text[], @labels::text[], @owner_reference::text[]) AS v(plate, labels, owner_reference)
+WHERE f.id = @fleet_id::uuid
+  AND (@all_tenants::boolean OR f.tenant_id IS NOT DISTINCT FROM sqlc.narg('tenant_id')::uuid)
+ON CONFLICT (fleet_id, plate) DO UPDATE
+    SET labels = EXCLUDED.labels, owner_reference = EXCLUDED.owner_reference, updated_at = NOW();
+
+-- name: GetFleetVehicles :many
+SELECT v.* FROM fleet_vehicles v
+JOIN fleets f ON f.id = v.fleet_id
+WHERE v.fleet_id = @fleet_id
+  AND (@all_tenants::boolean OR f.tenant_id IS NOT DISTINCT FROM sqlc.narg('tenant_id')::uuid)
+ORDER BY v.plate;
+
+-- name: DeleteFleetVehicle :execrows
+DELETE FROM fleet_vehicles v
+USING fleets f
+WHERE f.id = v.fleet_id
+  AND v.fleet_id = @fleet_id AND v.plate = @plate
+  AND (@all_tenants::boolean OR f.tenant_id IS NOT DISTINCT FROM sqlc.narg('tenant_id')::uuid);
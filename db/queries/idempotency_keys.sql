@@ -0,0 +1,28 @@
+-- name: ClaimIdempotencyKey :one
+-- Claims a key for a new request. A key already in use is only taken over
+-- once it has expired, or once its request has been pending so long it must
+-- have died; otherwise no row is returned.
+INSERT INTO idempotency_keys(client_id, endpoint, key, request_hash)
+VALUES (@client_id, @endpoint, @key, @request_hash)
+ON CONFLICT (client_id, endpoint, key) DO UPDATE
+SET request_hash = EXCLUDED.request_hash,
+    status_code = NULL,
+    response = NULL,
+    created_at = NOW(),
+    completed_at = NULL
+WHERE idempotency_keys.created_at < @expires_before
+   OR (idempotency_keys.completed_at IS NULL AND idempotency_keys.created_at < @stale_before)
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE client_id = @client_id AND endpoint = @endpoint AND key = @key;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code = @status_code, response = @response, completed_at = NOW()
+WHERE client_id = @client_id AND endpoint = @endpoint AND key = @key AND completed_at IS NULL;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE client_id = @client_id AND endpoint = @endpoint AND key = @key AND completed_at IS NULL;
@@ -0,0 +1,57 @@
+-- name: CreateIssuanceRequest :one
+INSERT INTO issuance_requests(kind, maker_id, tenant_id, plates, risk_type_id, note, estimated_amount, branch)
+VALUES (@kind, @maker_id, sqlc.narg('tenant_id'), @plates, sqlc.narg('risk_type_id'), @note, @estimated_amount, @branch)
+RETURNING *;
+
+-- name: GetIssuanceRequest :one
+SELECT * FROM issuance_requests
+WHERE id = @id;
+
+-- name: GetIssuanceRequests :many
+-- Lists requests newest first. all_tenants skips the tenant filter; otherwise
+-- only requests of the given tenant, or without one when it is null, match.
+SELECT * FROM issuance_requests
+WHERE (@all_tenants::boolean OR tenant_id IS NOT DISTINCT FROM sqlc.narg('tenant_id')::uuid)
+  AND (sqlc.narg('status')::varchar IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('kind')::varchar IS NULL OR kind = sqlc.narg('kind'))
+ORDER BY created_at DESC
+LIMIT @row_limit;
+
+-- name: DecideIssuanceRequest :one
+-- Moves a pending request to approved or rejected. No row is returned when
+-- the request is no longer pending, so only one decision can win.
+UPDATE issuance_requests
+SET status = @status, approver_id = @approver_id, decision_note = @decision_note, decided_at = NOW()
+WHERE id = @id AND status = 'pending'
+RETURNING *;
+
+-- name: StartIssuanceRequest :one
+-- Claims an approved request for execution. No row is returned when it has
+-- already been claimed, so only one worker runs the batch.
+UPDATE issuance_requests
+SET status = 'executing', started_at = NOW()
+WHERE id = @id AND status = 'approved'
+RETURNING *;
+
+-- name: CompleteIssuanceRequest :one
+UPDATE issuance_requests
+SET status = @status, issued = @issued, results = @results, error = @error, executed_at = NOW()
+WHERE id = @id AND status = 'executing'
+RETURNING *;
+
+-- name: FailInterruptedIssuanceRequests :many
+-- Fails requests that started executing before started_before and never
+-- finished, as the process running them stopped part way.
+UPDATE issuance_requests
+SET status = 'failed', error = @error, executed_at = NOW()
+WHERE status = 'executing' AND started_at < @started_before
+RETURNING id;
+
+-- name: CreateIssuanceRequestEvent :exec
+INSERT INTO issuance_request_events(request_id, from_status, to_status, client_id, note)
+VALUES (@request_id, @from_status, @to_status, sqlc.narg('client_id'), @note);
+
+-- name: GetIssuanceRequestEvents :many
+SELECT from_status, to_status, client_id, note, created_at FROM issuance_request_events
+WHERE request_id = @request_id
+ORDER BY created_at, id;
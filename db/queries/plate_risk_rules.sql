@@ -0,0 +1,25 @@
+-- name: CreatePlateRiskRule :one
+WITH inserted AS (
+    INSERT INTO plate_risk_rules(plate_kind, code, risk_category_id, default_risk_type_id)
+    VALUES (@plate_kind, @code, @risk_category_id, sqlc.narg('default_risk_type_id'))
+    RETURNING *
+)
+SELECT i.id, i.plate_kind, i.code, i.risk_category_id, i.default_risk_type_id, i.created_at,
+       c.name AS risk_category
+FROM inserted i
+JOIN risk_categories c ON c.id = i.risk_category_id;
+
+-- name: GetPlateRiskRules :many
+SELECT p.id, p.plate_kind, p.code, p.risk_category_id, p.default_risk_type_id, p.created_at,
+       c.name AS risk_category
+FROM plate_risk_rules p
+JOIN risk_categories c ON c.id = p.risk_category_id
+ORDER BY p.plate_kind, p.code, c.name;
+
+-- name: DeletePlateRiskRule :execrows
+DELETE FROM plate_risk_rules WHERE id = @id;
+
+-- name: GetActiveRiskTypesInCategories :many
+SELECT * FROM risk_types
+WHERE active AND risk_category_id = ANY(@risk_category_ids::uuid[])
+ORDER BY name;
@@ -1,16 +1,55 @@
--- name: CreateProducts :exec
+-- name: UpsertProducts :exec
 INSERT INTO products(product_id, name, product_code, description)
  SELECT unnest(@product_id::int[]),unnest(@name::text[]),unnest(@product_code::text[]),
-        unnest(@description::text[]) ON CONFLICT DO NOTHING ;
+        unnest(@description::text[])
+ON CONFLICT (product_id) DO UPDATE
+    SET name = EXCLUDED.name, product_code = EXCLUDED.product_code, description = EXCLUDED.description,
+        active = TRUE, updated_at = NOW();
+
+-- name: DeactivateProducts :exec
+UPDATE products SET active = FALSE, updated_at = NOW()
+WHERE product_id = ANY(@product_id::int[]) AND active;
 
 -- name: GetProducts :many
 SELECT * FROM products;
 
 
--- name: CreateRiskType :exec
+-- name: UpsertRiskTypes :exec
 INSERT INTO risk_types(risk_type_id, name, risk_category, risk_type_code, description)
 SELECT unnest(@risk_type_id::int[]),unnest(@name::text[]),unnest(@risk_category::text[]),
-       unnest(@risk_type_code::text[]),unnest(@description::text[]) ON CONFLICT DO NOTHING;
+       unnest(@risk_type_code::text[]),unnest(@description::text[])
+ON CONFLICT (risk_type_id) DO UPDATE
+    SET name = EXCLUDED.name, risk_category = EXCLUDED.risk_category, risk_type_code = EXCLUDED.risk_type_code,
+        description = EXCLUDED.description, active = TRUE, updated_at = NOW();
+
+-- name: DeactivateRiskTypes :exec
+UPDATE risk_types SET active = FALSE, updated_at = NOW()
+WHERE risk_type_id = ANY(@risk_type_id::int[]) AND active;
 
 -- name: GetRiskType :many
-SELECT * FROM risk_types;
\ No newline at end of file
+SELECT * FROM risk_types;
+
+
+-- name: GetProduct :one
+SELECT * FROM products WHERE id = @id;
+
+-- name: GetProductByProductID :one
+SELECT * FROM products WHERE product_id = @product_id;
+
+-- name: GetProductByCode :one
+SELECT * FROM products
+WHERE lower(product_code) = lower(@product_code::text)
+ORDER BY active DESC, product_id
+LIMIT 1;
+
+-- name: GetRiskTypeByID :one
+SELECT * FROM risk_types WHERE id = @id;
+
+-- name: GetRiskTypeByRiskTypeID :one
+SELECT * FROM risk_types WHERE risk_type_id = @risk_type_id;
+
+-- name: GetRiskTypeByCode :one
+SELECT * FROM risk_types
+WHERE lower(risk_type_code) = lower(@risk_type_code::text)
+ORDER BY active DESC, risk_type_id
+LIMIT 1;
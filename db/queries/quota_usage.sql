@@ -0,0 +1,25 @@
+-- name: ConsumeQuota :one
+-- Adds amount to the client's usage in the window starting at window_start,
+-- unless that would go over quota_limit. A new window starts from zero. No
+-- row is returned when the quota is exhausted.
+INSERT INTO api_client_quota_usage(client_id, quota, window_start, used)
+VALUES (@client_id, @quota, @window_start, @amount)
+ON CONFLICT (client_id, quota) DO UPDATE
+SET window_start = EXCLUDED.window_start,
+    used = CASE WHEN api_client_quota_usage.window_start = EXCLUDED.window_start
+                THEN api_client_quota_usage.used + EXCLUDED.used
+                ELSE EXCLUDED.used END
+WHERE api_client_quota_usage.window_start <> EXCLUDED.window_start
+   OR api_client_quota_usage.used + EXCLUDED.used <= @quota_limit::int
+RETURNING used;
+
+-- name: GetQuotaUsage :one
+SELECT used FROM api_client_quota_usage
+WHERE client_id = @client_id AND quota = @quota AND window_start = @window_start;
+
+-- name: RefundQuota :exec
+-- Gives back amount consumed earlier in the window starting at window_start.
+-- Nothing is given back once a new window has started.
+UPDATE api_client_quota_usage
+SET used = GREATEST(used - @amount::int, 0)
+WHERE client_id = @client_id AND quota = @quota AND window_start = @window_start;
@@ -0,0 +1,36 @@
+-- name: CreateRateTable :one
+INSERT INTO rate_tables(product_id, risk_type_id, currency, base_rate, sum_insured_bands,
+                        vehicle_class_loadings, minimum_premium, levies)
+VALUES (@product_id, @risk_type_id, @currency, @base_rate, @sum_insured_bands,
+        @vehicle_class_loadings, @minimum_premium, @levies)
+RETURNING *;
+
+-- name: GetRateTables :many
+SELECT * FROM rate_tables ORDER BY product_id, risk_type_id;
+
+-- name: GetRateTable :one
+SELECT * FROM rate_tables WHERE id = @id;
+
+-- name: GetRateTableFor :one
+SELECT * FROM rate_tables WHERE product_id = @product_id AND risk_type_id = @risk_type_id;
+
+-- name: UpdateRateTable :one
+UPDATE rate_tables
+SET currency = @currency, base_rate = @base_rate, sum_insured_bands = @sum_insured_bands,
+    vehicle_class_loadings = @vehicle_class_loadings, minimum_premium = @minimum_premium,
+    levies = @levies, updated_at = NOW()
+WHERE id = @id
+RETURNING *;
+
+-- name: DeleteRateTable :execrows
+DELETE FROM rate_tables WHERE id = @id;
+
+-- name: CreateQuote :one
+INSERT INTO quotes(plate, product_id, risk_type_id, rate_table_id, vehicle_class, sum_insured,
+                   cover_start, cover_end, cover_days, currency, rate, lines, premium, levies, total, warnings)
+VALUES (@plate, @product_id, @risk_type_id, @rate_table_id, @vehicle_class, @sum_insured,
+        @cover_start, @cover_end, @cover_days, @currency, @rate, @lines, @premium, @levies, @total, @warnings)
+RETURNING *;
+
+-- name: GetQuote :one
+SELECT * FROM quotes WHERE id = @id;
@@ -0,0 +1,54 @@
+-- name: RefreshRiskCategories :exec
+INSERT INTO risk_categories(name)
+SELECT DISTINCT ON (lower(btrim(risk_category))) btrim(risk_category)
+FROM risk_types
+WHERE btrim(risk_category) <> ''
+ON CONFLICT DO NOTHING;
+
+-- name: LinkRiskTypeCategories :exec
+UPDATE risk_types r SET risk_category_id = c.id
+FROM risk_categories c
+WHERE lower(c.name) = lower(btrim(r.risk_category))
+  AND r.risk_category_id IS DISTINCT FROM c.id;
+
+-- name: GetRiskCategories :many
+SELECT c.id, c.name, c.created_at,
+       COUNT(r.id) FILTER (WHERE r.active) AS active_risk_types
+FROM risk_categories c
+LEFT JOIN risk_types r ON r.risk_category_id = c.id
+GROUP BY c.id
+ORDER BY c.name;
+
+
+-- name: GetProductRiskTypes :many
+SELECT r.id, r.risk_type_id, r.name, r.risk_category, r.risk_type_code, r.description, r.created_at, r.active, r.updated_at,
+       pr.source
+FROM product_risk_types pr
+JOIN risk_types r ON r.risk_type_id = pr.risk_type_id
+WHERE pr.product_id = @product_id AND r.active
+ORDER BY r.name;
+
+-- name: AddProductRiskType :exec
+INSERT INTO product_risk_types(product_id, risk_type_id, source)
+VALUES (@product_id, @risk_type_id, 'admin')
+ON CONFLICT (product_id, risk_type_id) DO NOTHING;
+
+-- name: RemoveProductRiskType :execrows
+DELETE FROM product_risk_types
+WHERE product_id = @product_id AND risk_type_id = @risk_type_id;
+
+-- name: DeleteNICProductRiskTypes :exec
+DELETE FROM product_risk_types
+WHERE source = 'nic' AND product_id = ANY(@product_id::int[]);
+
+-- name: GetNICProductRiskTypes :many
+SELECT product_id, risk_type_id FROM product_risk_types
+WHERE source = 'nic'
+ORDER BY product_id, risk_type_id;
+
+-- name: InsertNICProductRiskTypes :exec
+INSERT INTO product_risk_types(product_id, risk_type_id, source)
+SELECT m.product_id, m.risk_type_id, 'nic'
+FROM unnest(@product_id::int[], @risk_type_id::int[]) AS m(product_id, risk_type_id)
+JOIN risk_types r ON r.risk_type_id = m.risk_type_id
+ON CONFLICT (product_id, risk_type_id) DO NOTHING;
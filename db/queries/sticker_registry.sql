@@ -0,0 +1,57 @@
+-- name: CreateStickerRegistryEntries :exec
+-- Records a batch of generated stickers in one statement. The arrays run in
+-- parallel, one element per sticker.
+INSERT INTO sticker_registry(plate, canonical_plate, sticker_number, sticker_link, client_id, tenant_id, branch)
+SELECT s.plate, s.canonical_plate, s.sticker_number, s.sticker_link, sqlc.narg('client_id')::uuid, sqlc.narg('tenant_id')::uuid, @branch
+FROM unnest(@plates::text[], @canonical_plates::text[], @sticker_numbers::text[], @sticker_links::text[])
+    AS s(plate, canonical_plate, sticker_number, sticker_link);
+
+-- name: GetStickerRegistryEntry :one
+SELECT * FROM sticker_registry
+WHERE id = @id;
+
+-- name: GetStickerRegistryEntries :many
+-- Lists entries newest first. all_tenants skips the tenant filter; otherwise
+-- only entries of the given tenant, or without one when it is null, match.
+SELECT * FROM sticker_registry
+WHERE (@all_tenants::boolean OR tenant_id IS NOT DISTINCT FROM sqlc.narg('tenant_id')::uuid)
+  AND (sqlc.narg('canonical_plate')::varchar IS NULL OR canonical_plate = sqlc.narg('canonical_plate'))
+  AND (sqlc.narg('sticker_number')::varchar IS NULL OR sticker_number = sqlc.narg('sticker_number'))
+  AND (sqlc.narg('status')::varchar IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('branch')::varchar IS NULL OR branch = sqlc.narg('branch'))
+ORDER BY issued_at DESC
+LIMIT @row_limit;
+
+-- name: SetStickerRegistryStatus :one
+UPDATE sticker_registry
+SET status = @status, status_note = @status_note, status_changed_at = NOW()
+WHERE id = @id
+RETURNING *;
+
+-- name: GetDuplicateStickerNumbers :many
+-- Sticker numbers recorded against more than one plate, whatever their
+-- status.
+SELECT sticker_number,
+       array_agg(DISTINCT canonical_plate ORDER BY canonical_plate)::text[] AS plates,
+       COUNT(*)::integer AS entries,
+       MIN(issued_at)::timestamptz AS first_issued_at,
+       MAX(issued_at)::timestamptz AS last_issued_at
+FROM sticker_registry
+WHERE (@all_tenants::boolean OR tenant_id IS NOT DISTINCT FROM sqlc.narg('tenant_id')::uuid)
+GROUP BY sticker_number
+HAVING COUNT(DISTINCT canonical_plate) > 1
+ORDER BY sticker_number;
+
+-- name: GetPlatesWithMultipleActiveStickers :many
+-- Plates with more than one active sticker number. Reprints of the same
+-- number are not counted twice.
+SELECT canonical_plate,
+       array_agg(DISTINCT sticker_number ORDER BY sticker_number)::text[] AS sticker_numbers,
+       COUNT(*)::integer AS entries,
+       MAX(issued_at)::timestamptz AS last_issued_at
+FROM sticker_registry
+WHERE status = 'active'
+  AND (@all_tenants::boolean OR tenant_id IS NOT DISTINCT FROM sqlc.narg('tenant_id')::uuid)
+GROUP BY canonical_plate
+HAVING COUNT(DISTINCT sticker_number) > 1
+ORDER BY canonical_plate;
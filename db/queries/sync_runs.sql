@@ -0,0 +1,22 @@
+-- name: CreateSyncRun :one
+INSERT INTO sync_runs(catalog, trigger_source, dry_run)
+VALUES (@catalog, @trigger_source, @dry_run) RETURNING *;
+
+-- name: FinishSyncRun :exec
+UPDATE sync_runs
+SET finished_at = NOW(), outcome = @outcome, error = @error, upstream_count = @upstream_count,
+    added = @added, updated = @updated, deactivated = @deactivated, unchanged = @unchanged,
+    changes = @changes, rejected = @rejected, payload_checksum = @payload_checksum
+WHERE id = @id;
+
+-- name: GetSyncRuns :many
+SELECT * FROM sync_runs
+WHERE catalog = @catalog
+ORDER BY started_at DESC
+LIMIT @row_limit;
+
+-- name: GetLastSuccessfulSyncRun :one
+SELECT * FROM sync_runs
+WHERE catalog = @catalog AND NOT dry_run AND outcome IN ('success', 'skipped')
+ORDER BY started_at DESC
+LIMIT 1;
@@ -0,0 +1,23 @@
+-- name: CreateTenant :one
+INSERT INTO tenants(name, nic_environment, nic_api_key_encrypted, nic_request_interval_ms, nic_burst, nic_workers)
+VALUES (@name, @nic_environment, @nic_api_key_encrypted, @nic_request_interval_ms, @nic_burst, @nic_workers) RETURNING *;
+
+-- name: GetTenants :many
+SELECT * FROM tenants
+ORDER BY name;
+
+-- name: GetTenant :one
+SELECT * FROM tenants
+WHERE id = @id;
+
+-- name: UpdateTenant :one
+UPDATE tenants
+SET name = @name,
+    nic_environment = @nic_environment,
+    nic_api_key_encrypted = @nic_api_key_encrypted,
+    nic_request_interval_ms = @nic_request_interval_ms,
+    nic_burst = @nic_burst,
+    nic_workers = @nic_workers,
+    updated_at = NOW()
+WHERE id = @id
+RETURNING *;
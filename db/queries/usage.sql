@@ -0,0 +1,33 @@
+-- name: CreateUsageRecord :exec
+INSERT INTO usage_records(client_id, service, plates, nic_calls, issued, amount)
+SELECT @client_id::uuid, @service::varchar, @plates::int, @nic_calls::int, @issued::int,
+       COALESCE((SELECT @plates::int * p.plate_price + @nic_calls::int * p.nic_call_price + @issued::int * p.issued_price
+                 FROM service_prices p WHERE p.service = @service::varchar), 0);
+
+-- name: GetUsageTotals :many
+SELECT u.client_id, c.name AS client_name, u.service,
+       COUNT(*)::int AS requests,
+       SUM(u.plates)::int AS plates,
+       SUM(u.nic_calls)::int AS nic_calls,
+       SUM(u.issued)::int AS issued,
+       SUM(u.amount)::bigint AS amount
+FROM usage_records u
+JOIN api_clients c ON c.id = u.client_id
+WHERE u.created_at >= @period_start AND u.created_at < @period_end
+  AND (sqlc.narg('client_id')::uuid IS NULL OR u.client_id = sqlc.narg('client_id'))
+GROUP BY u.client_id, c.name, u.service
+ORDER BY c.name, u.client_id, u.service;
+
+-- name: GetServicePrices :many
+SELECT * FROM service_prices
+ORDER BY service;
+
+-- name: UpsertServicePrice :one
+INSERT INTO service_prices(service, plate_price, nic_call_price, issued_price)
+VALUES (@service, @plate_price, @nic_call_price, @issued_price)
+ON CONFLICT (service) DO UPDATE
+SET plate_price = EXCLUDED.plate_price,
+    nic_call_price = EXCLUDED.nic_call_price,
+    issued_price = EXCLUDED.issued_price,
+    updated_at = NOW()
+RETURNING *;
@@ -0,0 +1,25 @@
+package http
+
+type APIClientRequest struct {
+	Name     string   `json:"name"`
+	Roles    []string `json:"roles"`
+	TenantID string   `json:"tenantId"`
+	// Limits is optional; new clients get the default limits without it.
+	Limits *APIClientLimitsRequest `json:"limits"`
+}
+
+type APIClientLimitsRequest struct {
+	RequestsPerMinute int `json:"requestsPerMinute"`
+	PlatesPerDay      int `json:"platesPerDay"`
+	MaxBatchSize      int `json:"maxBatchSize"`
+}
+
+// APIClientUpdateRequest is the body of PATCH /api_clients/{id}. Roles is
+// the only field that can be changed.
+type APIClientUpdateRequest struct {
+	Roles []string `json:"roles"`
+}
+
+type APIClientTenantRequest struct {
+	TenantID string `json:"tenantId"`
+}
@@ -0,0 +1,229 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/godsent-code/midtools/internal/application/api_client"
+	"github.com/godsent-code/midtools/internal/application/audit"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+)
+
+type APIClientHandler struct {
+	service api_client.APIClientService
+	audits  audit.AuditService
+}
+
+func (ah *APIClientHandler) CreateAPIClient(w http.ResponseWriter, r *http.Request) {
+	var request APIClientRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := api_client.APIClientInput{
+		Name:     request.Name,
+		Roles:    request.Roles,
+		TenantID: request.TenantID,
+	}
+	if request.Limits != nil {
+		input.Limits = &api_client.APIClientLimitsInput{
+			RequestsPerMinute: request.Limits.RequestsPerMinute,
+			PlatesPerDay:      request.Limits.PlatesPerDay,
+			MaxBatchSize:      request.Limits.MaxBatchSize,
+		}
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := ah.service.CreateAPIClient(r.Context(), input)
+	if err != nil {
+		auditAdmin(r, ah.audits, domain.AuditAPIClientCreate, input.Name, nil, err)
+		writeAPIClientProblem(w, r, err)
+		return
+	}
+	auditAdmin(r, ah.audits, domain.AuditAPIClientCreate, result.ID.String(), result.APIClientOutput, nil)
+	pkg.WriteResponse(w, http.StatusCreated, result)
+}
+
+func (ah *APIClientHandler) GetAPIClients(w http.ResponseWriter, r *http.Request) {
+	results, err := ah.service.GetAPIClients(r.Context())
+	if err != nil {
+		writeAPIClientProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, results)
+}
+
+func (ah *APIClientHandler) GetAPIClient(w http.ResponseWriter, r *http.Request) {
+	id, ok := uuidParam(w, r, "api client")
+	if !ok {
+		return
+	}
+	result, err := ah.service.GetAPIClient(r.Context(), id)
+	if err != nil {
+		writeAPIClientProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (ah *APIClientHandler) RotateAPIClientKey(w http.ResponseWriter, r *http.Request) {
+	id, ok := uuidParam(w, r, "api client")
+	if !ok {
+		return
+	}
+	result, err := ah.service.RotateAPIClientKey(r.Context(), id)
+	if err != nil {
+		auditAdmin(r, ah.audits, domain.AuditAPIClientRotate, id.String(), nil, err)
+		writeAPIClientProblem(w, r, err)
+		return
+	}
+	auditAdmin(r, ah.audits, domain.AuditAPIClientRotate, id.String(), result.APIClientOutput, nil)
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (ah *APIClientHandler) RevokeAPIClient(w http.ResponseWriter, r *http.Request) {
+	id, ok := uuidParam(w, r, "api client")
+	if !ok {
+		return
+	}
+	result, err := ah.service.RevokeAPIClient(r.Context(), id)
+	auditAdmin(r, ah.audits, domain.AuditAPIClientRevoke, id.String(), result, err)
+	if err != nil {
+		writeAPIClientProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+// UpdateAPIClient changes an existing client's roles without issuing a new
+// key.
+func (ah *APIClientHandler) UpdateAPIClient(w http.ResponseWriter, r *http.Request) {
+	id, ok := uuidParam(w, r, "api client")
+	if !ok {
+		return
+	}
+	var request APIClientUpdateRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := api_client.APIClientUpdateInput{Roles: request.Roles}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := ah.service.UpdateAPIClient(r.Context(), id, input)
+	auditAdmin(r, ah.audits, domain.AuditAPIClientSetRoles, id.String(), result, err)
+	if err != nil {
+		writeAPIClientProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (ah *APIClientHandler) SetAPIClientTenant(w http.ResponseWriter, r *http.Request) {
+	id, ok := uuidParam(w, r, "api client")
+	if !ok {
+		return
+	}
+	var request APIClientTenantRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := api_client.APIClientTenantInput{TenantID: request.TenantID}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := ah.service.SetAPIClientTenant(r.Context(), id, input)
+	auditAdmin(r, ah.audits, domain.AuditAPIClientSetTenant, id.String(), result, err)
+	if err != nil {
+		writeAPIClientProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (ah *APIClientHandler) SetAPIClientLimits(w http.ResponseWriter, r *http.Request) {
+	id, ok := uuidParam(w, r, "api client")
+	if !ok {
+		return
+	}
+	var request APIClientLimitsRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := api_client.APIClientLimitsInput{
+		RequestsPerMinute: request.RequestsPerMinute,
+		PlatesPerDay:      request.PlatesPerDay,
+		MaxBatchSize:      request.MaxBatchSize,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := ah.service.SetAPIClientLimits(r.Context(), id, input)
+	auditAdmin(r, ah.audits, domain.AuditAPIClientSetLimits, id.String(), result, err)
+	if err != nil {
+		writeAPIClientProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (ah *APIClientHandler) GetAccessDenials(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	input := api_client.AccessDenialsInput{
+		ClientID: r.URL.Query().Get("clientId"),
+		Limit:    limit,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := ah.service.GetAccessDenials(r.Context(), input)
+	if err != nil {
+		writeAPIClientProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, results)
+}
+
+func writeAPIClientProblem(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		pkg.WriteProblem(w, r, http.StatusNotFound, "api client not found")
+	case errors.Is(err, domain.ErrConflict):
+		pkg.WriteProblem(w, r, http.StatusConflict, "an active api client with that name already exists")
+	case errors.Is(err, api_client.ErrUnknownTenant):
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+	case errors.Is(err, api_client.ErrRevoked):
+		pkg.WriteProblem(w, r, http.StatusConflict, err.Error())
+	default:
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func NewAPIClientHandler(service api_client.APIClientService, audits audit.AuditService) *APIClientHandler {
+	return &APIClientHandler{service: service, audits: audits}
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/godsent-code/midtools/internal/application/audit"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+)
+
+// auditIssuance records a sticker or brown card request with the NIC
+// response for each plate, or the error that stopped it.
+func auditIssuance(r *http.Request, service audit.AuditService, action domain.AuditAction, plates []string, issued int, results any, err error) {
+	auditIssuanceFor(r.Context(), service, clientFromContext(r.Context()), action, plates, issued, results, err)
+}
+
+// auditIssuanceFor records an issuance made for client outside of its
+// request, as approved issuance requests are executed.
+func auditIssuanceFor(ctx context.Context, service audit.AuditService, client *domain.APIClient, action domain.AuditAction, plates []string, issued int, results any, err error) {
+	record := audit.AuditRecord{
+		Client:  client,
+		Action:  action,
+		Plates:  plates,
+		Outcome: domain.AuditOutcomeOf(issued, len(plates)),
+		Detail:  results,
+	}
+	if err != nil {
+		record.Outcome = domain.AuditFailed
+		record.Detail = map[string]string{"error": err.Error()}
+	}
+	service.Record(ctx, record)
+}
+
+// auditAdmin records an admin or review action once the service has
+// answered: the resulting record when it succeeded and the error when it did
+// not. Results holding secrets, such as issued keys, must be stripped of them
+// first.
+func auditAdmin(r *http.Request, service audit.AuditService, action domain.AuditAction, target string, result any, err error) {
+	record := audit.AuditRecord{
+		Client:  clientFromContext(r.Context()),
+		Action:  action,
+		Target:  target,
+		Outcome: domain.AuditSucceeded,
+		Detail:  result,
+	}
+	if err != nil {
+		record.Outcome = domain.AuditFailed
+		record.Detail = map[string]string{"error": err.Error()}
+	}
+	service.Record(r.Context(), record)
+}
+
+// auditCatalogRun records a catalog sync or import that changed the catalog,
+// with its summary. Dry runs change nothing and are not recorded.
+func auditCatalogRun(r *http.Request, service audit.AuditService, action domain.AuditAction, dryRun bool, runID *uuid.UUID, summary any, err error) {
+	if dryRun {
+		return
+	}
+	target := ""
+	if runID != nil {
+		target = runID.String()
+	}
+	auditAdmin(r, service, action, target, summary, err)
+}
+
+type AuditHandler struct {
+	service audit.AuditService
+}
+
+func (ah *AuditHandler) GetEntries(w http.ResponseWriter, r *http.Request) {
+	before, err := queryInt(r, "before")
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	query := r.URL.Query()
+	input := audit.AuditEntriesInput{
+		ClientID: query.Get("clientId"),
+		Action:   query.Get("action"),
+		Plate:    query.Get("plate"),
+		From:     query.Get("from"),
+		To:       query.Get("to"),
+		Before:   before,
+		Limit:    limit,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, next, err := ah.service.GetEntries(r.Context(), input)
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if next != "" {
+		w.Header().Set(nextCursorHeader, next)
+	}
+	pkg.WriteResponse(w, http.StatusOK, results)
+}
+
+func NewAuditHandler(service audit.AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
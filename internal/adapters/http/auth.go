@@ -0,0 +1,91 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/godsent-code/midtools/internal/application/api_client"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+type clientContextKey struct{}
+
+// authenticate rejects requests without a valid API key and stores the
+// calling client, and its tenant for NIC calls, in the request context. The
+// key may be sent as a bearer token or in X-API-Key.
+func authenticate(service api_client.APIClientService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := requestAPIKey(r)
+			if key == "" {
+				writeUnauthorized(w, r, "an API key is required")
+				return
+			}
+			client, err := service.Authenticate(r.Context(), key)
+			if err != nil {
+				if errors.Is(err, api_client.ErrInvalidAPIKey) {
+					writeUnauthorized(w, r, err.Error())
+					return
+				}
+				pkg.WriteProblem(w, r, http.StatusServiceUnavailable, err.Error())
+				return
+			}
+			ctx := context.WithValue(r.Context(), clientContextKey{}, client)
+			if client.Tenant != nil {
+				ctx = domain.WithTenant(ctx, client.Tenant)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// requireRole only lets through clients holding a role that grants role.
+// Refusals are recorded by the service and answered with a 403 naming the
+// role that was needed and the roles the client has.
+func requireRole(service api_client.APIClientService, role domain.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client := clientFromContext(r.Context())
+			if client == nil {
+				writeUnauthorized(w, r, "an API key is required")
+				return
+			}
+			if err := service.Authorize(r.Context(), client, role, r.Method, r.URL.Path); err != nil {
+				pkg.WriteProblemWithExtensions(w, r, http.StatusForbidden,
+					fmt.Sprintf("%s %s requires the %s role", r.Method, r.URL.Path, role),
+					map[string]any{
+						"requiredRole": role,
+						"roles":        client.Roles,
+						"clientId":     client.ID,
+					})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientFromContext returns the authenticated client, or nil outside
+// authenticated routes.
+func clientFromContext(ctx context.Context) *domain.APIClient {
+	client, _ := ctx.Value(clientContextKey{}).(*domain.APIClient)
+	return client
+}
+
+func requestAPIKey(r *http.Request) string {
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return strings.TrimSpace(r.Header.Get(apiKeyHeader))
+}
+
+func writeUnauthorized(w http.ResponseWriter, r *http.Request, detail string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="midtools"`)
+	pkg.WriteProblem(w, r, http.StatusUnauthorized, detail)
+}
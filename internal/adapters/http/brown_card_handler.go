@@ -1,43 +1,109 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 
+	"github.com/godsent-code/midtools/internal/application/audit"
 	"github.com/godsent-code/midtools/internal/application/brown_card_service"
+	"github.com/godsent-code/midtools/internal/application/fleet"
+	"github.com/godsent-code/midtools/internal/application/idempotency"
+	"github.com/godsent-code/midtools/internal/application/issuance"
+	"github.com/godsent-code/midtools/internal/application/metering"
+	"github.com/godsent-code/midtools/internal/application/quota"
+	"github.com/godsent-code/midtools/internal/domain"
 	"github.com/godsent-code/midtools/pkg"
 )
 
 type BrownCardHandler struct {
-	service brown_card_service.BrownCard
+	service   brown_card_service.BrownCard
+	fleets    fleet.FleetService
+	quotas    quota.QuotaService
+	usage     metering.MeteringService
+	audits    audit.AuditService
+	keys      idempotency.IdempotencyService
+	issuances issuance.IssuanceService
 }
 
 func (ach *BrownCardHandler) GetBrownCard(w http.ResponseWriter, r *http.Request) {
 	var request BrownCardRequest
 
 	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
-		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	preview, err := queryBool(r, "dryRun")
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	cars, ok := resolveCarsProblem(w, r, ach.fleets, request.Cars, request.FleetID)
+	if !ok {
 		return
 	}
 
 	br := brown_card_service.BrownCardInput{
-		Cars: request.Cars,
+		Cars: cars,
 	}
 
 	if err := br.Validate(); err != nil {
-		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	plates := len(pkg.SplitCars(cars))
+	if !checkDirectIssuance(w, r, ach.issuances, ach.quotas, plates) {
+		return
+	}
+
+	if preview {
+		dryRun(w, r, ach.keys, ach.quotas, ach.usage, domain.ServiceBrownCard, request, plates, func(ctx context.Context) (*domain.DryRun, error) {
+			return ach.service.PlanBrownCard(ctx, br)
+		})
+		return
+	}
+
+	idem, ok := beginIdempotent(w, r, ach.keys, request)
+	if !ok {
 		return
 	}
 
-	results, err := ach.service.GetBrownCard(r.Context(), br)
+	// The plate quota is taken first as it is the one a client most often
+	// runs out of; the direct issuance allowance is only counted for batches
+	// it lets through, and a refusal there gives the plates back.
+	if !allowPlates(w, r, ach.quotas, plates) {
+		idem.release()
+		return
+	}
+	if !allowDirectIssuance(w, r, ach.issuances, ach.quotas, plates) {
+		refundPlates(r, ach.quotas, plates)
+		idem.release()
+		return
+	}
+
+	ctx, record := meter(r, ach.usage, domain.ServiceBrownCard)
+	results, err := ach.service.GetBrownCard(context.WithoutCancel(ctx), br)
+	issued := 0
+	for _, result := range results {
+		if result.Status {
+			issued++
+		}
+	}
+	record(plates, issued)
+	auditIssuance(r, ach.audits, domain.AuditBrownCardIssue, pkg.SplitCars(cars), issued, results, err)
 	if err != nil {
-		pkg.WriteResponse(w, http.StatusInternalServerError, err.Error())
+		idem.release()
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
+	idem.complete(http.StatusOK, results)
 	pkg.WriteResponse(w, http.StatusOK, results)
 }
 
-func NewBrownCardHandler(service brown_card_service.BrownCard) *BrownCardHandler {
-	return &BrownCardHandler{service: service}
+func NewBrownCardHandler(service brown_card_service.BrownCard, fleets fleet.FleetService, quotas quota.QuotaService, usage metering.MeteringService, audits audit.AuditService, keys idempotency.IdempotencyService, issuances issuance.IssuanceService) *BrownCardHandler {
+	return &BrownCardHandler{service: service, fleets: fleets, quotas: quotas, usage: usage, audits: audits, keys: keys, issuances: issuances}
 }
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+)
+
+const maxCatalogFileSize = 10 << 20
+
+// readCatalogFile reads an uploaded catalog file, either as the raw body or
+// as the "file" field of a multipart upload. The format comes from ?format=
+// and otherwise from the content type, so text/csv bodies need no parameter.
+func readCatalogFile(w http.ResponseWriter, r *http.Request) (string, []byte, bool) {
+	format := r.URL.Query().Get("format")
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxCatalogFileSize)
+	var body io.Reader = r.Body
+	if mediaType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(maxCatalogFileSize); err != nil {
+			writeUploadProblem(w, r, err)
+			return "", nil, false
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+			return "", nil, false
+		}
+		defer file.Close()
+		body = file
+		if format == "" && strings.HasSuffix(strings.ToLower(header.Filename), ".csv") {
+			format = string(domain.CatalogFileCSV)
+		}
+	}
+	if format == "" && mediaType == "text/csv" {
+		format = string(domain.CatalogFileCSV)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		writeUploadProblem(w, r, err)
+		return "", nil, false
+	}
+	return format, data, true
+}
+
+// writeCatalogFile sends an exported catalog as a download named after the
+// catalog.
+func writeCatalogFile(w http.ResponseWriter, name string, format domain.CatalogFileFormat, data []byte) {
+	contentType := "application/json"
+	if format == domain.CatalogFileCSV {
+		contentType = "text/csv; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func writeUploadProblem(w http.ResponseWriter, r *http.Request, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		pkg.WriteProblem(w, r, http.StatusRequestEntityTooLarge, fmt.Sprintf("file must be at most %d bytes", maxCatalogFileSize))
+		return
+	}
+	pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+}
+
+// writeCatalogFileProblem maps catalog import errors to problem responses.
+// Anything other than a bad file is a storage failure, reported the way NIC
+// syncs report theirs.
+func writeCatalogFileProblem(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, domain.ErrInvalidCatalogFile) {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, domain.ErrEmptyCatalog) {
+		pkg.WriteProblem(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	pkg.WriteProblem(w, r, http.StatusServiceUnavailable, err.Error())
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/godsent-code/midtools/internal/application/idempotency"
+	"github.com/godsent-code/midtools/internal/application/metering"
+	"github.com/godsent-code/midtools/internal/application/quota"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+)
+
+// dryRun answers a sticker or brown card request sent with ?dryRun=true. It
+// makes the idempotency and quota checks the request would, refusing it the
+// same way, then plans the batch and prices it at the current service
+// prices. Nothing is sent to NIC, no quota is used and no key is claimed.
+func dryRun(w http.ResponseWriter, r *http.Request, keys idempotency.IdempotencyService, quotas quota.QuotaService, usage metering.MeteringService, name domain.MeteredService, request any, plates int, plan func(ctx context.Context) (*domain.DryRun, error)) {
+	replay, ok := checkIdempotent(w, r, keys, request)
+	if !ok {
+		return
+	}
+	if replay {
+		pkg.WriteResponse(w, http.StatusOK, &domain.DryRun{
+			DryRun:           true,
+			Plates:           plates,
+			Invalid:          []domain.InvalidPlate{},
+			Duplicates:       []string{},
+			NICCalls:         []domain.NICCall{},
+			IdempotentReplay: true,
+		})
+		return
+	}
+	status, ok := checkPlates(w, r, quotas, plates)
+	if !ok {
+		return
+	}
+
+	result, err := plan(r.Context())
+	if err != nil {
+		if errors.Is(err, domain.ErrUnknownRiskType) {
+			pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	prices, err := usage.GetServicePrices(r.Context())
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for _, price := range prices {
+		if price.Service == name {
+			// Priced as if NIC issues every plate it is sent.
+			result.EstimatedAmount = price.Estimate(plates, len(result.NICCalls), len(result.NICCalls))
+		}
+	}
+	result.PlateQuota = status
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
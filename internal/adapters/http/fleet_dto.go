@@ -0,0 +1,16 @@
+package http
+
+type FleetRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type FleetVehicleRequest struct {
+	Plate          string   `json:"plate"`
+	Labels         []string `json:"labels"`
+	OwnerReference string   `json:"ownerReference"`
+}
+
+type FleetVehiclesRequest struct {
+	Vehicles []FleetVehicleRequest `json:"vehicles"`
+}
@@ -0,0 +1,272 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/godsent-code/midtools/internal/application/fleet"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+)
+
+const maxFleetImportSize = 10 << 20
+
+type FleetHandler struct {
+	service fleet.FleetService
+}
+
+func (fh *FleetHandler) CreateFleet(w http.ResponseWriter, r *http.Request) {
+	var request FleetRequest
+
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := fleet.FleetInput{
+		Name:        request.Name,
+		Description: request.Description,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := fh.service.CreateFleet(r.Context(), clientFromContext(r.Context()), input)
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	pkg.WriteResponse(w, http.StatusCreated, result)
+}
+
+func (fh *FleetHandler) GetFleets(w http.ResponseWriter, r *http.Request) {
+	results, err := fh.service.GetFleets(r.Context(), clientFromContext(r.Context()))
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, results)
+}
+
+func (fh *FleetHandler) GetFleet(w http.ResponseWriter, r *http.Request) {
+	id, ok := fleetID(w, r)
+	if !ok {
+		return
+	}
+	result, err := fh.service.GetFleet(r.Context(), clientFromContext(r.Context()), id)
+	if err != nil {
+		writeFleetError(w, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (fh *FleetHandler) UpdateFleet(w http.ResponseWriter, r *http.Request) {
+	id, ok := fleetID(w, r)
+	if !ok {
+		return
+	}
+
+	var request FleetRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := fleet.FleetInput{
+		Name:        request.Name,
+		Description: request.Description,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := fh.service.UpdateFleet(r.Context(), clientFromContext(r.Context()), id, input)
+	if err != nil {
+		writeFleetError(w, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (fh *FleetHandler) DeleteFleet(w http.ResponseWriter, r *http.Request) {
+	id, ok := fleetID(w, r)
+	if !ok {
+		return
+	}
+	if err := fh.service.DeleteFleet(r.Context(), clientFromContext(r.Context()), id); err != nil {
+		writeFleetError(w, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, "Fleet deleted")
+}
+
+func (fh *FleetHandler) GetFleetVehicles(w http.ResponseWriter, r *http.Request) {
+	id, ok := fleetID(w, r)
+	if !ok {
+		return
+	}
+	results, err := fh.service.GetFleetVehicles(r.Context(), clientFromContext(r.Context()), id)
+	if err != nil {
+		writeFleetError(w, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, results)
+}
+
+func (fh *FleetHandler) AddFleetVehicles(w http.ResponseWriter, r *http.Request) {
+	id, ok := fleetID(w, r)
+	if !ok {
+		return
+	}
+
+	var request FleetVehiclesRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(request.Vehicles) == 0 {
+		pkg.WriteResponse(w, http.StatusBadRequest, "vehicles is required")
+		return
+	}
+
+	inputs := make([]fleet.FleetVehicleInput, len(request.Vehicles))
+	for i, v := range request.Vehicles {
+		inputs[i] = fleet.FleetVehicleInput{
+			Plate:          v.Plate,
+			Labels:         v.Labels,
+			OwnerReference: v.OwnerReference,
+		}
+	}
+
+	result, err := fh.service.AddVehicles(r.Context(), clientFromContext(r.Context()), id, inputs)
+	if err != nil {
+		writeFleetError(w, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+// ImportFleetVehicles accepts either a raw CSV body or a multipart upload
+// with the CSV in the "file" field.
+func (fh *FleetHandler) ImportFleetVehicles(w http.ResponseWriter, r *http.Request) {
+	id, ok := fleetID(w, r)
+	if !ok {
+		return
+	}
+
+	var body io.Reader = io.LimitReader(r.Body, maxFleetImportSize)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxFleetImportSize); err != nil {
+			pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	result, err := fh.service.ImportVehiclesCSV(r.Context(), clientFromContext(r.Context()), id, body)
+	if err != nil {
+		writeFleetError(w, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (fh *FleetHandler) RemoveFleetVehicle(w http.ResponseWriter, r *http.Request) {
+	id, ok := fleetID(w, r)
+	if !ok {
+		return
+	}
+	if err := fh.service.RemoveVehicle(r.Context(), clientFromContext(r.Context()), id, chi.URLParam(r, "plate")); err != nil {
+		writeFleetError(w, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, "Vehicle removed")
+}
+
+func fleetID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, "invalid fleet id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func writeFleetError(w http.ResponseWriter, err error) {
+	code, detail := fleetErrorStatus(err)
+	pkg.WriteResponse(w, code, detail)
+}
+
+func fleetErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound, "fleet not found"
+	case errors.Is(err, fleet.ErrEmptyFleet):
+		return http.StatusBadRequest, err.Error()
+	default:
+		return http.StatusInternalServerError, err.Error()
+	}
+}
+
+// resolveCars lets the vehicle endpoints take a fleetId in place of a cars
+// list. Only the calling client's fleets resolve. It writes the error
+// response itself and returns false on failure.
+func resolveCars(ctx context.Context, w http.ResponseWriter, fleets fleet.FleetService, cars, fleetIDValue string) (string, bool) {
+	resolved, code, detail := carsFor(ctx, fleets, cars, fleetIDValue)
+	if code != 0 {
+		pkg.WriteResponse(w, code, detail)
+		return "", false
+	}
+	return resolved, true
+}
+
+// resolveCarsProblem is resolveCars for the issuance endpoints, which answer
+// every error with a problem response.
+func resolveCarsProblem(w http.ResponseWriter, r *http.Request, fleets fleet.FleetService, cars, fleetIDValue string) (string, bool) {
+	resolved, code, detail := carsFor(r.Context(), fleets, cars, fleetIDValue)
+	if code != 0 {
+		pkg.WriteProblem(w, r, code, detail)
+		return "", false
+	}
+	return resolved, true
+}
+
+// carsFor returns the cars a request names, directly or by fleetId, or the
+// status and detail of the error response when they cannot be resolved.
+func carsFor(ctx context.Context, fleets fleet.FleetService, cars, fleetIDValue string) (string, int, string) {
+	if strings.TrimSpace(fleetIDValue) == "" {
+		return cars, 0, ""
+	}
+	if strings.TrimSpace(cars) != "" {
+		return "", http.StatusBadRequest, "provide either cars or fleetId, not both"
+	}
+	id, err := uuid.Parse(fleetIDValue)
+	if err != nil {
+		return "", http.StatusBadRequest, "invalid fleet id"
+	}
+	resolved, err := fleets.FleetCars(ctx, clientFromContext(ctx), id)
+	if err != nil {
+		code, detail := fleetErrorStatus(err)
+		return "", code, detail
+	}
+	return resolved, 0, ""
+}
+
+func NewFleetHandler(service fleet.FleetService) *FleetHandler {
+	return &FleetHandler{service: service}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/godsent-code/midtools/internal/application/fleet"
+	"github.com/godsent-code/midtools/internal/application/fleet_monitor"
+	"github.com/godsent-code/midtools/internal/application/metering"
+	"github.com/godsent-code/midtools/internal/application/quota"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+)
+
+type FleetMonitorHandler struct {
+	service fleet_monitor.FleetMonitorService
+	fleets  fleet.FleetService
+	quotas  quota.QuotaService
+	usage   metering.MeteringService
+}
+
+func (fmh *FleetMonitorHandler) CheckFleet(w http.ResponseWriter, r *http.Request) {
+	id, ok := fleetID(w, r)
+	if !ok {
+		return
+	}
+	// Checks call NIC for every vehicle, so they count against the plate
+	// quota like any other batch.
+	cars, err := fmh.fleets.FleetCars(r.Context(), clientFromContext(r.Context()), id)
+	if err != nil && !errors.Is(err, fleet.ErrEmptyFleet) {
+		writeFleetError(w, err)
+		return
+	}
+	plates := len(pkg.SplitCars(cars))
+	if !allowPlates(w, r, fmh.quotas, plates) {
+		return
+	}
+	ctx, record := meter(r, fmh.usage, domain.ServiceFleetCheck)
+	result, err := fmh.service.CheckFleet(ctx, clientFromContext(ctx), id)
+	record(plates, 0)
+	if err != nil {
+		writeFleetError(w, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (fmh *FleetMonitorHandler) GetVehicleStatuses(w http.ResponseWriter, r *http.Request) {
+	id, ok := fleetID(w, r)
+	if !ok {
+		return
+	}
+	results, err := fmh.service.GetVehicleStatuses(r.Context(), clientFromContext(r.Context()), id)
+	if err != nil {
+		writeFleetError(w, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, results)
+}
+
+func (fmh *FleetMonitorHandler) GetOpenAlerts(w http.ResponseWriter, r *http.Request) {
+	id, ok := fleetID(w, r)
+	if !ok {
+		return
+	}
+	results, err := fmh.service.GetOpenAlerts(r.Context(), clientFromContext(r.Context()), id)
+	if err != nil {
+		writeFleetError(w, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, results)
+}
+
+func (fmh *FleetMonitorHandler) ResolveAlert(w http.ResponseWriter, r *http.Request) {
+	id, ok := fleetID(w, r)
+	if !ok {
+		return
+	}
+	alertID, err := uuid.Parse(chi.URLParam(r, "alertId"))
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, "invalid alert id")
+		return
+	}
+	if err := fmh.service.ResolveAlert(r.Context(), clientFromContext(r.Context()), id, alertID); err != nil {
+		writeFleetError(w, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, "Alert resolved")
+}
+
+func NewFleetMonitorHandler(service fleet_monitor.FleetMonitorService, fleets fleet.FleetService, quotas quota.QuotaService, usage metering.MeteringService) *FleetMonitorHandler {
+	return &FleetMonitorHandler{service: service, fleets: fleets, quotas: quotas, usage: usage}
+}
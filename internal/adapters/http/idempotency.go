@@ -0,0 +1,104 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/godsent-code/midtools/internal/application/idempotency"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayedHeader marks a response replayed from an earlier
+	// request with the same key.
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// idempotentRequest is a request holding its Idempotency-Key. A nil
+// idempotentRequest, for requests sent without a key, does nothing.
+type idempotentRequest struct {
+	r       *http.Request
+	service idempotency.IdempotencyService
+	key     domain.IdempotencyKey
+}
+
+// beginIdempotent claims the request's Idempotency-Key, if it has one, for
+// request, the decoded body. It returns false when the response has already
+// been written: a replay of the key's first request, or a refusal because
+// the key is in use.
+func beginIdempotent(w http.ResponseWriter, r *http.Request, service idempotency.IdempotencyService, request any) (*idempotentRequest, bool) {
+	value := r.Header.Get(idempotencyKeyHeader)
+	client := clientFromContext(r.Context())
+	if value == "" || client == nil {
+		return nil, true
+	}
+	if err := idempotency.ValidateKey(value); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	key := domain.IdempotencyKey{ClientID: client.ID, Endpoint: r.URL.Path, Key: value}
+	record, err := service.Begin(r.Context(), key, request)
+	switch {
+	case err == nil && record == nil:
+		return &idempotentRequest{r: r, service: service, key: key}, true
+	case err == nil:
+		w.Header().Set(idempotentReplayedHeader, "true")
+		pkg.WriteResponse(w, record.StatusCode, record.Response)
+	default:
+		writeIdempotencyProblem(w, r, err)
+	}
+	return nil, false
+}
+
+// checkIdempotent is beginIdempotent for dry runs: it reports whether the
+// request would be replayed, and refuses it as beginIdempotent would, but
+// leaves the key unclaimed.
+func checkIdempotent(w http.ResponseWriter, r *http.Request, service idempotency.IdempotencyService, request any) (replay, ok bool) {
+	value := r.Header.Get(idempotencyKeyHeader)
+	client := clientFromContext(r.Context())
+	if value == "" || client == nil {
+		return false, true
+	}
+	if err := idempotency.ValidateKey(value); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return false, false
+	}
+	key := domain.IdempotencyKey{ClientID: client.ID, Endpoint: r.URL.Path, Key: value}
+	record, err := service.Check(r.Context(), key, request)
+	if err != nil {
+		writeIdempotencyProblem(w, r, err)
+		return false, false
+	}
+	return record != nil, true
+}
+
+func writeIdempotencyProblem(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, idempotency.ErrKeyReused):
+		pkg.WriteProblem(w, r, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, idempotency.ErrKeyInProgress):
+		w.Header().Set("Retry-After", "1")
+		pkg.WriteProblem(w, r, http.StatusConflict, err.Error())
+	default:
+		pkg.WriteProblem(w, r, http.StatusServiceUnavailable, err.Error())
+	}
+}
+
+// complete stores the response for replay to retries.
+func (ir *idempotentRequest) complete(statusCode int, response any) {
+	if ir == nil {
+		return
+	}
+	ir.service.Complete(ir.r.Context(), ir.key, statusCode, response)
+}
+
+// release frees the key of a request that did not complete, so the client
+// can retry it with the same key.
+func (ir *idempotentRequest) release() {
+	if ir == nil {
+		return
+	}
+	ir.service.Release(ir.r.Context(), ir.key)
+}
@@ -0,0 +1,14 @@
+package http
+
+type IssuanceRequestRequest struct {
+	Kind       string `json:"kind"`
+	Cars       string `json:"cars"`
+	FleetID    string `json:"fleetId"`
+	RiskTypeID int    `json:"riskTypeId"`
+	Note       string `json:"note"`
+	Branch     string `json:"branch"`
+}
+
+type IssuanceDecisionRequest struct {
+	Note string `json:"note"`
+}
@@ -0,0 +1,236 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/godsent-code/midtools/internal/application/audit"
+	"github.com/godsent-code/midtools/internal/application/fleet"
+	"github.com/godsent-code/midtools/internal/application/issuance"
+	"github.com/godsent-code/midtools/internal/application/quota"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+)
+
+// allowDirectIssuance refuses sticker and brown card batches that must go
+// through an issuance request instead: those above the approval threshold
+// and those that would take the client past it in plates issued directly
+// today. Batches let through are counted. It writes the error response
+// itself and returns false when refused.
+func allowDirectIssuance(w http.ResponseWriter, r *http.Request, issuances issuance.IssuanceService, quotas quota.QuotaService, plates int) bool {
+	return directIssuance(w, r, issuances, plates, func(client *domain.APIClient) (*domain.QuotaStatus, error) {
+		return quotas.AllowDirectIssuance(r.Context(), client, plates, issuances.Threshold())
+	})
+}
+
+// checkDirectIssuance refuses a batch as allowDirectIssuance would without
+// counting it, for dry runs and before an idempotency key is claimed.
+func checkDirectIssuance(w http.ResponseWriter, r *http.Request, issuances issuance.IssuanceService, quotas quota.QuotaService, plates int) bool {
+	return directIssuance(w, r, issuances, plates, func(client *domain.APIClient) (*domain.QuotaStatus, error) {
+		return quotas.CheckDirectIssuance(r.Context(), client, plates, issuances.Threshold())
+	})
+}
+
+func directIssuance(w http.ResponseWriter, r *http.Request, issuances issuance.IssuanceService, plates int, count func(client *domain.APIClient) (*domain.QuotaStatus, error)) bool {
+	threshold := issuances.Threshold()
+	if issuances.RequiresApproval(plates) {
+		pkg.WriteProblemWithExtensions(w, r, http.StatusForbidden,
+			fmt.Sprintf("batches of more than %d plates must be submitted to /issuance_requests for approval", threshold),
+			map[string]any{"plates": plates, "approvalThreshold": threshold})
+		return false
+	}
+	client := clientFromContext(r.Context())
+	if client == nil {
+		return true
+	}
+	status, err := count(client)
+	if err != nil {
+		if errors.Is(err, quota.ErrApprovalRequired) {
+			pkg.WriteProblemWithExtensions(w, r, http.StatusForbidden,
+				fmt.Sprintf("at most %d plates a day may be issued without approval; submit this batch to /issuance_requests", threshold),
+				map[string]any{"plates": plates, "approvalThreshold": threshold, "directIssuance": status})
+			return false
+		}
+		pkg.WriteProblem(w, r, http.StatusServiceUnavailable, err.Error())
+		return false
+	}
+	return true
+}
+
+type IssuanceHandler struct {
+	service issuance.IssuanceService
+	fleets  fleet.FleetService
+	quotas  quota.QuotaService
+	audits  audit.AuditService
+	runner  *IssuanceRunner
+}
+
+// SubmitIssuanceRequest stores a batch for approval. The maker's batch size
+// and daily plate quota are applied now, as the batch is run on their
+// behalf once approved.
+func (ih *IssuanceHandler) SubmitIssuanceRequest(w http.ResponseWriter, r *http.Request) {
+	client := clientFromContext(r.Context())
+	if client == nil {
+		writeUnauthorized(w, r, "an API key is required")
+		return
+	}
+	var request IssuanceRequestRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	cars, ok := resolveCarsProblem(w, r, ih.fleets, request.Cars, request.FleetID)
+	if !ok {
+		return
+	}
+	input := issuance.SubmitInput{
+		Kind:       request.Kind,
+		Cars:       cars,
+		RiskTypeID: request.RiskTypeID,
+		Note:       request.Note,
+		Branch:     request.Branch,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !allowPlates(w, r, ih.quotas, len(pkg.SplitCars(cars))) {
+		return
+	}
+
+	result, err := ih.service.Submit(r.Context(), client, input)
+	if err != nil {
+		auditAdmin(r, ih.audits, domain.AuditIssuanceSubmit, "", nil, err)
+		writeIssuanceProblem(w, r, err)
+		return
+	}
+	auditAdmin(r, ih.audits, domain.AuditIssuanceSubmit, result.ID.String(), result, nil)
+	pkg.WriteResponse(w, http.StatusCreated, result)
+}
+
+func (ih *IssuanceHandler) GetIssuanceRequests(w http.ResponseWriter, r *http.Request) {
+	client := clientFromContext(r.Context())
+	if client == nil {
+		writeUnauthorized(w, r, "an API key is required")
+		return
+	}
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	input := issuance.ListInput{
+		Status: r.URL.Query().Get("status"),
+		Kind:   r.URL.Query().Get("kind"),
+		Limit:  limit,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	results, err := ih.service.List(r.Context(), client, input)
+	if err != nil {
+		writeIssuanceProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, results)
+}
+
+func (ih *IssuanceHandler) GetIssuanceRequest(w http.ResponseWriter, r *http.Request) {
+	client := clientFromContext(r.Context())
+	if client == nil {
+		writeUnauthorized(w, r, "an API key is required")
+		return
+	}
+	id, ok := uuidParam(w, r, "issuance request")
+	if !ok {
+		return
+	}
+	result, err := ih.service.Get(r.Context(), client, id)
+	if err != nil {
+		writeIssuanceProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+// ApproveIssuanceRequest approves a pending request and starts executing
+// it in the background. The response is the approved request; its outcome
+// is read back with GetIssuanceRequest once it is executed or failed.
+func (ih *IssuanceHandler) ApproveIssuanceRequest(w http.ResponseWriter, r *http.Request) {
+	client, id, input, ok := ih.readDecision(w, r)
+	if !ok {
+		return
+	}
+	approved, err := ih.service.Approve(r.Context(), client, id, input)
+	auditAdmin(r, ih.audits, domain.AuditIssuanceApprove, id.String(), approved, err)
+	if err != nil {
+		writeIssuanceProblem(w, r, err)
+		return
+	}
+	ih.runner.Start(r.Context(), issuance.Execution{Request: approved, Approver: client})
+	pkg.WriteResponse(w, http.StatusAccepted, approved)
+}
+
+func (ih *IssuanceHandler) RejectIssuanceRequest(w http.ResponseWriter, r *http.Request) {
+	client, id, input, ok := ih.readDecision(w, r)
+	if !ok {
+		return
+	}
+	result, err := ih.service.Reject(r.Context(), client, id, input)
+	auditAdmin(r, ih.audits, domain.AuditIssuanceReject, id.String(), result, err)
+	if err != nil {
+		writeIssuanceProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (ih *IssuanceHandler) readDecision(w http.ResponseWriter, r *http.Request) (*domain.APIClient, uuid.UUID, issuance.DecisionInput, bool) {
+	client := clientFromContext(r.Context())
+	if client == nil {
+		writeUnauthorized(w, r, "an API key is required")
+		return nil, uuid.Nil, issuance.DecisionInput{}, false
+	}
+	id, ok := uuidParam(w, r, "issuance request")
+	if !ok {
+		return nil, uuid.Nil, issuance.DecisionInput{}, false
+	}
+	var request IssuanceDecisionRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return nil, uuid.Nil, issuance.DecisionInput{}, false
+	}
+	input := issuance.DecisionInput{Note: request.Note}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return nil, uuid.Nil, issuance.DecisionInput{}, false
+	}
+	return client, id, input, true
+}
+
+func writeIssuanceProblem(w http.ResponseWriter, r *http.Request, err error) {
+	var invalid *issuance.InvalidPlatesError
+	switch {
+	case errors.As(err, &invalid):
+		pkg.WriteProblemWithExtensions(w, r, http.StatusBadRequest, err.Error(), map[string]any{"invalidPlates": invalid.Plates})
+	case errors.Is(err, domain.ErrNotFound):
+		pkg.WriteProblem(w, r, http.StatusNotFound, "issuance request not found")
+	case errors.Is(err, domain.ErrUnknownRiskType), errors.Is(err, issuance.ErrReasonRequired):
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+	case errors.Is(err, issuance.ErrSelfApproval), errors.Is(err, issuance.ErrOtherTenant):
+		pkg.WriteProblem(w, r, http.StatusForbidden, err.Error())
+	case errors.Is(err, issuance.ErrNotPending):
+		pkg.WriteProblem(w, r, http.StatusConflict, err.Error())
+	default:
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func NewIssuanceHandler(service issuance.IssuanceService, fleets fleet.FleetService, quotas quota.QuotaService, audits audit.AuditService, runner *IssuanceRunner) *IssuanceHandler {
+	return &IssuanceHandler{service: service, fleets: fleets, quotas: quotas, audits: audits, runner: runner}
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/godsent-code/midtools/internal/application/audit"
+	"github.com/godsent-code/midtools/internal/application/brown_card_service"
+	"github.com/godsent-code/midtools/internal/application/issuance"
+	"github.com/godsent-code/midtools/internal/application/metering"
+	"github.com/godsent-code/midtools/internal/application/sticker"
+	"github.com/godsent-code/midtools/internal/application/sticker_registry"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/rs/zerolog/log"
+)
+
+// IssuanceRunner executes approved issuance requests in the background, so a
+// batch neither holds up the approver's request nor stops when it ends. Each
+// run claims its request first, so a request picked up twice runs once.
+type IssuanceRunner struct {
+	service    issuance.IssuanceService
+	stickers   sticker.StickerService
+	brownCards brown_card_service.BrownCard
+	usage      metering.MeteringService
+	audits     audit.AuditService
+	registry   sticker_registry.StickerRegistryService
+}
+
+// Start executes an approved request in the background. ctx is only used for
+// its values; cancelling it does not stop the batch.
+func (ir *IssuanceRunner) Start(ctx context.Context, execution issuance.Execution) {
+	go ir.run(context.WithoutCancel(ctx), execution)
+}
+
+// Recover fails requests interrupted while executing and executes, one after
+// the other, approved requests that were never started, such as those
+// approved just before a restart. Cancelling ctx stops it between batches.
+func (ir *IssuanceRunner) Recover(ctx context.Context) {
+	executions, err := ir.service.Recover(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error recovering issuance requests")
+		return
+	}
+	for _, execution := range executions {
+		if ctx.Err() != nil {
+			return
+		}
+		ir.run(context.WithoutCancel(ctx), execution)
+	}
+}
+
+// run executes the batch with the approver's tenant and bills it to the
+// maker.
+func (ir *IssuanceRunner) run(ctx context.Context, execution issuance.Execution) {
+	approver := execution.Approver
+	request, err := ir.service.Start(ctx, approver.ID, execution.Request.ID)
+	if err != nil {
+		if !errors.Is(err, issuance.ErrNotApproved) {
+			log.Error().Err(err).Str("request", execution.Request.ID.String()).Msg("Error starting issuance request")
+		}
+		return
+	}
+	if approver.Tenant != nil {
+		ctx = domain.WithTenant(ctx, approver.Tenant)
+	}
+
+	ctx, record := meterFor(ctx, ir.usage, domain.MeteredService(request.Kind), &request.MakerID)
+	cars := strings.Join(request.Plates, ",")
+	var results any
+	var action domain.AuditAction
+	issued := 0
+	switch request.Kind {
+	case domain.IssuanceSticker:
+		input := sticker.StickerInput{Cars: cars}
+		if request.RiskTypeID != nil {
+			input.RiskTypeID = *request.RiskTypeID
+		}
+		var stickers []sticker.StickerOutput
+		stickers, err = ir.stickers.GetSticker(ctx, input)
+		for _, result := range stickers {
+			if result.Status {
+				issued++
+			}
+		}
+		if err == nil {
+			registerStickers(ctx, ir.registry, sticker_registry.RegistryRecord{
+				ClientID: &request.MakerID,
+				TenantID: request.TenantID,
+				Branch:   request.Branch,
+			}, stickers)
+		}
+		results, action = stickers, domain.AuditStickerIssue
+	case domain.IssuanceBrownCard:
+		var brownCards []brown_card_service.BrownCardOutput
+		brownCards, err = ir.brownCards.GetBrownCard(ctx, brown_card_service.BrownCardInput{Cars: cars})
+		for _, result := range brownCards {
+			if result.Status {
+				issued++
+			}
+		}
+		results, action = brownCards, domain.AuditBrownCardIssue
+	}
+	record(len(request.Plates), issued)
+	auditIssuanceFor(ctx, ir.audits, approver, action, request.Plates, issued, results, err)
+
+	if _, err := ir.service.Complete(ctx, approver.ID, request.ID, results, issued, err); err != nil {
+		log.Error().Err(err).Str("request", request.ID.String()).Msg("Error completing issuance request")
+	}
+}
+
+func NewIssuanceRunner(service issuance.IssuanceService, stickers sticker.StickerService, brownCards brown_card_service.BrownCard, usage metering.MeteringService, audits audit.AuditService, registry sticker_registry.StickerRegistryService) *IssuanceRunner {
+	return &IssuanceRunner{service: service, stickers: stickers, brownCards: brownCards, usage: usage, audits: audits, registry: registry}
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+)
+
+const (
+	// lastSyncedAtHeader carries the time a catalog was last confirmed to
+	// match NIC on catalog listings.
+	lastSyncedAtHeader = "X-Last-Synced-At"
+	totalCountHeader   = "X-Total-Count"
+	nextCursorHeader   = "X-Next-Cursor"
+)
+
+func queryBool(r *http.Request, name string) (bool, error) {
+	value := strings.TrimSpace(r.URL.Query().Get(name))
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false", name)
+	}
+	return b, nil
+}
+
+func queryInt(r *http.Request, name string) (int, error) {
+	value := strings.TrimSpace(r.URL.Query().Get(name))
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return n, nil
+}
+
+// triggerSource identifies who started a sync from an HTTP request: the
+// authenticated client, by name and ID, so the sync history cannot be
+// attributed to someone else.
+func triggerSource(r *http.Request) string {
+	client := clientFromContext(r.Context())
+	if client == nil {
+		return "api"
+	}
+	return fmt.Sprintf("api:%s:%s", client.Name, client.ID)
+}
+
+func setLastSyncedAt(w http.ResponseWriter, t *time.Time) {
+	if t != nil {
+		w.Header().Set(lastSyncedAtHeader, t.UTC().Format(time.RFC3339))
+	}
+}
+
+// setPage reports paging on listings that keep their plain array body: the
+// total number of matches and, unless this is the last page, the cursor for
+// the next one.
+func setPage(w http.ResponseWriter, total int, nextCursor string) {
+	w.Header().Set(totalCountHeader, strconv.Itoa(total))
+	if nextCursor != "" {
+		w.Header().Set(nextCursorHeader, nextCursor)
+	}
+}
+
+// writeCatalogProblem maps product and risk type lookup errors to problem
+// responses.
+func writeCatalogProblem(w http.ResponseWriter, r *http.Request, err error, label string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		pkg.WriteProblem(w, r, http.StatusNotFound, label+" not found")
+	case errors.Is(err, domain.ErrInvalidCatalogRef), errors.Is(err, domain.ErrUnknownRiskType):
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+	default:
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// uuidParam reads the {id} URL parameter, writing a problem response if it is
+// not a UUID.
+func uuidParam(w http.ResponseWriter, r *http.Request, label string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, "invalid "+label+" id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,8 @@
+package http
+
+type PlateRiskRuleRequest struct {
+	PlateKind         string `json:"plateKind"`
+	Code              string `json:"code"`
+	RiskCategoryID    string `json:"riskCategoryId"`
+	DefaultRiskTypeID int    `json:"defaultRiskTypeId"`
+}
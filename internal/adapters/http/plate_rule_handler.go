@@ -0,0 +1,114 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/godsent-code/midtools/internal/application/audit"
+	"github.com/godsent-code/midtools/internal/application/plate_rule"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+)
+
+type PlateRuleHandler struct {
+	service plate_rule.PlateRuleService
+	audits  audit.AuditService
+}
+
+func (ph *PlateRuleHandler) CreatePlateRiskRule(w http.ResponseWriter, r *http.Request) {
+	var request PlateRiskRuleRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := plate_rule.PlateRiskRuleInput{
+		PlateKind:         request.PlateKind,
+		Code:              request.Code,
+		RiskCategoryID:    request.RiskCategoryID,
+		DefaultRiskTypeID: request.DefaultRiskTypeID,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := ph.service.CreatePlateRiskRule(r.Context(), input)
+	if err != nil {
+		auditAdmin(r, ph.audits, domain.AuditPlateRuleCreate, "", nil, err)
+		writePlateRuleProblem(w, r, err)
+		return
+	}
+	auditAdmin(r, ph.audits, domain.AuditPlateRuleCreate, result.ID.String(), result, nil)
+	pkg.WriteResponse(w, http.StatusCreated, result)
+}
+
+func (ph *PlateRuleHandler) GetPlateRiskRules(w http.ResponseWriter, r *http.Request) {
+	results, err := ph.service.GetPlateRiskRules(r.Context())
+	if err != nil {
+		writePlateRuleProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, results)
+}
+
+func (ph *PlateRuleHandler) DeletePlateRiskRule(w http.ResponseWriter, r *http.Request) {
+	id, ok := uuidParam(w, r, "plate rule")
+	if !ok {
+		return
+	}
+	err := ph.service.DeletePlateRiskRule(r.Context(), id)
+	auditAdmin(r, ph.audits, domain.AuditPlateRuleDelete, id.String(), nil, err)
+	if err != nil {
+		writePlateRuleProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, "Plate rule deleted")
+}
+
+func (ph *PlateRuleHandler) SuggestRiskTypes(w http.ResponseWriter, r *http.Request) {
+	productID, err := queryInt(r, "productId")
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := plate_rule.PlateRiskTypesInput{
+		Plate:     chi.URLParam(r, "plate"),
+		ProductID: productID,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := ph.service.SuggestRiskTypes(r.Context(), input)
+	if err != nil {
+		writePlateRuleProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func writePlateRuleProblem(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		pkg.WriteProblem(w, r, http.StatusNotFound, "plate rule not found")
+	case errors.Is(err, domain.ErrConflict):
+		pkg.WriteProblem(w, r, http.StatusConflict, "the plate kind and code already allow that risk category")
+	case errors.Is(err, domain.ErrUnknownProduct),
+		errors.Is(err, domain.ErrUnknownRiskType),
+		errors.Is(err, domain.ErrUnknownRiskCategory),
+		errors.Is(err, plate_rule.ErrDefaultOutsideCategory):
+		pkg.WriteProblem(w, r, http.StatusUnprocessableEntity, err.Error())
+	default:
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func NewPlateRuleHandler(service plate_rule.PlateRuleService, audits audit.AuditService) *PlateRuleHandler {
+	return &PlateRuleHandler{service: service, audits: audits}
+}
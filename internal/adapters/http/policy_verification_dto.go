@@ -1,5 +1,7 @@
 package http
 
 type PolicyVerificationRequest struct {
-	Cars string `json:"cars"`
+	Cars    string `json:"cars"`
+	FleetID string `json:"fleetId"`
+	AsOf    string `json:"asOf"`
 }
@@ -5,12 +5,19 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/godsent-code/midtools/internal/application/fleet"
+	"github.com/godsent-code/midtools/internal/application/metering"
 	"github.com/godsent-code/midtools/internal/application/policy_verification"
+	"github.com/godsent-code/midtools/internal/application/quota"
+	"github.com/godsent-code/midtools/internal/domain"
 	"github.com/godsent-code/midtools/pkg"
 )
 
 type PolicyVerificationHandler struct {
 	service policy_verification.PolicyVerificationService
+	fleets  fleet.FleetService
+	quotas  quota.QuotaService
+	usage   metering.MeteringService
 }
 
 func (pvh *PolicyVerificationHandler) GetPolicyVerifications(w http.ResponseWriter, r *http.Request) {
@@ -21,8 +28,14 @@ func (pvh *PolicyVerificationHandler) GetPolicyVerifications(w http.ResponseWrit
 		return
 	}
 
+	cars, ok := resolveCars(r.Context(), w, pvh.fleets, request.Cars, request.FleetID)
+	if !ok {
+		return
+	}
+
 	br := policy_verification.PolicyVerificationInput{
-		Cars: request.Cars,
+		Cars: cars,
+		AsOf: request.AsOf,
 	}
 
 	if err := br.Validate(); err != nil {
@@ -30,7 +43,14 @@ func (pvh *PolicyVerificationHandler) GetPolicyVerifications(w http.ResponseWrit
 		return
 	}
 
-	results, err := pvh.service.GetPolicyVerifications(r.Context(), br)
+	plates := len(pkg.SplitCars(cars))
+	if !allowPlates(w, r, pvh.quotas, plates) {
+		return
+	}
+
+	ctx, record := meter(r, pvh.usage, domain.ServicePolicyVerification)
+	results, err := pvh.service.GetPolicyVerifications(ctx, br)
+	record(plates, 0)
 	if err != nil {
 		pkg.WriteResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -38,6 +58,6 @@ func (pvh *PolicyVerificationHandler) GetPolicyVerifications(w http.ResponseWrit
 	pkg.WriteResponse(w, http.StatusOK, results)
 }
 
-func NewPolicyVerificationHandler(service policy_verification.PolicyVerificationService) *PolicyVerificationHandler {
-	return &PolicyVerificationHandler{service: service}
+func NewPolicyVerificationHandler(service policy_verification.PolicyVerificationService, fleets fleet.FleetService, quotas quota.QuotaService, usage metering.MeteringService) *PolicyVerificationHandler {
+	return &PolicyVerificationHandler{service: service, fleets: fleets, quotas: quotas, usage: usage}
 }
@@ -0,0 +1,5 @@
+package http
+
+type ProductRiskTypeRequest struct {
+	RiskTypeID int `json:"riskTypeId"`
+}
@@ -1,34 +1,247 @@
 package http
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/godsent-code/midtools/internal/application/audit"
 	"github.com/godsent-code/midtools/internal/application/product"
+	"github.com/godsent-code/midtools/internal/domain"
 	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
 )
 
 type ProductHandler struct {
 	service product.ProductService
+	audits  audit.AuditService
 }
 
 func (ph *ProductHandler) GetProducts(w http.ResponseWriter, r *http.Request) {
-	products, err := ph.service.GetProducts(r.Context())
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	input := product.ProductsInput{
+		AsOf:   query.Get("asOf"),
+		Search: query.Get("q"),
+		Code:   query.Get("code"),
+		Active: query.Get("active"),
+		Sort:   query.Get("sort"),
+		Limit:  limit,
+		Cursor: query.Get("cursor"),
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	products, err := ph.service.GetProducts(r.Context(), input)
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	lastSynced, err := ph.service.LastSyncedAt(r.Context())
 	if err != nil {
 		pkg.WriteResponse(w, http.StatusServiceUnavailable, err.Error())
 		return
 	}
-	pkg.WriteResponse(w, http.StatusOK, products)
+	setLastSyncedAt(w, lastSynced)
+	setPage(w, products.Total, products.NextCursor)
+	pkg.WriteResponse(w, http.StatusOK, products.Products)
 }
 
 func (ph *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
-	err := ph.service.CreateProducts(r.Context())
+	dryRun, err := queryBool(r, "dryRun")
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	force, err := queryBool(r, "force")
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := product.SyncInput{
+		TriggerSource: triggerSource(r),
+		DryRun:        dryRun,
+		Force:         force,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	summary, err := ph.service.CreateProducts(r.Context(), input)
+	auditCatalogRun(r, ph.audits, domain.AuditProductSync, dryRun, productRunID(summary), summary, err)
+	if errors.Is(err, domain.ErrEmptyCatalog) {
+		pkg.WriteResponse(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
 	if err != nil {
 		pkg.WriteResponse(w, http.StatusServiceUnavailable, err.Error())
 		return
 	}
-	pkg.WriteResponse(w, http.StatusOK, "Product created")
+	pkg.WriteResponse(w, http.StatusOK, summary)
+}
+
+// ImportProducts reconciles the catalog with an uploaded fixture file in the
+// NIC shape, for environments that cannot reach NIC.
+func (ph *ProductHandler) ImportProducts(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := queryBool(r, "dryRun")
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	force, err := queryBool(r, "force")
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	format, data, ok := readCatalogFile(w, r)
+	if !ok {
+		return
+	}
+
+	input := product.ImportInput{
+		TriggerSource: triggerSource(r),
+		DryRun:        dryRun,
+		Force:         force,
+		Format:        format,
+		Data:          data,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	summary, err := ph.service.ImportProducts(r.Context(), input)
+	auditCatalogRun(r, ph.audits, domain.AuditProductImport, dryRun, productRunID(summary), summary, err)
+	if err != nil {
+		writeCatalogFileProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, summary)
+}
+
+func (ph *ProductHandler) ExportProducts(w http.ResponseWriter, r *http.Request) {
+	format, err := domain.ParseCatalogFileFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	data, err := ph.service.ExportProducts(r.Context(), format)
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeCatalogFile(w, "products", format, data)
+}
+
+func (ph *ProductHandler) GetSyncRuns(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	runs, err := ph.service.GetSyncRuns(r.Context(), limit)
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, runs)
+}
+
+func (ph *ProductHandler) GetProductHistory(w http.ResponseWriter, r *http.Request) {
+	versions, err := ph.service.GetProductHistory(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeCatalogProblem(w, r, err, "product")
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, versions)
+}
+
+func (ph *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
+	result, err := ph.service.GetProduct(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeCatalogProblem(w, r, err, "product")
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (ph *ProductHandler) GetProductByCode(w http.ResponseWriter, r *http.Request) {
+	result, err := ph.service.GetProductByCode(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		writeCatalogProblem(w, r, err, "product")
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (ph *ProductHandler) GetProductRiskTypes(w http.ResponseWriter, r *http.Request) {
+	riskTypes, err := ph.service.GetProductRiskTypes(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeCatalogProblem(w, r, err, "product")
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, riskTypes)
+}
+
+func (ph *ProductHandler) AddProductRiskType(w http.ResponseWriter, r *http.Request) {
+	var request ProductRiskTypeRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := product.ProductRiskTypeInput{
+		RiskTypeID: request.RiskTypeID,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err := ph.service.AddProductRiskType(r.Context(), chi.URLParam(r, "id"), input)
+	auditAdmin(r, ph.audits, domain.AuditProductMapRisk, chi.URLParam(r, "id"), map[string]int{"riskTypeId": input.RiskTypeID}, err)
+	if err != nil {
+		writeCatalogProblem(w, r, err, "product")
+		return
+	}
+	ph.GetProductRiskTypes(w, r)
+}
+
+func (ph *ProductHandler) RemoveProductRiskType(w http.ResponseWriter, r *http.Request) {
+	riskTypeID, err := strconv.Atoi(chi.URLParam(r, "riskTypeId"))
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, "riskTypeId must be a NIC numeric id")
+		return
+	}
+	err = ph.service.RemoveProductRiskType(r.Context(), chi.URLParam(r, "id"), riskTypeID)
+	auditAdmin(r, ph.audits, domain.AuditProductUnmapRisk, chi.URLParam(r, "id"), map[string]int{"riskTypeId": riskTypeID}, err)
+	if err != nil {
+		writeCatalogProblem(w, r, err, "product risk type mapping")
+		return
+	}
+	ph.GetProductRiskTypes(w, r)
+}
+
+// productRunID is the sync run a summary belongs to, nil without one.
+func productRunID(summary *product.SyncOutput) *uuid.UUID {
+	if summary == nil {
+		return nil
+	}
+	return &summary.RunID
 }
 
-func NewProductHandler(service product.ProductService) *ProductHandler {
-	return &ProductHandler{service: service}
+func NewProductHandler(service product.ProductService, audits audit.AuditService) *ProductHandler {
+	return &ProductHandler{service: service, audits: audits}
 }
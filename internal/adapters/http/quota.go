@@ -0,0 +1,144 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/application/quota"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/rs/zerolog/log"
+)
+
+// rateLimit counts every authenticated request against the client's
+// per-minute limit and reports the client's standing in X-RateLimit headers.
+func rateLimit(service quota.QuotaService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client := clientFromContext(r.Context())
+			if client == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			status, err := service.AllowRequest(r.Context(), client)
+			setQuotaHeaders(w, "X-RateLimit", status)
+			if err != nil {
+				if errors.Is(err, quota.ErrRateLimited) {
+					writeTooManyRequests(w, r, status, fmt.Sprintf("at most %d requests per minute are allowed", status.Limit))
+					return
+				}
+				pkg.WriteProblem(w, r, http.StatusServiceUnavailable, err.Error())
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// allowPlates checks a batch against the calling client's batch size and
+// daily plate quota before any NIC work starts, and writes the problem
+// response when it is refused.
+func allowPlates(w http.ResponseWriter, r *http.Request, service quota.QuotaService, plates int) bool {
+	client := clientFromContext(r.Context())
+	if client == nil {
+		return true
+	}
+	status, err := service.AllowPlates(r.Context(), client, plates)
+	setQuotaHeaders(w, "X-PlateQuota", status)
+	if err != nil {
+		writePlatesProblem(w, r, client, plates, status, err)
+		return false
+	}
+	return true
+}
+
+// refundPlates gives back plates allowPlates counted for a batch that was
+// refused afterwards, before any NIC work started.
+func refundPlates(r *http.Request, service quota.QuotaService, plates int) {
+	client := clientFromContext(r.Context())
+	if client == nil {
+		return
+	}
+	if err := service.RefundPlates(r.Context(), client, plates); err != nil {
+		log.Error().Err(err).Str("clientId", client.ID.String()).Int("plates", plates).Msg("Error refund plate quota")
+	}
+}
+
+// checkPlates is allowPlates for dry runs: it refuses the batch as
+// allowPlates would but leaves the quota as it was, and returns the
+// client's daily plate quota, nil when unlimited.
+func checkPlates(w http.ResponseWriter, r *http.Request, service quota.QuotaService, plates int) (*domain.QuotaStatus, bool) {
+	client := clientFromContext(r.Context())
+	if client == nil {
+		return nil, true
+	}
+	status, err := service.CheckPlates(r.Context(), client, plates)
+	setQuotaHeaders(w, "X-PlateQuota", status)
+	if err != nil {
+		writePlatesProblem(w, r, client, plates, status, err)
+		return nil, false
+	}
+	return status, true
+}
+
+func writePlatesProblem(w http.ResponseWriter, r *http.Request, client *domain.APIClient, plates int, status *domain.QuotaStatus, err error) {
+	switch {
+	case errors.Is(err, quota.ErrBatchTooLarge):
+		maxBatch := client.Limits.BatchLimit()
+		pkg.WriteProblemWithExtensions(w, r, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("%d plates were submitted but at most %d are allowed per request", plates, maxBatch),
+			map[string]any{"plates": plates, "maxBatchSize": maxBatch})
+	case errors.Is(err, quota.ErrPlateQuotaExceeded):
+		writeTooManyRequests(w, r, status,
+			fmt.Sprintf("%d plates were submitted but only %d of the %d allowed today remain", plates, status.Remaining, status.Limit))
+	default:
+		pkg.WriteProblem(w, r, http.StatusServiceUnavailable, err.Error())
+	}
+}
+
+// setQuotaHeaders sets <prefix>-Limit, -Remaining and -Reset, the last as a
+// Unix time. Unlimited quotas get no headers.
+func setQuotaHeaders(w http.ResponseWriter, prefix string, status *domain.QuotaStatus) {
+	if status == nil {
+		return
+	}
+	w.Header().Set(prefix+"-Limit", strconv.Itoa(status.Limit))
+	w.Header().Set(prefix+"-Remaining", strconv.Itoa(status.Remaining))
+	w.Header().Set(prefix+"-Reset", strconv.FormatInt(status.ResetsAt.Unix(), 10))
+}
+
+func writeTooManyRequests(w http.ResponseWriter, r *http.Request, status *domain.QuotaStatus, detail string) {
+	retryAfter := int(time.Until(status.ResetsAt).Seconds() + 1)
+	w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+	pkg.WriteProblemWithExtensions(w, r, http.StatusTooManyRequests, detail, map[string]any{
+		"limit":     status.Limit,
+		"remaining": status.Remaining,
+		"resetsAt":  status.ResetsAt,
+	})
+}
+
+type QuotaHandler struct {
+	service quota.QuotaService
+}
+
+// GetQuota shows the calling client its own limits and what is left of them.
+func (qh *QuotaHandler) GetQuota(w http.ResponseWriter, r *http.Request) {
+	client := clientFromContext(r.Context())
+	if client == nil {
+		writeUnauthorized(w, r, "an API key is required")
+		return
+	}
+	result, err := qh.service.GetQuota(r.Context(), client)
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func NewQuotaHandler(service quota.QuotaService) *QuotaHandler {
+	return &QuotaHandler{service: service}
+}
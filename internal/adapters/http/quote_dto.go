@@ -0,0 +1,29 @@
+package http
+
+import "github.com/godsent-code/midtools/internal/domain"
+
+// Amounts are in cedis; rates and loadings are percentages.
+type RatesRequest struct {
+	Currency             string                       `json:"currency"`
+	BaseRate             float64                      `json:"baseRate"`
+	SumInsuredBands      []domain.SumInsuredBand      `json:"sumInsuredBands"`
+	VehicleClassLoadings []domain.VehicleClassLoading `json:"vehicleClassLoadings"`
+	MinimumPremium       domain.Money                 `json:"minimumPremium"`
+	Levies               []domain.Levy                `json:"levies"`
+}
+
+type RateTableRequest struct {
+	ProductID  int `json:"productId"`
+	RiskTypeID int `json:"riskTypeId"`
+	RatesRequest
+}
+
+type QuoteRequest struct {
+	Plate        string       `json:"plate"`
+	ProductID    int          `json:"productId"`
+	RiskTypeID   int          `json:"riskTypeId"`
+	VehicleClass string       `json:"vehicleClass"`
+	SumInsured   domain.Money `json:"sumInsured"`
+	StartDate    string       `json:"startDate"`
+	EndDate      string       `json:"endDate"`
+}
@@ -0,0 +1,188 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/godsent-code/midtools/internal/application/audit"
+	"github.com/godsent-code/midtools/internal/application/quote"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+)
+
+type QuoteHandler struct {
+	service quote.QuoteService
+	audits  audit.AuditService
+}
+
+func (qh *QuoteHandler) CreateRateTable(w http.ResponseWriter, r *http.Request) {
+	var request RateTableRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := quote.RateTableInput{
+		ProductID:  request.ProductID,
+		RiskTypeID: request.RiskTypeID,
+		RatesInput: toRatesInput(request.RatesRequest),
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := qh.service.CreateRateTable(r.Context(), input)
+	if err != nil {
+		auditAdmin(r, qh.audits, domain.AuditRateTableCreate, "", nil, err)
+		writeQuoteProblem(w, r, err, "rate table")
+		return
+	}
+	auditAdmin(r, qh.audits, domain.AuditRateTableCreate, result.ID.String(), result, nil)
+	pkg.WriteResponse(w, http.StatusCreated, result)
+}
+
+func (qh *QuoteHandler) GetRateTables(w http.ResponseWriter, r *http.Request) {
+	results, err := qh.service.GetRateTables(r.Context())
+	if err != nil {
+		writeQuoteProblem(w, r, err, "rate table")
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, results)
+}
+
+func (qh *QuoteHandler) GetRateTable(w http.ResponseWriter, r *http.Request) {
+	id, ok := uuidParam(w, r, "rate table")
+	if !ok {
+		return
+	}
+	result, err := qh.service.GetRateTable(r.Context(), id)
+	if err != nil {
+		writeQuoteProblem(w, r, err, "rate table")
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (qh *QuoteHandler) UpdateRateTable(w http.ResponseWriter, r *http.Request) {
+	id, ok := uuidParam(w, r, "rate table")
+	if !ok {
+		return
+	}
+
+	var request RatesRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := toRatesInput(request)
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := qh.service.UpdateRateTable(r.Context(), id, input)
+	auditAdmin(r, qh.audits, domain.AuditRateTableUpdate, id.String(), result, err)
+	if err != nil {
+		writeQuoteProblem(w, r, err, "rate table")
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (qh *QuoteHandler) DeleteRateTable(w http.ResponseWriter, r *http.Request) {
+	id, ok := uuidParam(w, r, "rate table")
+	if !ok {
+		return
+	}
+	err := qh.service.DeleteRateTable(r.Context(), id)
+	auditAdmin(r, qh.audits, domain.AuditRateTableDelete, id.String(), nil, err)
+	if err != nil {
+		writeQuoteProblem(w, r, err, "rate table")
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, "Rate table deleted")
+}
+
+func (qh *QuoteHandler) CreateQuote(w http.ResponseWriter, r *http.Request) {
+	var request QuoteRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := quote.QuoteInput{
+		Plate:        request.Plate,
+		ProductID:    request.ProductID,
+		RiskTypeID:   request.RiskTypeID,
+		VehicleClass: request.VehicleClass,
+		SumInsured:   request.SumInsured,
+		StartDate:    request.StartDate,
+		EndDate:      request.EndDate,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := qh.service.CreateQuote(r.Context(), input)
+	if err != nil {
+		writeQuoteProblem(w, r, err, "quote")
+		return
+	}
+	pkg.WriteResponse(w, http.StatusCreated, result)
+}
+
+func (qh *QuoteHandler) GetQuote(w http.ResponseWriter, r *http.Request) {
+	id, ok := uuidParam(w, r, "quote")
+	if !ok {
+		return
+	}
+	result, err := qh.service.GetQuote(r.Context(), id)
+	if err != nil {
+		writeQuoteProblem(w, r, err, "quote")
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func toRatesInput(request RatesRequest) quote.RatesInput {
+	return quote.RatesInput{
+		Currency:             request.Currency,
+		BaseRate:             request.BaseRate,
+		SumInsuredBands:      request.SumInsuredBands,
+		VehicleClassLoadings: request.VehicleClassLoadings,
+		MinimumPremium:       request.MinimumPremium,
+		Levies:               request.Levies,
+	}
+}
+
+// writeQuoteProblem maps rate table and quote errors to problem responses.
+// Requests naming a product or risk type that cannot be quoted are
+// unprocessable rather than not found, since the URL itself exists.
+func writeQuoteProblem(w http.ResponseWriter, r *http.Request, err error, label string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		pkg.WriteProblem(w, r, http.StatusNotFound, label+" not found")
+	case errors.Is(err, quote.ErrNoDefaultRiskType):
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrConflict):
+		pkg.WriteProblem(w, r, http.StatusConflict, "a rate table already exists for the product and risk type")
+	case errors.Is(err, domain.ErrUnknownProduct),
+		errors.Is(err, domain.ErrUnknownRiskType),
+		errors.Is(err, quote.ErrInactiveProduct),
+		errors.Is(err, quote.ErrInactiveRiskType),
+		errors.Is(err, quote.ErrRiskTypeNotAllowed),
+		errors.Is(err, quote.ErrNoRateTable):
+		pkg.WriteProblem(w, r, http.StatusUnprocessableEntity, err.Error())
+	default:
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func NewQuoteHandler(service quote.QuoteService, audits audit.AuditService) *QuoteHandler {
+	return &QuoteHandler{service: service, audits: audits}
+}
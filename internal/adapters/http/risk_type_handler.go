@@ -1,34 +1,206 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/godsent-code/midtools/internal/application/audit"
 	"github.com/godsent-code/midtools/internal/application/risk_type"
+	"github.com/godsent-code/midtools/internal/domain"
 	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
 )
 
 type RiskTypeHandler struct {
 	service risk_type.RiskTypeService
+	audits  audit.AuditService
 }
 
 func (rth *RiskTypeHandler) GetRiskTypes(w http.ResponseWriter, r *http.Request) {
-	risks, err := rth.service.GetRiskTypes(r.Context())
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	input := risk_type.RiskTypesInput{
+		AsOf:     query.Get("asOf"),
+		Search:   query.Get("q"),
+		Code:     query.Get("code"),
+		Category: query.Get("riskCategory"),
+		Active:   query.Get("active"),
+		Sort:     query.Get("sort"),
+		Limit:    limit,
+		Cursor:   query.Get("cursor"),
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	risks, err := rth.service.GetRiskTypes(r.Context(), input)
 	if err != nil {
 		pkg.WriteResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	pkg.WriteResponse(w, http.StatusOK, risks)
+	lastSynced, err := rth.service.LastSyncedAt(r.Context())
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	setLastSyncedAt(w, lastSynced)
+	setPage(w, risks.Total, risks.NextCursor)
+	pkg.WriteResponse(w, http.StatusOK, risks.RiskTypes)
 }
 
 func (rth *RiskTypeHandler) CreateRiskType(w http.ResponseWriter, r *http.Request) {
-	err := rth.service.CreateRiskType(r.Context())
+	dryRun, err := queryBool(r, "dryRun")
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	force, err := queryBool(r, "force")
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := risk_type.SyncInput{
+		TriggerSource: triggerSource(r),
+		DryRun:        dryRun,
+		Force:         force,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	summary, err := rth.service.CreateRiskType(r.Context(), input)
+	auditCatalogRun(r, rth.audits, domain.AuditRiskTypeSync, dryRun, riskTypeRunID(summary), summary, err)
+	if errors.Is(err, domain.ErrEmptyCatalog) {
+		pkg.WriteResponse(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
 	if err != nil {
 		pkg.WriteResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	pkg.WriteResponse(w, http.StatusOK, "Risk Type created")
+	pkg.WriteResponse(w, http.StatusOK, summary)
+}
+
+// ImportRiskTypes reconciles the catalog with an uploaded fixture file in the
+// NIC shape, for environments that cannot reach NIC.
+func (rth *RiskTypeHandler) ImportRiskTypes(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := queryBool(r, "dryRun")
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	force, err := queryBool(r, "force")
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	format, data, ok := readCatalogFile(w, r)
+	if !ok {
+		return
+	}
+
+	input := risk_type.ImportInput{
+		TriggerSource: triggerSource(r),
+		DryRun:        dryRun,
+		Force:         force,
+		Format:        format,
+		Data:          data,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	summary, err := rth.service.ImportRiskTypes(r.Context(), input)
+	auditCatalogRun(r, rth.audits, domain.AuditRiskTypeImport, dryRun, riskTypeRunID(summary), summary, err)
+	if err != nil {
+		writeCatalogFileProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, summary)
+}
+
+func (rth *RiskTypeHandler) ExportRiskTypes(w http.ResponseWriter, r *http.Request) {
+	format, err := domain.ParseCatalogFileFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	data, err := rth.service.ExportRiskTypes(r.Context(), format)
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeCatalogFile(w, "risk_types", format, data)
+}
+
+func (rth *RiskTypeHandler) GetSyncRuns(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	runs, err := rth.service.GetSyncRuns(r.Context(), limit)
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, runs)
+}
+
+func (rth *RiskTypeHandler) GetRiskTypeHistory(w http.ResponseWriter, r *http.Request) {
+	versions, err := rth.service.GetRiskTypeHistory(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeCatalogProblem(w, r, err, "risk type")
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, versions)
+}
+
+func (rth *RiskTypeHandler) GetRiskType(w http.ResponseWriter, r *http.Request) {
+	result, err := rth.service.GetRiskType(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeCatalogProblem(w, r, err, "risk type")
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (rth *RiskTypeHandler) GetRiskTypeByCode(w http.ResponseWriter, r *http.Request) {
+	result, err := rth.service.GetRiskTypeByCode(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		writeCatalogProblem(w, r, err, "risk type")
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (rth *RiskTypeHandler) GetRiskCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := rth.service.GetRiskCategories(r.Context())
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, categories)
+}
+
+// riskTypeRunID is the sync run a summary belongs to, nil without one.
+func riskTypeRunID(summary *risk_type.SyncOutput) *uuid.UUID {
+	if summary == nil {
+		return nil
+	}
+	return &summary.RunID
 }
 
-func NewRiskTypeHandler(service risk_type.RiskTypeService) *RiskTypeHandler {
-	return &RiskTypeHandler{service: service}
+func NewRiskTypeHandler(service risk_type.RiskTypeService, audits audit.AuditService) *RiskTypeHandler {
+	return &RiskTypeHandler{service: service, audits: audits}
 }
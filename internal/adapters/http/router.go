@@ -3,12 +3,26 @@ package http
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/godsent-code/midtools/internal/application/api_client"
+	"github.com/godsent-code/midtools/internal/application/audit"
 	"github.com/godsent-code/midtools/internal/application/brown_card_service"
+	"github.com/godsent-code/midtools/internal/application/fleet"
+	"github.com/godsent-code/midtools/internal/application/fleet_monitor"
+	"github.com/godsent-code/midtools/internal/application/idempotency"
+	"github.com/godsent-code/midtools/internal/application/issuance"
+	"github.com/godsent-code/midtools/internal/application/metering"
+	"github.com/godsent-code/midtools/internal/application/plate_rule"
 	"github.com/godsent-code/midtools/internal/application/policy_verification"
 	"github.com/godsent-code/midtools/internal/application/product"
+	"github.com/godsent-code/midtools/internal/application/quota"
+	"github.com/godsent-code/midtools/internal/application/quote"
 	"github.com/godsent-code/midtools/internal/application/risk_type"
 	"github.com/godsent-code/midtools/internal/application/sticker"
+	"github.com/godsent-code/midtools/internal/application/sticker_registry"
+	"github.com/godsent-code/midtools/internal/application/tenant"
 	"github.com/godsent-code/midtools/internal/application/ussd_check"
+	"github.com/godsent-code/midtools/internal/application/vehicle_profile"
+	"github.com/godsent-code/midtools/internal/domain"
 )
 
 func NewRouter(
@@ -18,25 +32,148 @@ func NewRouter(
 	policyVerification policy_verification.PolicyVerificationService,
 	productService product.ProductService,
 	riskTypeService risk_type.RiskTypeService,
+	vehicleProfileService vehicle_profile.VehicleProfileService,
+	fleetService fleet.FleetService,
+	fleetMonitorService fleet_monitor.FleetMonitorService,
+	quoteService quote.QuoteService,
+	plateRuleService plate_rule.PlateRuleService,
+	apiClientService api_client.APIClientService,
+	tenantService tenant.TenantService,
+	quotaService quota.QuotaService,
+	meteringService metering.MeteringService,
+	auditService audit.AuditService,
+	idempotencyService idempotency.IdempotencyService,
+	issuanceService issuance.IssuanceService,
+	stickerRegistryService sticker_registry.StickerRegistryService,
+	issuanceRunner *IssuanceRunner,
 ) *chi.Mux {
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
+	r.Use(authenticate(apiClientService))
+	r.Use(rateLimit(quotaService))
 
-	BrownCard := NewBrownCardHandler(service)
-	Sticker := NewStickerHandler(stickerService)
-	Ussd := NewUSSDCheckHandler(ussdCheckService)
-	policyVerificationService := NewPolicyVerificationHandler(policyVerification)
-	productHandler := NewProductHandler(productService)
-	riskTypeHandler := NewRiskTypeHandler(riskTypeService)
-
-	r.Post("/browncard", BrownCard.GetBrownCard)
-	r.Post("/sticker", Sticker.GetSticker)
-	r.Post("/ussd_check", Ussd.GetUSSDCheck)
-	r.Post("/policy_verification", policyVerificationService.GetPolicyVerifications)
-	r.Post("/products", productHandler.CreateProduct)
-	r.Get("/products", productHandler.GetProducts)
-	r.Post("/risk_type", riskTypeHandler.CreateRiskType)
-	r.Get("/risk_type", riskTypeHandler.GetRiskTypes)
+	BrownCard := NewBrownCardHandler(service, fleetService, quotaService, meteringService, auditService, idempotencyService, issuanceService)
+	Sticker := NewStickerHandler(stickerService, fleetService, quotaService, meteringService, auditService, idempotencyService, issuanceService, stickerRegistryService)
+	Ussd := NewUSSDCheckHandler(ussdCheckService, fleetService, quotaService, meteringService)
+	policyVerificationService := NewPolicyVerificationHandler(policyVerification, fleetService, quotaService, meteringService)
+	productHandler := NewProductHandler(productService, auditService)
+	riskTypeHandler := NewRiskTypeHandler(riskTypeService, auditService)
+	vehicleProfileHandler := NewVehicleProfileHandler(vehicleProfileService, fleetService, quotaService, meteringService)
+	fleetHandler := NewFleetHandler(fleetService)
+	fleetMonitorHandler := NewFleetMonitorHandler(fleetMonitorService, fleetService, quotaService, meteringService)
+	quoteHandler := NewQuoteHandler(quoteService, auditService)
+	plateRuleHandler := NewPlateRuleHandler(plateRuleService, auditService)
+	apiClientHandler := NewAPIClientHandler(apiClientService, auditService)
+	tenantHandler := NewTenantHandler(tenantService, auditService)
+	quotaHandler := NewQuotaHandler(quotaService)
+	usageHandler := NewUsageHandler(meteringService, auditService)
+	auditHandler := NewAuditHandler(auditService)
+	issuanceHandler := NewIssuanceHandler(issuanceService, fleetService, quotaService, auditService, issuanceRunner)
+	stickerRegistryHandler := NewStickerRegistryHandler(stickerRegistryService, auditService)
+
+	// Every route names the role it needs; see domain.Role.Grants. Viewer
+	// routes only look things up: anything that generates stickers or brown
+	// cards needs the issuer role and goes through the issuance controls.
+	viewer := r.With(requireRole(apiClientService, domain.RoleViewer))
+	issuer := r.With(requireRole(apiClientService, domain.RoleIssuer))
+	catalogAdmin := r.With(requireRole(apiClientService, domain.RoleCatalogAdmin))
+	approver := r.With(requireRole(apiClientService, domain.RoleApprover))
+	admin := r.With(requireRole(apiClientService, domain.RoleAdmin))
+
+	issuer.Post("/browncard", BrownCard.GetBrownCard)
+	issuer.Post("/sticker", Sticker.GetSticker)
+	viewer.Post("/ussd_check", Ussd.GetUSSDCheck)
+	viewer.Post("/policy_verification", policyVerificationService.GetPolicyVerifications)
+	viewer.Post("/vehicles/profile", vehicleProfileHandler.GetVehicleProfiles)
+	catalogAdmin.Post("/products", productHandler.CreateProduct)
+	viewer.Get("/products", productHandler.GetProducts)
+	viewer.Get("/products/sync_runs", productHandler.GetSyncRuns)
+	catalogAdmin.Post("/products/import", productHandler.ImportProducts)
+	viewer.Get("/products/export", productHandler.ExportProducts)
+	viewer.Get("/products/by-code/{code}", productHandler.GetProductByCode)
+	viewer.Get("/products/{id}", productHandler.GetProduct)
+	viewer.Get("/products/{id}/history", productHandler.GetProductHistory)
+	viewer.Get("/products/{id}/risk_types", productHandler.GetProductRiskTypes)
+	catalogAdmin.Post("/products/{id}/risk_types", productHandler.AddProductRiskType)
+	catalogAdmin.Delete("/products/{id}/risk_types/{riskTypeId}", productHandler.RemoveProductRiskType)
+	catalogAdmin.Post("/risk_type", riskTypeHandler.CreateRiskType)
+	viewer.Get("/risk_type", riskTypeHandler.GetRiskTypes)
+	viewer.Get("/risk_type/sync_runs", riskTypeHandler.GetSyncRuns)
+	catalogAdmin.Post("/risk_type/import", riskTypeHandler.ImportRiskTypes)
+	viewer.Get("/risk_type/export", riskTypeHandler.ExportRiskTypes)
+	viewer.Get("/risk_type/by-code/{code}", riskTypeHandler.GetRiskTypeByCode)
+	viewer.Get("/risk_type/{id}", riskTypeHandler.GetRiskType)
+	viewer.Get("/risk_type/{id}/history", riskTypeHandler.GetRiskTypeHistory)
+	viewer.Get("/risk_categories", riskTypeHandler.GetRiskCategories)
+	catalogAdmin.Post("/rate_tables", quoteHandler.CreateRateTable)
+	viewer.Get("/rate_tables", quoteHandler.GetRateTables)
+	viewer.Get("/rate_tables/{id}", quoteHandler.GetRateTable)
+	catalogAdmin.Put("/rate_tables/{id}", quoteHandler.UpdateRateTable)
+	catalogAdmin.Delete("/rate_tables/{id}", quoteHandler.DeleteRateTable)
+	issuer.Post("/quotes", quoteHandler.CreateQuote)
+	viewer.Get("/quotes/{id}", quoteHandler.GetQuote)
+	catalogAdmin.Post("/plate_rules", plateRuleHandler.CreatePlateRiskRule)
+	viewer.Get("/plate_rules", plateRuleHandler.GetPlateRiskRules)
+	catalogAdmin.Delete("/plate_rules/{id}", plateRuleHandler.DeletePlateRiskRule)
+	viewer.Get("/plates/{plate}/risk_types", plateRuleHandler.SuggestRiskTypes)
+	viewer.Get("/quota", quotaHandler.GetQuota)
+	viewer.Get("/usage/statement", usageHandler.GetOwnStatement)
+	issuer.Post("/issuance_requests", issuanceHandler.SubmitIssuanceRequest)
+	viewer.Get("/issuance_requests", issuanceHandler.GetIssuanceRequests)
+	viewer.Get("/issuance_requests/{id}", issuanceHandler.GetIssuanceRequest)
+	approver.Post("/issuance_requests/{id}/approve", issuanceHandler.ApproveIssuanceRequest)
+	approver.Post("/issuance_requests/{id}/reject", issuanceHandler.RejectIssuanceRequest)
+	viewer.Get("/sticker_registry", stickerRegistryHandler.SearchStickers)
+	viewer.Get("/sticker_registry/reconciliation", stickerRegistryHandler.Reconcile)
+	viewer.Get("/sticker_registry/{id}", stickerRegistryHandler.GetSticker)
+	issuer.Put("/sticker_registry/{id}/status", stickerRegistryHandler.SetStickerStatus)
+
+	r.Route("/fleets", func(r chi.Router) {
+		viewer := r.With(requireRole(apiClientService, domain.RoleViewer))
+		issuer := r.With(requireRole(apiClientService, domain.RoleIssuer))
+
+		issuer.Post("/", fleetHandler.CreateFleet)
+		viewer.Get("/", fleetHandler.GetFleets)
+		viewer.Get("/{id}", fleetHandler.GetFleet)
+		issuer.Put("/{id}", fleetHandler.UpdateFleet)
+		issuer.Delete("/{id}", fleetHandler.DeleteFleet)
+		viewer.Get("/{id}/vehicles", fleetHandler.GetFleetVehicles)
+		issuer.Post("/{id}/vehicles", fleetHandler.AddFleetVehicles)
+		issuer.Post("/{id}/vehicles/import", fleetHandler.ImportFleetVehicles)
+		issuer.Delete("/{id}/vehicles/{plate}", fleetHandler.RemoveFleetVehicle)
+		viewer.Post("/{id}/check", fleetMonitorHandler.CheckFleet)
+		viewer.Get("/{id}/status", fleetMonitorHandler.GetVehicleStatuses)
+		viewer.Get("/{id}/alerts", fleetMonitorHandler.GetOpenAlerts)
+		issuer.Post("/{id}/alerts/{alertId}/resolve", fleetMonitorHandler.ResolveAlert)
+	})
+
+	admin.Route("/api_clients", func(r chi.Router) {
+		r.Post("/", apiClientHandler.CreateAPIClient)
+		r.Get("/", apiClientHandler.GetAPIClients)
+		r.Get("/denials", apiClientHandler.GetAccessDenials)
+		r.Get("/{id}", apiClientHandler.GetAPIClient)
+		r.Patch("/{id}", apiClientHandler.UpdateAPIClient)
+		r.Post("/{id}/rotate", apiClientHandler.RotateAPIClientKey)
+		r.Post("/{id}/revoke", apiClientHandler.RevokeAPIClient)
+		r.Put("/{id}/tenant", apiClientHandler.SetAPIClientTenant)
+		r.Put("/{id}/limits", apiClientHandler.SetAPIClientLimits)
+	})
+
+	admin.Route("/tenants", func(r chi.Router) {
+		r.Post("/", tenantHandler.CreateTenant)
+		r.Get("/", tenantHandler.GetTenants)
+		r.Get("/{id}", tenantHandler.GetTenant)
+		r.Put("/{id}", tenantHandler.UpdateTenant)
+	})
+
+	admin.Route("/usage", func(r chi.Router) {
+		r.Get("/statements", usageHandler.GetStatements)
+		r.Get("/statements/export", usageHandler.ExportStatements)
+		r.Get("/prices", usageHandler.GetServicePrices)
+		r.Put("/prices/{service}", usageHandler.SetServicePrice)
+	})
+
+	admin.Get("/audit", auditHandler.GetEntries)
 	return r
 
 }
@@ -1,5 +1,9 @@
 package http
 
 type StickerRequest struct {
-	Cars string `json:"cars"`
+	Cars               string `json:"cars"`
+	FleetID            string `json:"fleetId"`
+	RiskTypeID         int    `json:"riskTypeId"`
+	Branch             string `json:"branch"`
+	OverridePlateRules bool   `json:"overridePlateRules"`
 }
@@ -1,43 +1,138 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 
+	"github.com/godsent-code/midtools/internal/application/audit"
+	"github.com/godsent-code/midtools/internal/application/fleet"
+	"github.com/godsent-code/midtools/internal/application/idempotency"
+	"github.com/godsent-code/midtools/internal/application/issuance"
+	"github.com/godsent-code/midtools/internal/application/metering"
+	"github.com/godsent-code/midtools/internal/application/quota"
 	"github.com/godsent-code/midtools/internal/application/sticker"
+	"github.com/godsent-code/midtools/internal/application/sticker_registry"
+	"github.com/godsent-code/midtools/internal/domain"
 	"github.com/godsent-code/midtools/pkg"
 )
 
 type StickerHandler struct {
-	service sticker.StickerService
+	service   sticker.StickerService
+	fleets    fleet.FleetService
+	quotas    quota.QuotaService
+	usage     metering.MeteringService
+	audits    audit.AuditService
+	keys      idempotency.IdempotencyService
+	issuances issuance.IssuanceService
+	registry  sticker_registry.StickerRegistryService
 }
 
 func (ach *StickerHandler) GetSticker(w http.ResponseWriter, r *http.Request) {
 	var request StickerRequest
 
 	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
-		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	preview, err := queryBool(r, "dryRun")
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	cars, ok := resolveCarsProblem(w, r, ach.fleets, request.Cars, request.FleetID)
+	if !ok {
 		return
 	}
 
 	br := sticker.StickerInput{
-		Cars: request.Cars,
+		Cars:               cars,
+		RiskTypeID:         request.RiskTypeID,
+		OverridePlateRules: request.OverridePlateRules,
 	}
 
 	if err := br.Validate(); err != nil {
-		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := sticker_registry.ValidateBranch(request.Branch); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := ach.service.CheckRiskType(r.Context(), request.RiskTypeID); err != nil {
+		if errors.Is(err, domain.ErrUnknownRiskType) {
+			pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	plates := len(pkg.SplitCars(cars))
+	if !checkDirectIssuance(w, r, ach.issuances, ach.quotas, plates) {
+		return
+	}
+
+	if preview {
+		dryRun(w, r, ach.keys, ach.quotas, ach.usage, domain.ServiceSticker, request, plates, func(ctx context.Context) (*domain.DryRun, error) {
+			return ach.service.PlanSticker(ctx, br)
+		})
+		return
+	}
+
+	idem, ok := beginIdempotent(w, r, ach.keys, request)
+	if !ok {
 		return
 	}
 
-	results, err := ach.service.GetSticker(r.Context(), br)
+	// The plate quota is taken first as it is the one a client most often
+	// runs out of; the direct issuance allowance is only counted for batches
+	// it lets through, and a refusal there gives the plates back.
+	if !allowPlates(w, r, ach.quotas, plates) {
+		idem.release()
+		return
+	}
+	if !allowDirectIssuance(w, r, ach.issuances, ach.quotas, plates) {
+		refundPlates(r, ach.quotas, plates)
+		idem.release()
+		return
+	}
+
+	// The batch runs to the end even if the client goes away: NIC may have
+	// issued stickers already, and the outcome is what a retry replays.
+	ctx, record := meter(r, ach.usage, domain.ServiceSticker)
+	results, err := ach.service.GetSticker(context.WithoutCancel(ctx), br)
+	issued := 0
+	for _, result := range results {
+		if result.Status {
+			issued++
+		}
+	}
+	record(plates, issued)
+	auditIssuance(r, ach.audits, domain.AuditStickerIssue, pkg.SplitCars(cars), issued, results, err)
 	if err != nil {
-		pkg.WriteResponse(w, http.StatusInternalServerError, err.Error())
+		idem.release()
+		if errors.Is(err, domain.ErrUnknownRiskType) {
+			pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
+	registered := sticker_registry.RegistryRecord{Branch: request.Branch}
+	if client := clientFromContext(r.Context()); client != nil {
+		registered.ClientID, registered.TenantID = &client.ID, client.TenantID
+	}
+	registerStickers(r.Context(), ach.registry, registered, results)
+	idem.complete(http.StatusOK, results)
 	pkg.WriteResponse(w, http.StatusOK, results)
 }
 
-func NewStickerHandler(service sticker.StickerService) *StickerHandler {
-	return &StickerHandler{service: service}
+func NewStickerHandler(service sticker.StickerService, fleets fleet.FleetService, quotas quota.QuotaService, usage metering.MeteringService, audits audit.AuditService, keys idempotency.IdempotencyService, issuances issuance.IssuanceService, registry sticker_registry.StickerRegistryService) *StickerHandler {
+	return &StickerHandler{service: service, fleets: fleets, quotas: quotas, usage: usage, audits: audits, keys: keys, issuances: issuances, registry: registry}
 }
@@ -0,0 +1,6 @@
+package http
+
+type StickerStatusRequest struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/godsent-code/midtools/internal/application/audit"
+	"github.com/godsent-code/midtools/internal/application/sticker"
+	"github.com/godsent-code/midtools/internal/application/sticker_registry"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+)
+
+// registerStickers records the stickers NIC generated in the registry,
+// issued to record's client. Results that failed are left out.
+func registerStickers(ctx context.Context, service sticker_registry.StickerRegistryService, record sticker_registry.RegistryRecord, results []sticker.StickerOutput) {
+	for _, result := range results {
+		if result.Status {
+			record.Stickers = append(record.Stickers, domain.IssuedSticker{
+				Plate:         result.CarNumber,
+				StickerNumber: result.StickerNumber,
+				StickerLink:   result.StickerLink,
+			})
+		}
+	}
+	service.Record(ctx, record)
+}
+
+type StickerRegistryHandler struct {
+	service sticker_registry.StickerRegistryService
+	audits  audit.AuditService
+}
+
+func (sh *StickerRegistryHandler) SearchStickers(w http.ResponseWriter, r *http.Request) {
+	client := clientFromContext(r.Context())
+	if client == nil {
+		writeUnauthorized(w, r, "an API key is required")
+		return
+	}
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	query := r.URL.Query()
+	input := sticker_registry.SearchInput{
+		Plate:         query.Get("plate"),
+		StickerNumber: query.Get("number"),
+		Status:        query.Get("status"),
+		Branch:        query.Get("branch"),
+		Limit:         limit,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	results, err := sh.service.Search(r.Context(), client, input)
+	if err != nil {
+		writeStickerRegistryProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, results)
+}
+
+func (sh *StickerRegistryHandler) GetSticker(w http.ResponseWriter, r *http.Request) {
+	client := clientFromContext(r.Context())
+	if client == nil {
+		writeUnauthorized(w, r, "an API key is required")
+		return
+	}
+	id, ok := uuidParam(w, r, "sticker")
+	if !ok {
+		return
+	}
+	result, err := sh.service.Get(r.Context(), client, id)
+	if err != nil {
+		writeStickerRegistryProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (sh *StickerRegistryHandler) SetStickerStatus(w http.ResponseWriter, r *http.Request) {
+	client := clientFromContext(r.Context())
+	if client == nil {
+		writeUnauthorized(w, r, "an API key is required")
+		return
+	}
+	id, ok := uuidParam(w, r, "sticker")
+	if !ok {
+		return
+	}
+	var request StickerStatusRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	input := sticker_registry.StatusInput{Status: request.Status, Note: request.Note}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	result, err := sh.service.SetStatus(r.Context(), client, id, input)
+	auditAdmin(r, sh.audits, domain.AuditStickerSetStatus, id.String(), result, err)
+	if err != nil {
+		writeStickerRegistryProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (sh *StickerRegistryHandler) Reconcile(w http.ResponseWriter, r *http.Request) {
+	client := clientFromContext(r.Context())
+	if client == nil {
+		writeUnauthorized(w, r, "an API key is required")
+		return
+	}
+	result, err := sh.service.Reconcile(r.Context(), client)
+	if err != nil {
+		writeStickerRegistryProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func writeStickerRegistryProblem(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		pkg.WriteProblem(w, r, http.StatusNotFound, "sticker not found")
+	case errors.Is(err, sticker_registry.ErrNoteRequired):
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+	default:
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func NewStickerRegistryHandler(service sticker_registry.StickerRegistryService, audits audit.AuditService) *StickerRegistryHandler {
+	return &StickerRegistryHandler{service: service, audits: audits}
+}
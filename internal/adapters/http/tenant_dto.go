@@ -0,0 +1,14 @@
+package http
+
+type TenantRequest struct {
+	Name           string              `json:"name"`
+	NICEnvironment string              `json:"nicEnvironment"`
+	NICAPIKey      string              `json:"nicApiKey"`
+	NICRateLimit   NICRateLimitRequest `json:"nicRateLimit"`
+}
+
+type NICRateLimitRequest struct {
+	RequestIntervalMs int `json:"requestIntervalMs"`
+	Burst             int `json:"burst"`
+	Workers           int `json:"workers"`
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/godsent-code/midtools/internal/application/audit"
+	"github.com/godsent-code/midtools/internal/application/tenant"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+)
+
+type TenantHandler struct {
+	service tenant.TenantService
+	audits  audit.AuditService
+}
+
+func (th *TenantHandler) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	input, ok := readTenantInput(w, r)
+	if !ok {
+		return
+	}
+	result, err := th.service.CreateTenant(r.Context(), input)
+	if err != nil {
+		auditAdmin(r, th.audits, domain.AuditTenantCreate, input.Name, nil, err)
+		writeTenantProblem(w, r, err)
+		return
+	}
+	auditAdmin(r, th.audits, domain.AuditTenantCreate, result.ID.String(), result, nil)
+	pkg.WriteResponse(w, http.StatusCreated, result)
+}
+
+func (th *TenantHandler) GetTenants(w http.ResponseWriter, r *http.Request) {
+	results, err := th.service.GetTenants(r.Context())
+	if err != nil {
+		writeTenantProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, results)
+}
+
+func (th *TenantHandler) GetTenant(w http.ResponseWriter, r *http.Request) {
+	id, ok := uuidParam(w, r, "tenant")
+	if !ok {
+		return
+	}
+	result, err := th.service.GetTenant(r.Context(), id)
+	if err != nil {
+		writeTenantProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (th *TenantHandler) UpdateTenant(w http.ResponseWriter, r *http.Request) {
+	id, ok := uuidParam(w, r, "tenant")
+	if !ok {
+		return
+	}
+	input, ok := readTenantInput(w, r)
+	if !ok {
+		return
+	}
+	result, err := th.service.UpdateTenant(r.Context(), id, input)
+	auditAdmin(r, th.audits, domain.AuditTenantUpdate, id.String(), result, err)
+	if err != nil {
+		writeTenantProblem(w, r, err)
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func readTenantInput(w http.ResponseWriter, r *http.Request) (tenant.TenantInput, bool) {
+	var request TenantRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return tenant.TenantInput{}, false
+	}
+	input := tenant.TenantInput{
+		Name:              request.Name,
+		NICEnvironment:    request.NICEnvironment,
+		NICAPIKey:         request.NICAPIKey,
+		RequestIntervalMs: request.NICRateLimit.RequestIntervalMs,
+		Burst:             request.NICRateLimit.Burst,
+		Workers:           request.NICRateLimit.Workers,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return tenant.TenantInput{}, false
+	}
+	return input, true
+}
+
+func writeTenantProblem(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		pkg.WriteProblem(w, r, http.StatusNotFound, "tenant not found")
+	case errors.Is(err, domain.ErrConflict):
+		pkg.WriteProblem(w, r, http.StatusConflict, "a tenant with that name already exists")
+	case errors.Is(err, tenant.ErrNICAPIKeyRequired):
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+	default:
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func NewTenantHandler(service tenant.TenantService, audits audit.AuditService) *TenantHandler {
+	return &TenantHandler{service: service, audits: audits}
+}
@@ -0,0 +1,154 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/godsent-code/midtools/internal/application/audit"
+	"github.com/godsent-code/midtools/internal/application/metering"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+)
+
+// meter starts metering a vehicle service request. NIC calls made under the
+// returned context are counted, and record stores them with the plates
+// submitted and the documents issued once the service returns, whether it
+// succeeded or not. Requests without a client are not metered.
+func meter(r *http.Request, service metering.MeteringService, name domain.MeteredService) (context.Context, func(plates, issued int)) {
+	var clientID *uuid.UUID
+	if client := clientFromContext(r.Context()); client != nil {
+		clientID = &client.ID
+	}
+	return meterFor(r.Context(), service, name, clientID)
+}
+
+// meterFor meters on behalf of clientID rather than the caller, as approved
+// issuance requests are billed to the client that submitted them.
+func meterFor(ctx context.Context, service metering.MeteringService, name domain.MeteredService, clientID *uuid.UUID) (context.Context, func(plates, issued int)) {
+	ctx, calls := domain.WithNICCallCounter(ctx)
+	return ctx, func(plates, issued int) {
+		if clientID == nil {
+			return
+		}
+		service.RecordUsage(ctx, domain.UsageRecord{
+			ClientID: *clientID,
+			Service:  name,
+			Plates:   plates,
+			NICCalls: calls.Calls(),
+			Issued:   issued,
+		})
+	}
+}
+
+type UsageHandler struct {
+	service metering.MeteringService
+	audits  audit.AuditService
+}
+
+func (uh *UsageHandler) GetStatements(w http.ResponseWriter, r *http.Request) {
+	input := metering.StatementInput{
+		Month:    r.URL.Query().Get("month"),
+		ClientID: r.URL.Query().Get("clientId"),
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	results, err := uh.service.GetStatements(r.Context(), input)
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, results)
+}
+
+func (uh *UsageHandler) ExportStatements(w http.ResponseWriter, r *http.Request) {
+	input := metering.StatementInput{
+		Month:    r.URL.Query().Get("month"),
+		ClientID: r.URL.Query().Get("clientId"),
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	data, err := uh.service.ExportStatements(r.Context(), input)
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	name := "statements-" + input.Month + ".csv"
+	if input.Month == "" {
+		name = "statements.csv"
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// GetOwnStatement shows the calling client its statement for a month.
+func (uh *UsageHandler) GetOwnStatement(w http.ResponseWriter, r *http.Request) {
+	client := clientFromContext(r.Context())
+	if client == nil {
+		writeUnauthorized(w, r, "an API key is required")
+		return
+	}
+	input := metering.StatementInput{Month: r.URL.Query().Get("month")}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	result, err := uh.service.GetClientStatement(r.Context(), client, input)
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func (uh *UsageHandler) GetServicePrices(w http.ResponseWriter, r *http.Request) {
+	results, err := uh.service.GetServicePrices(r.Context())
+	if err != nil {
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, results)
+}
+
+func (uh *UsageHandler) SetServicePrice(w http.ResponseWriter, r *http.Request) {
+	var request ServicePriceRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	input := metering.ServicePriceInput{
+		PlatePrice:   request.PlatePrice,
+		NICCallPrice: request.NICCallPrice,
+		IssuedPrice:  request.IssuedPrice,
+	}
+	if err := input.Validate(); err != nil {
+		pkg.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	service := chi.URLParam(r, "service")
+	result, err := uh.service.SetServicePrice(r.Context(), service, input)
+	auditAdmin(r, uh.audits, domain.AuditServicePriceSet, service, result, err)
+	if err != nil {
+		if errors.Is(err, metering.ErrUnknownService) {
+			pkg.WriteProblem(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		pkg.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, result)
+}
+
+func NewUsageHandler(service metering.MeteringService, audits audit.AuditService) *UsageHandler {
+	return &UsageHandler{service: service, audits: audits}
+}
@@ -0,0 +1,10 @@
+package http
+
+import "github.com/godsent-code/midtools/internal/domain"
+
+// ServicePriceRequest holds a service's unit prices in cedis.
+type ServicePriceRequest struct {
+	PlatePrice   domain.Money `json:"platePrice"`
+	NICCallPrice domain.Money `json:"nicCallPrice"`
+	IssuedPrice  domain.Money `json:"issuedPrice"`
+}
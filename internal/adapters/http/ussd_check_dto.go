@@ -1,5 +1,6 @@
 package http
 
 type USSDCheckRequest struct {
-	Cars string `json:"cars"`
+	Cars    string `json:"cars"`
+	FleetID string `json:"fleetId"`
 }
@@ -5,12 +5,19 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/godsent-code/midtools/internal/application/fleet"
+	"github.com/godsent-code/midtools/internal/application/metering"
+	"github.com/godsent-code/midtools/internal/application/quota"
 	"github.com/godsent-code/midtools/internal/application/ussd_check"
+	"github.com/godsent-code/midtools/internal/domain"
 	"github.com/godsent-code/midtools/pkg"
 )
 
 type USSDCheckHandler struct {
 	service ussd_check.USSDCheckService
+	fleets  fleet.FleetService
+	quotas  quota.QuotaService
+	usage   metering.MeteringService
 }
 
 func (usd *USSDCheckHandler) GetUSSDCheck(w http.ResponseWriter, r *http.Request) {
@@ -21,8 +28,13 @@ func (usd *USSDCheckHandler) GetUSSDCheck(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	cars, ok := resolveCars(r.Context(), w, usd.fleets, request.Cars, request.FleetID)
+	if !ok {
+		return
+	}
+
 	br := ussd_check.USSDCheckInput{
-		Cars: request.Cars,
+		Cars: cars,
 	}
 
 	if err := br.Validate(); err != nil {
@@ -30,7 +42,14 @@ func (usd *USSDCheckHandler) GetUSSDCheck(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	results, err := usd.service.GetUSSDCheck(r.Context(), br)
+	plates := len(pkg.SplitCars(cars))
+	if !allowPlates(w, r, usd.quotas, plates) {
+		return
+	}
+
+	ctx, record := meter(r, usd.usage, domain.ServiceUSSDCheck)
+	results, err := usd.service.GetUSSDCheck(ctx, br)
+	record(plates, 0)
 	if err != nil {
 		pkg.WriteResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -38,6 +57,6 @@ func (usd *USSDCheckHandler) GetUSSDCheck(w http.ResponseWriter, r *http.Request
 	pkg.WriteResponse(w, http.StatusOK, results)
 }
 
-func NewUSSDCheckHandler(service ussd_check.USSDCheckService) *USSDCheckHandler {
-	return &USSDCheckHandler{service: service}
+func NewUSSDCheckHandler(service ussd_check.USSDCheckService, fleets fleet.FleetService, quotas quota.QuotaService, usage metering.MeteringService) *USSDCheckHandler {
+	return &USSDCheckHandler{service: service, fleets: fleets, quotas: quotas, usage: usage}
 }
@@ -0,0 +1,7 @@
+package http
+
+type VehicleProfileRequest struct {
+	Cars         string `json:"cars"`
+	FleetID      string `json:"fleetId"`
+	ExpiringDays int    `json:"expiringDays"`
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/godsent-code/midtools/internal/application/fleet"
+	"github.com/godsent-code/midtools/internal/application/metering"
+	"github.com/godsent-code/midtools/internal/application/quota"
+	"github.com/godsent-code/midtools/internal/application/vehicle_profile"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+)
+
+type VehicleProfileHandler struct {
+	service vehicle_profile.VehicleProfileService
+	fleets  fleet.FleetService
+	quotas  quota.QuotaService
+	usage   metering.MeteringService
+}
+
+func (vph *VehicleProfileHandler) GetVehicleProfiles(w http.ResponseWriter, r *http.Request) {
+	var request VehicleProfileRequest
+
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1048576)).Decode(&request); err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	cars, ok := resolveCars(r.Context(), w, vph.fleets, request.Cars, request.FleetID)
+	if !ok {
+		return
+	}
+
+	vp := vehicle_profile.VehicleProfileInput{
+		Cars:         cars,
+		ExpiringDays: request.ExpiringDays,
+	}
+
+	if err := vp.Validate(); err != nil {
+		pkg.WriteResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	plates := len(pkg.SplitCars(cars))
+	if !allowPlates(w, r, vph.quotas, plates) {
+		return
+	}
+
+	ctx, record := meter(r, vph.usage, domain.ServiceVehicleProfile)
+	results, err := vph.service.GetVehicleProfiles(ctx, clientFromContext(r.Context()), vp)
+	record(plates, 0)
+	if err != nil {
+		pkg.WriteResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	pkg.WriteResponse(w, http.StatusOK, results)
+}
+
+func NewVehicleProfileHandler(service vehicle_profile.VehicleProfileService, fleets fleet.FleetService, quotas quota.QuotaService, usage metering.MeteringService) *VehicleProfileHandler {
+	return &VehicleProfileHandler{service: service, fleets: fleets, quotas: quotas, usage: usage}
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+// AdvisoryLock elects a single leader among replicas sharing a database. The
+// holder keeps a session-level pg_advisory_lock on a dedicated connection, so
+// the lock is released by Postgres if the process dies or the connection
+// drops, letting another replica take over.
+type AdvisoryLock struct {
+	pool *pgxpool.Pool
+	name string
+	key  int64
+
+	mu   sync.Mutex
+	conn *pgxpool.Conn
+}
+
+// TryAcquire reports whether this process holds the lock, taking it if it is
+// free. It never blocks waiting for another holder.
+func (al *AdvisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	if al.conn != nil {
+		if err := al.conn.Ping(ctx); err == nil {
+			return true, nil
+		}
+		log.Warn().Str("lock", al.name).Msg("Lost connection holding advisory lock")
+		al.conn.Release()
+		al.conn = nil
+	}
+
+	conn, err := al.pool.Acquire(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error acquire connection")
+		return false, err
+	}
+	var acquired bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", al.key).Scan(&acquired); err != nil {
+		log.Error().Err(err).Msg("Error try advisory lock")
+		conn.Release()
+		return false, err
+	}
+	if !acquired {
+		conn.Release()
+		return false, nil
+	}
+	al.conn = conn
+	log.Info().Str("lock", al.name).Msg("Acquired advisory lock")
+	return true, nil
+}
+
+// Release gives up the lock if this process holds it.
+func (al *AdvisoryLock) Release(ctx context.Context) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	if al.conn == nil {
+		return
+	}
+	if _, err := al.conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", al.key); err != nil {
+		log.Error().Err(err).Msg("Error advisory unlock")
+	}
+	al.conn.Release()
+	al.conn = nil
+}
+
+func NewAdvisoryLock(pool *pgxpool.Pool, name string) *AdvisoryLock {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return &AdvisoryLock{pool: pool, name: name, key: int64(h.Sum64())}
+}
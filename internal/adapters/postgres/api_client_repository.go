@@ -0,0 +1,263 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+type APIClientRepository struct {
+	q *pgxpool.Pool
+}
+
+func (ar *APIClientRepository) CreateAPIClient(ctx context.Context, client domain.APIClient) (*domain.APIClient, error) {
+	q := sqlc.New(ar.q)
+	result, err := q.CreateAPIClient(ctx, sqlc.CreateAPIClientParams{
+		Name:              client.Name,
+		KeyPrefix:         client.KeyPrefix,
+		KeyHash:           client.KeyHash,
+		Roles:             roleStrings(client.Roles),
+		TenantID:          optionalUUIDPtr(client.TenantID),
+		RequestsPerMinute: int32(client.Limits.RequestsPerMinute),
+		PlatesPerDay:      int32(client.Limits.PlatesPerDay),
+		MaxBatchSize:      int32(client.Limits.MaxBatchSize),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, domain.ErrConflict
+		}
+		log.Error().Err(err).Msg("Error create api client")
+		return nil, err
+	}
+	return toDomainAPIClient(result), nil
+}
+
+func (ar *APIClientRepository) GetAPIClients(ctx context.Context) ([]*domain.APIClient, error) {
+	q := sqlc.New(ar.q)
+	results, err := q.GetAPIClients(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error get api clients")
+		return nil, err
+	}
+	clients := make([]*domain.APIClient, len(results))
+	for i, result := range results {
+		clients[i] = toDomainAPIClient(result)
+	}
+	return clients, nil
+}
+
+func (ar *APIClientRepository) GetAPIClient(ctx context.Context, id uuid.UUID) (*domain.APIClient, error) {
+	q := sqlc.New(ar.q)
+	result, err := q.GetAPIClient(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error get api client")
+		return nil, err
+	}
+	return toDomainAPIClient(result), nil
+}
+
+func (ar *APIClientRepository) GetAPIClientByKeyPrefix(ctx context.Context, prefix string) (*domain.APIClient, error) {
+	q := sqlc.New(ar.q)
+	result, err := q.GetAPIClientByKeyPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error get api client by key prefix")
+		return nil, err
+	}
+	return toDomainAPIClient(result), nil
+}
+
+// RotateAPIClientKey replaces a client's key. Revoked clients are reported as
+// not found, since they cannot be given a new key.
+func (ar *APIClientRepository) RotateAPIClientKey(ctx context.Context, id uuid.UUID, prefix, hash string) (*domain.APIClient, error) {
+	q := sqlc.New(ar.q)
+	result, err := q.RotateAPIClientKey(ctx, sqlc.RotateAPIClientKeyParams{
+		KeyPrefix: prefix,
+		KeyHash:   hash,
+		ID:        id,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error rotate api client key")
+		return nil, err
+	}
+	return toDomainAPIClient(result), nil
+}
+
+func (ar *APIClientRepository) RevokeAPIClient(ctx context.Context, id uuid.UUID) (*domain.APIClient, error) {
+	q := sqlc.New(ar.q)
+	result, err := q.RevokeAPIClient(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error revoke api client")
+		return nil, err
+	}
+	return toDomainAPIClient(result), nil
+}
+
+// SetAPIClientTenant moves a client to a tenant, or back to the configured NIC
+// account when tenantID is nil.
+func (ar *APIClientRepository) SetAPIClientTenant(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) (*domain.APIClient, error) {
+	q := sqlc.New(ar.q)
+	result, err := q.SetAPIClientTenant(ctx, sqlc.SetAPIClientTenantParams{
+		TenantID: optionalUUIDPtr(tenantID),
+		ID:       id,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error set api client tenant")
+		return nil, err
+	}
+	return toDomainAPIClient(result), nil
+}
+
+func (ar *APIClientRepository) SetAPIClientRoles(ctx context.Context, id uuid.UUID, roles []domain.Role) (*domain.APIClient, error) {
+	q := sqlc.New(ar.q)
+	result, err := q.SetAPIClientRoles(ctx, sqlc.SetAPIClientRolesParams{
+		Roles: roleStrings(roles),
+		ID:    id,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error set api client roles")
+		return nil, err
+	}
+	return toDomainAPIClient(result), nil
+}
+
+func (ar *APIClientRepository) SetAPIClientLimits(ctx context.Context, id uuid.UUID, limits domain.ClientLimits) (*domain.APIClient, error) {
+	q := sqlc.New(ar.q)
+	result, err := q.SetAPIClientLimits(ctx, sqlc.SetAPIClientLimitsParams{
+		RequestsPerMinute: int32(limits.RequestsPerMinute),
+		PlatesPerDay:      int32(limits.PlatesPerDay),
+		MaxBatchSize:      int32(limits.MaxBatchSize),
+		ID:                id,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error set api client limits")
+		return nil, err
+	}
+	return toDomainAPIClient(result), nil
+}
+
+func (ar *APIClientRepository) TouchAPIClient(ctx context.Context, id uuid.UUID) error {
+	q := sqlc.New(ar.q)
+	if err := q.TouchAPIClient(ctx, id); err != nil {
+		log.Error().Err(err).Msg("Error touch api client")
+		return err
+	}
+	return nil
+}
+
+func (ar *APIClientRepository) RecordAccessDenial(ctx context.Context, denial domain.AccessDenial) error {
+	q := sqlc.New(ar.q)
+	if err := q.CreateAccessDenial(ctx, sqlc.CreateAccessDenialParams{
+		ClientID:     denial.ClientID,
+		Method:       denial.Method,
+		Path:         denial.Path,
+		RequiredRole: string(denial.RequiredRole),
+		Roles:        roleStrings(denial.Roles),
+	}); err != nil {
+		log.Error().Err(err).Msg("Error create access denial")
+		return err
+	}
+	return nil
+}
+
+// GetAccessDenials lists the latest denials, newest first, for one client or
+// for all of them when clientID is uuid.Nil.
+func (ar *APIClientRepository) GetAccessDenials(ctx context.Context, clientID uuid.UUID, limit int) ([]*domain.AccessDenial, error) {
+	q := sqlc.New(ar.q)
+	results, err := q.GetAccessDenials(ctx, sqlc.GetAccessDenialsParams{
+		ClientID: optionalUUID(clientID),
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error get access denials")
+		return nil, err
+	}
+	denials := make([]*domain.AccessDenial, len(results))
+	for i, result := range results {
+		denials[i] = &domain.AccessDenial{
+			ID:           result.ID,
+			ClientID:     result.ClientID,
+			ClientName:   result.ClientName,
+			Method:       result.Method,
+			Path:         result.Path,
+			RequiredRole: domain.Role(result.RequiredRole),
+			Roles:        toDomainRoles(result.Roles),
+			CreatedAt:    result.CreatedAt.Time,
+		}
+	}
+	return denials, nil
+}
+
+func toDomainAPIClient(c sqlc.ApiClients) *domain.APIClient {
+	return &domain.APIClient{
+		ID:        c.ID,
+		Name:      c.Name,
+		KeyPrefix: c.KeyPrefix,
+		KeyHash:   c.KeyHash,
+		Roles:     toDomainRoles(c.Roles),
+		TenantID:  uuidPtr(c.TenantID),
+		Limits: domain.ClientLimits{
+			RequestsPerMinute: int(c.RequestsPerMinute),
+			PlatesPerDay:      int(c.PlatesPerDay),
+			MaxBatchSize:      int(c.MaxBatchSize),
+		},
+		CreatedAt:  c.CreatedAt.Time,
+		LastUsedAt: timePtr(c.LastUsedAt),
+		RotatedAt:  timePtr(c.RotatedAt),
+		RevokedAt:  timePtr(c.RevokedAt),
+	}
+}
+
+func optionalUUIDPtr(id *uuid.UUID) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{}
+	}
+	return optionalUUID(*id)
+}
+
+func roleStrings(roles []domain.Role) []string {
+	values := make([]string, len(roles))
+	for i, r := range roles {
+		values[i] = string(r)
+	}
+	return values
+}
+
+func toDomainRoles(values []string) []domain.Role {
+	roles := make([]domain.Role, len(values))
+	for i, v := range values {
+		roles[i] = domain.Role(v)
+	}
+	return roles
+}
+
+func NewAPIClientRepository(pool *pgxpool.Pool) *APIClientRepository {
+	return &APIClientRepository{q: pool}
+}
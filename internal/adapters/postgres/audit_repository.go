@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+type AuditRepository struct {
+	q *pgxpool.Pool
+}
+
+// AppendAuditEntry numbers, timestamps and chains entry onto the end of the
+// log. Appends are serialised by a transaction-level advisory lock, so
+// replicas writing at once still produce one unbroken chain.
+func (ar *AuditRepository) AppendAuditEntry(ctx context.Context, entry domain.AuditEntry) (*domain.AuditEntry, error) {
+	tx, err := ar.q.Begin(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error begin audit transaction")
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	q := sqlc.New(ar.q).WithTx(tx)
+
+	if err := q.LockAuditLog(ctx); err != nil {
+		log.Error().Err(err).Msg("Error lock audit log")
+		return nil, err
+	}
+	entry.Seq, entry.PrevHash = 1, domain.AuditGenesisHash
+	last, err := q.GetLastAuditEntry(ctx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		log.Error().Err(err).Msg("Error get last audit entry")
+		return nil, err
+	}
+	if err == nil {
+		entry.Seq, entry.PrevHash = last.Seq+1, last.Hash
+	}
+	entry.OccurredAt = time.Now().UTC().Truncate(time.Microsecond)
+	if entry.Plates == nil {
+		entry.Plates = make([]string, 0)
+	}
+	if entry.Detail == nil {
+		entry.Detail = []byte("null")
+	}
+	entry.Hash = entry.ComputeHash()
+
+	result, err := q.CreateAuditEntry(ctx, sqlc.CreateAuditEntryParams{
+		Seq:        entry.Seq,
+		OccurredAt: optionalTimestamptz(entry.OccurredAt),
+		ClientID:   optionalUUIDPtr(entry.ClientID),
+		Actor:      entry.Actor,
+		Action:     string(entry.Action),
+		Target:     entry.Target,
+		Plates:     entry.Plates,
+		Outcome:    string(entry.Outcome),
+		Detail:     entry.Detail,
+		PrevHash:   entry.PrevHash,
+		Hash:       entry.Hash,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error create audit entry")
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Error().Err(err).Msg("Error commit audit entry")
+		return nil, err
+	}
+	return toDomainAuditEntry(result), nil
+}
+
+// GetAuditEntries lists the entries matching filter, newest first.
+func (ar *AuditRepository) GetAuditEntries(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	q := sqlc.New(ar.q)
+	results, err := q.GetAuditEntries(ctx, sqlc.GetAuditEntriesParams{
+		ClientID:     optionalUUID(filter.ClientID),
+		Action:       optionalText(string(filter.Action)),
+		Plate:        optionalText(filter.Plate),
+		OccurredFrom: optionalTimestamptz(filter.From),
+		OccurredTo:   optionalTimestamptz(filter.To),
+		BeforeSeq:    pgtype.Int8{Int64: filter.BeforeSeq, Valid: filter.BeforeSeq > 0},
+		RowLimit:     int32(filter.Limit),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error get audit entries")
+		return nil, err
+	}
+	entries := make([]*domain.AuditEntry, len(results))
+	for i, result := range results {
+		entries[i] = toDomainAuditEntry(result)
+	}
+	return entries, nil
+}
+
+// GetAuditChain returns up to limit entries after afterSeq, oldest first,
+// for walking the whole chain.
+func (ar *AuditRepository) GetAuditChain(ctx context.Context, afterSeq int64, limit int) ([]*domain.AuditEntry, error) {
+	q := sqlc.New(ar.q)
+	results, err := q.GetAuditChain(ctx, sqlc.GetAuditChainParams{
+		AfterSeq: afterSeq,
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error get audit chain")
+		return nil, err
+	}
+	entries := make([]*domain.AuditEntry, len(results))
+	for i, result := range results {
+		entries[i] = toDomainAuditEntry(result)
+	}
+	return entries, nil
+}
+
+func toDomainAuditEntry(e sqlc.AuditLog) *domain.AuditEntry {
+	return &domain.AuditEntry{
+		Seq:        e.Seq,
+		OccurredAt: e.OccurredAt.Time,
+		ClientID:   uuidPtr(e.ClientID),
+		Actor:      e.Actor,
+		Action:     domain.AuditAction(e.Action),
+		Target:     e.Target,
+		Plates:     e.Plates,
+		Outcome:    domain.AuditOutcome(e.Outcome),
+		Detail:     e.Detail,
+		PrevHash:   e.PrevHash,
+		Hash:       e.Hash,
+	}
+}
+
+func NewAuditRepository(pool *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{q: pool}
+}
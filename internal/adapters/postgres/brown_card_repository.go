@@ -11,9 +11,10 @@ import (
 
 	"github.com/godsent-code/midtools/configs"
 	"github.com/godsent-code/midtools/internal/domain"
-	"golang.org/x/time/rate"
 )
 
+const nicBrownCardPath = "/public-api/generate-browncard"
+
 type BrownCardRepository struct {
 	config configs.Config
 }
@@ -31,14 +32,18 @@ type nicResponse struct {
 }
 
 func (bcr *BrownCardRepository) GetBrownCard(ctx context.Context, cars []string) ([]domain.BrownCard, error) {
+	account, err := nicAccountFor(ctx, bcr.config)
+	if err != nil {
+		return nil, err
+	}
 
 	results := make([]domain.BrownCard, 0, len(cars))
 	var mu sync.Mutex
 
-	workerCount := 5
+	workerCount := account.workers()
 	jobs := make(chan string)
 
-	limiter := rate.NewLimiter(rate.Every(300*time.Millisecond), 2)
+	limiter := account.limiter()
 
 	client := &http.Client{
 		Timeout: 5 * time.Second,
@@ -54,14 +59,17 @@ func (bcr *BrownCardRepository) GetBrownCard(ctx context.Context, cars []string)
 
 			for car := range jobs {
 
-				if err := limiter.Wait(ctx); err != nil {
-					return
-				}
-
 				result := domain.BrownCard{
 					RegistrationNumber: car,
 				}
 
+				if err := limiter.Wait(ctx); err != nil {
+					result.Success = false
+					result.Message = err.Error()
+					appendResult(&mu, &results, result)
+					continue
+				}
+
 				payload := map[string]interface{}{
 					"data": map[string]string{
 						"registrationNumber": car,
@@ -79,7 +87,7 @@ func (bcr *BrownCardRepository) GetBrownCard(ctx context.Context, cars []string)
 				req, err := http.NewRequestWithContext(
 					ctx,
 					http.MethodPost,
-					bcr.config.ApiEndPoint+"/public-api/generate-browncard",
+					account.url(nicBrownCardPath),
 					bytes.NewBuffer(jsonData),
 				)
 				if err != nil {
@@ -89,7 +97,7 @@ func (bcr *BrownCardRepository) GetBrownCard(ctx context.Context, cars []string)
 					continue
 				}
 
-				req.Header.Set("Authorization", "x-api-key "+bcr.config.ApiKey)
+				account.authorize(req)
 				req.Header.Set("Content-Type", "application/json")
 
 				resp, err := client.Do(req)
@@ -142,6 +150,16 @@ func appendResult(mu *sync.Mutex, results *[]domain.BrownCard, result domain.Bro
 	mu.Unlock()
 }
 
+// PlanBrownCards lists the NIC calls GetBrownCard would make for cars, without making
+// them.
+func (bcr *BrownCardRepository) PlanBrownCards(ctx context.Context, cars []string) (*domain.NICPlan, error) {
+	account, err := nicAccountFor(ctx, bcr.config)
+	if err != nil {
+		return nil, err
+	}
+	return account.plan(http.MethodPost, nicBrownCardPath, cars), nil
+}
+
 func NewBrownCardRepository(config configs.Config) *BrownCardRepository {
 	return &BrownCardRepository{config: config}
 }
@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/godsent-code/midtools/internal/domain"
+)
+
+// CSV catalog files carry one column per NIC field, named as NIC names them.
+// A product's risk types are listed in one cell, separated by semicolons.
+var (
+	productFileColumns  = []string{"id", "name", "productCode", "description", "riskTypes"}
+	riskTypeFileColumns = []string{"id", "name", "riskCategory", "riskTypeCode", "description"}
+)
+
+const riskTypeIDSeparator = ";"
+
+// decodeProductFile reads a product catalog file. In CSV, a riskTypes column
+// replaces each product's NIC risk type mappings even when the cell is empty;
+// without the column the mappings are left alone, as when NIC omits them.
+func decodeProductFile(file domain.CatalogFile) (productNICResponse, error) {
+	var resp productNICResponse
+	switch file.Format {
+	case domain.CatalogFileJSON:
+		if err := json.Unmarshal(file.Data, &resp); err != nil {
+			return resp, fmt.Errorf("%w: %v", domain.ErrInvalidCatalogFile, err)
+		}
+	case domain.CatalogFileCSV:
+		rows, columns, err := readCatalogCSV(file.Data, "id", "name")
+		if err != nil {
+			return resp, err
+		}
+		_, withRiskTypes := columns["riskTypes"]
+		resp.Data.Products = make([]nicProduct, 0, len(rows))
+		for _, row := range rows {
+			record := nicProduct{
+				ID:          cell(row, columns, "id"),
+				Name:        cell(row, columns, "name"),
+				ProductCode: cell(row, columns, "productCode"),
+				Description: cell(row, columns, "description"),
+			}
+			if withRiskTypes {
+				record.RiskTypes = make([]nicRiskTypeRef, 0)
+				for _, id := range strings.Split(cell(row, columns, "riskTypes"), riskTypeIDSeparator) {
+					if id = strings.TrimSpace(id); id != "" {
+						record.RiskTypes = append(record.RiskTypes, nicRiskTypeRef{ID: id})
+					}
+				}
+			}
+			resp.Data.Products = append(resp.Data.Products, record)
+		}
+	default:
+		return resp, fmt.Errorf("%w: unknown format %q", domain.ErrInvalidCatalogFile, file.Format)
+	}
+	resp.Success = true
+	return resp, nil
+}
+
+func decodeRiskTypeFile(file domain.CatalogFile) (riskTypeNICResponse, error) {
+	var resp riskTypeNICResponse
+	switch file.Format {
+	case domain.CatalogFileJSON:
+		if err := json.Unmarshal(file.Data, &resp); err != nil {
+			return resp, fmt.Errorf("%w: %v", domain.ErrInvalidCatalogFile, err)
+		}
+	case domain.CatalogFileCSV:
+		rows, columns, err := readCatalogCSV(file.Data, "id", "name")
+		if err != nil {
+			return resp, err
+		}
+		resp.Data.RiskTypes = make([]nicRiskType, 0, len(rows))
+		for _, row := range rows {
+			resp.Data.RiskTypes = append(resp.Data.RiskTypes, nicRiskType{
+				ID:           cell(row, columns, "id"),
+				Name:         cell(row, columns, "name"),
+				RiskCategory: cell(row, columns, "riskCategory"),
+				RiskTypeCode: cell(row, columns, "riskTypeCode"),
+				Description:  cell(row, columns, "description"),
+			})
+		}
+	default:
+		return resp, fmt.Errorf("%w: unknown format %q", domain.ErrInvalidCatalogFile, file.Format)
+	}
+	resp.Success = true
+	return resp, nil
+}
+
+func encodeProductFile(resp productNICResponse, format domain.CatalogFileFormat) ([]byte, error) {
+	if format != domain.CatalogFileCSV {
+		return encodeCatalogJSON(resp)
+	}
+	rows := make([][]string, len(resp.Data.Products))
+	for i, p := range resp.Data.Products {
+		ids := make([]string, len(p.RiskTypes))
+		for j, rt := range p.RiskTypes {
+			ids[j] = rt.ID
+		}
+		rows[i] = []string{p.ID, p.Name, p.ProductCode, p.Description, strings.Join(ids, riskTypeIDSeparator)}
+	}
+	return encodeCatalogCSV(productFileColumns, rows)
+}
+
+func encodeRiskTypeFile(resp riskTypeNICResponse, format domain.CatalogFileFormat) ([]byte, error) {
+	if format != domain.CatalogFileCSV {
+		return encodeCatalogJSON(resp)
+	}
+	rows := make([][]string, len(resp.Data.RiskTypes))
+	for i, r := range resp.Data.RiskTypes {
+		rows[i] = []string{r.ID, r.Name, r.RiskCategory, r.RiskTypeCode, r.Description}
+	}
+	return encodeCatalogCSV(riskTypeFileColumns, rows)
+}
+
+// readCatalogCSV returns the data rows of a CSV file and the position of each
+// column named in its header. Unknown columns are ignored.
+func readCatalogCSV(data []byte, required ...string) ([][]string, map[string]int, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("%w: missing header row", domain.ErrInvalidCatalogFile)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", domain.ErrInvalidCatalogFile, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("%w: missing %s column", domain.ErrInvalidCatalogFile, name)
+		}
+	}
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", domain.ErrInvalidCatalogFile, err)
+	}
+	return rows, columns, nil
+}
+
+func cell(row []string, columns map[string]int, name string) string {
+	i, ok := columns[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func encodeCatalogJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeCatalogCSV(header []string, rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+// GetProductHistory returns every recorded version of a product, oldest
+// first.
+func (pr *ProductRepository) GetProductHistory(ctx context.Context, productID int) ([]*domain.ProductVersion, error) {
+	q := sqlc.New(pr.q)
+	results, err := q.GetProductHistory(ctx, int32(productID))
+	if err != nil {
+		log.Error().Err(err).Msg("Error get product history")
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	versions := make([]*domain.ProductVersion, len(results))
+	for i, result := range results {
+		versions[i] = &domain.ProductVersion{
+			ProductId:   int(result.ProductID),
+			Name:        result.Name,
+			ProductCode: result.ProductCode,
+			Description: result.Description.String,
+			Active:      result.Active,
+			SyncRunID:   uuidPtr(result.SyncRunID),
+			ValidFrom:   result.ValidFrom.Time,
+			ValidTo:     timePtr(result.ValidTo),
+		}
+	}
+	return versions, nil
+}
+
+// GetProductsAsOf returns the product catalog as it stood at asOf. UpdatedAt
+// is when the returned version took effect.
+func (pr *ProductRepository) GetProductsAsOf(ctx context.Context, asOf time.Time) ([]*domain.Product, error) {
+	q := sqlc.New(pr.q)
+	results, err := q.GetProductsAsOf(ctx, pgtype.Timestamptz{Time: asOf, Valid: true})
+	if err != nil {
+		log.Error().Err(err).Msg("Error get products as of")
+		return nil, err
+	}
+	products := make([]*domain.Product, len(results))
+	for i, result := range results {
+		products[i] = &domain.Product{
+			ID:          result.ID,
+			Name:        result.Name,
+			ProductCode: result.ProductCode,
+			ProductId:   int(result.ProductID),
+			Description: result.Description.String,
+			Active:      result.Active,
+			CreatedAt:   result.CreatedAt.Time,
+			UpdatedAt:   result.ValidFrom.Time,
+		}
+	}
+	return products, nil
+}
+
+// GetRiskTypeHistory returns every recorded version of a risk type, oldest
+// first.
+func (rtr *RiskTypeRepository) GetRiskTypeHistory(ctx context.Context, riskTypeID int) ([]*domain.RiskTypeVersion, error) {
+	q := sqlc.New(rtr.q)
+	results, err := q.GetRiskTypeHistory(ctx, int32(riskTypeID))
+	if err != nil {
+		log.Error().Err(err).Msg("Error get risk type history")
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	versions := make([]*domain.RiskTypeVersion, len(results))
+	for i, result := range results {
+		versions[i] = &domain.RiskTypeVersion{
+			RiskTypeId:   int(result.RiskTypeID),
+			Name:         result.Name,
+			Description:  result.Description.String,
+			RiskCategory: result.RiskCategory,
+			RiskTypeCode: result.RiskTypeCode,
+			Active:       result.Active,
+			SyncRunID:    uuidPtr(result.SyncRunID),
+			ValidFrom:    result.ValidFrom.Time,
+			ValidTo:      timePtr(result.ValidTo),
+		}
+	}
+	return versions, nil
+}
+
+// GetRiskTypesAsOf returns the risk type catalog as it stood at asOf.
+// UpdatedAt is when the returned version took effect.
+func (rtr *RiskTypeRepository) GetRiskTypesAsOf(ctx context.Context, asOf time.Time) ([]*domain.RiskType, error) {
+	q := sqlc.New(rtr.q)
+	results, err := q.GetRiskTypesAsOf(ctx, pgtype.Timestamptz{Time: asOf, Valid: true})
+	if err != nil {
+		log.Error().Err(err).Msg("Error get risk types as of")
+		return nil, err
+	}
+	riskTypes := make([]*domain.RiskType, len(results))
+	for i, result := range results {
+		riskTypes[i] = &domain.RiskType{
+			ID:           result.ID,
+			Name:         result.Name,
+			RiskTypeId:   int(result.RiskTypeID),
+			Description:  result.Description.String,
+			RiskCategory: result.RiskCategory,
+			RiskTypeCode: result.RiskTypeCode,
+			Active:       result.Active,
+			CreatedAt:    result.CreatedAt.Time,
+			UpdatedAt:    result.ValidFrom.Time,
+		}
+	}
+	return riskTypes, nil
+}
+
+func optionalUUID(id uuid.UUID) pgtype.UUID {
+	return pgtype.UUID{Bytes: id, Valid: id != uuid.Nil}
+}
+
+func uuidPtr(id pgtype.UUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	u := uuid.UUID(id.Bytes)
+	return &u
+}
+
+func timePtr(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
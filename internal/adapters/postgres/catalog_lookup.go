@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+func (pr *ProductRepository) GetProduct(ctx context.Context, ref domain.CatalogRef) (*domain.Product, error) {
+	q := sqlc.New(pr.q)
+	var result sqlc.Products
+	var err error
+	if ref.ID != uuid.Nil {
+		result, err = q.GetProduct(ctx, ref.ID)
+	} else {
+		result, err = q.GetProductByProductID(ctx, int32(ref.ExternalID))
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error get product")
+		return nil, err
+	}
+	return toDomainProduct(result), nil
+}
+
+// GetProductByCode returns the product with the given code, ignoring case.
+// Codes are not unique in NIC; an active product wins, then the lowest NIC ID.
+func (pr *ProductRepository) GetProductByCode(ctx context.Context, code string) (*domain.Product, error) {
+	q := sqlc.New(pr.q)
+	result, err := q.GetProductByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error get product by code")
+		return nil, err
+	}
+	return toDomainProduct(result), nil
+}
+
+func (rtr *RiskTypeRepository) GetRiskType(ctx context.Context, ref domain.CatalogRef) (*domain.RiskType, error) {
+	q := sqlc.New(rtr.q)
+	var result sqlc.RiskTypes
+	var err error
+	if ref.ID != uuid.Nil {
+		result, err = q.GetRiskTypeByID(ctx, ref.ID)
+	} else {
+		result, err = q.GetRiskTypeByRiskTypeID(ctx, int32(ref.ExternalID))
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error get risk type")
+		return nil, err
+	}
+	return toDomainRiskType(result), nil
+}
+
+// GetRiskTypeByCode returns the risk type with the given code, ignoring case.
+// Codes are not unique in NIC; an active risk type wins, then the lowest NIC
+// ID.
+func (rtr *RiskTypeRepository) GetRiskTypeByCode(ctx context.Context, code string) (*domain.RiskType, error) {
+	q := sqlc.New(rtr.q)
+	result, err := q.GetRiskTypeByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error get risk type by code")
+		return nil, err
+	}
+	return toDomainRiskType(result), nil
+}
+
+func toDomainProduct(p sqlc.Products) *domain.Product {
+	return &domain.Product{
+		ID:          p.ID,
+		Name:        p.Name,
+		ProductCode: p.ProductCode,
+		ProductId:   int(p.ProductID),
+		Description: p.Description.String,
+		Active:      p.Active,
+		CreatedAt:   p.CreatedAt.Time,
+		UpdatedAt:   p.UpdatedAt.Time,
+	}
+}
+
+func toDomainRiskType(r sqlc.RiskTypes) *domain.RiskType {
+	return &domain.RiskType{
+		ID:           r.ID,
+		Name:         r.Name,
+		RiskTypeId:   int(r.RiskTypeID),
+		Description:  r.Description.String,
+		RiskCategory: r.RiskCategory,
+		RiskTypeCode: r.RiskTypeCode,
+		Active:       r.Active,
+		CreatedAt:    r.CreatedAt.Time,
+		UpdatedAt:    r.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+// SearchProducts returns one page of products matching the query along with
+// the total number of matches.
+func (pr *ProductRepository) SearchProducts(ctx context.Context, query domain.CatalogQuery) (*domain.CatalogPage[domain.Product], error) {
+	q := sqlc.New(pr.q)
+	total, err := q.CountProducts(ctx, sqlc.CountProductsParams{
+		ProductCode: optionalText(query.Code),
+		Active:      optionalBool(query.Active),
+		Search:      optionalText(query.Search),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error count products")
+		return nil, err
+	}
+
+	cursorKey, cursorID := cursorParams(query.After)
+	results, err := q.SearchProducts(ctx, sqlc.SearchProductsParams{
+		Search:      optionalText(query.Search),
+		ProductCode: optionalText(query.Code),
+		Active:      optionalBool(query.Active),
+		SortBy:      string(query.Sort),
+		CursorKey:   cursorKey,
+		Descending:  query.Descending,
+		CursorID:    cursorID,
+		RowLimit:    pageLimit(query.Limit),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error search products")
+		return nil, err
+	}
+
+	page := &domain.CatalogPage[domain.Product]{Total: int(total)}
+	if query.Limit > 0 && len(results) > query.Limit {
+		results = results[:query.Limit]
+		last := results[len(results)-1]
+		page.Next = &domain.CatalogCursor{Key: last.SortKey, ID: last.ID}
+	}
+	page.Items = make([]*domain.Product, len(results))
+	for i, result := range results {
+		page.Items[i] = &domain.Product{
+			ID:          result.ID,
+			Name:        result.Name,
+			ProductCode: result.ProductCode,
+			ProductId:   int(result.ProductID),
+			Description: result.Description.String,
+			Active:      result.Active,
+			CreatedAt:   result.CreatedAt.Time,
+			UpdatedAt:   result.UpdatedAt.Time,
+		}
+	}
+	return page, nil
+}
+
+// SearchRiskTypes returns one page of risk types matching the query along
+// with the total number of matches.
+func (rtr *RiskTypeRepository) SearchRiskTypes(ctx context.Context, query domain.CatalogQuery) (*domain.CatalogPage[domain.RiskType], error) {
+	q := sqlc.New(rtr.q)
+	total, err := q.CountRiskTypes(ctx, sqlc.CountRiskTypesParams{
+		RiskTypeCode: optionalText(query.Code),
+		RiskCategory: optionalText(query.Category),
+		Active:       optionalBool(query.Active),
+		Search:       optionalText(query.Search),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error count risk types")
+		return nil, err
+	}
+
+	cursorKey, cursorID := cursorParams(query.After)
+	results, err := q.SearchRiskTypes(ctx, sqlc.SearchRiskTypesParams{
+		Search:       optionalText(query.Search),
+		RiskTypeCode: optionalText(query.Code),
+		RiskCategory: optionalText(query.Category),
+		Active:       optionalBool(query.Active),
+		SortBy:       string(query.Sort),
+		CursorKey:    cursorKey,
+		Descending:   query.Descending,
+		CursorID:     cursorID,
+		RowLimit:     pageLimit(query.Limit),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error search risk types")
+		return nil, err
+	}
+
+	page := &domain.CatalogPage[domain.RiskType]{Total: int(total)}
+	if query.Limit > 0 && len(results) > query.Limit {
+		results = results[:query.Limit]
+		last := results[len(results)-1]
+		page.Next = &domain.CatalogCursor{Key: last.SortKey, ID: last.ID}
+	}
+	page.Items = make([]*domain.RiskType, len(results))
+	for i, result := range results {
+		page.Items[i] = &domain.RiskType{
+			ID:           result.ID,
+			Name:         result.Name,
+			RiskTypeId:   int(result.RiskTypeID),
+			Description:  result.Description.String,
+			RiskCategory: result.RiskCategory,
+			RiskTypeCode: result.RiskTypeCode,
+			Active:       result.Active,
+			CreatedAt:    result.CreatedAt.Time,
+			UpdatedAt:    result.UpdatedAt.Time,
+		}
+	}
+	return page, nil
+}
+
+func optionalBool(b *bool) pgtype.Bool {
+	if b == nil {
+		return pgtype.Bool{}
+	}
+	return pgtype.Bool{Bool: *b, Valid: true}
+}
+
+func cursorParams(cursor *domain.CatalogCursor) (pgtype.Text, pgtype.UUID) {
+	if cursor == nil {
+		return pgtype.Text{}, pgtype.UUID{}
+	}
+	return pgtype.Text{String: cursor.Key, Valid: true}, pgtype.UUID{Bytes: cursor.ID, Valid: true}
+}
+
+// pageLimit asks for one row more than the page holds so the caller can tell
+// whether another page follows. No limit returns every row.
+func pageLimit(limit int) pgtype.Int4 {
+	if limit <= 0 {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: int32(limit + 1), Valid: true}
+}
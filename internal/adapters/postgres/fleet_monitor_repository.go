@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+type FleetMonitorRepository struct {
+	q *pgxpool.Pool
+}
+
+func (fmr *FleetMonitorRepository) SaveVehicleChecks(ctx context.Context, fleetID uuid.UUID, checks []domain.VehicleCheck) error {
+	if len(checks) == 0 {
+		return nil
+	}
+	params := sqlc.CreateFleetVehicleChecksParams{
+		FleetID:         fleetID,
+		Plate:           make([]string, len(checks)),
+		Success:         make([]bool, len(checks)),
+		ProductName:     make([]string, len(checks)),
+		StartDate:       make([]pgtype.Timestamptz, len(checks)),
+		EndDate:         make([]pgtype.Timestamptz, len(checks)),
+		InsuranceStatus: make([]string, len(checks)),
+		Message:         make([]string, len(checks)),
+	}
+	for i, c := range checks {
+		params.Plate[i] = c.Plate
+		params.Success[i] = c.Success
+		params.ProductName[i] = c.ProductName
+		params.StartDate[i] = optionalTimestamptz(c.StartDate)
+		params.EndDate[i] = optionalTimestamptz(c.EndDate)
+		params.InsuranceStatus[i] = string(c.InsuranceStatus)
+		params.Message[i] = c.Message
+	}
+
+	q := sqlc.New(fmr.q)
+	if err := q.CreateFleetVehicleChecks(ctx, params); err != nil {
+		log.Error().Err(err).Msg("Error save fleet vehicle checks")
+		return err
+	}
+	return nil
+}
+
+func (fmr *FleetMonitorRepository) GetLatestVehicleChecks(ctx context.Context, fleetID uuid.UUID) ([]*domain.VehicleCheck, error) {
+	q := sqlc.New(fmr.q)
+	results, err := q.GetLatestFleetVehicleChecks(ctx, fleetID)
+	if err != nil {
+		log.Error().Err(err).Msg("Error get latest fleet vehicle checks")
+		return nil, err
+	}
+	checks := make([]*domain.VehicleCheck, len(results))
+	for i, r := range results {
+		checks[i] = &domain.VehicleCheck{
+			FleetID:         r.FleetID,
+			Plate:           r.Plate,
+			Success:         r.Success,
+			ProductName:     r.ProductName,
+			StartDate:       r.StartDate.Time,
+			EndDate:         r.EndDate.Time,
+			InsuranceStatus: domain.InsuranceStatus(r.InsuranceStatus),
+			Message:         r.Message,
+			CheckedAt:       r.CheckedAt.Time,
+		}
+	}
+	return checks, nil
+}
+
+// OpenAlert opens the alert unless one of its type is already open for the
+// plate, whose message is then refreshed. It reports whether it opened one.
+func (fmr *FleetMonitorRepository) OpenAlert(ctx context.Context, alert domain.FleetAlert) (bool, error) {
+	q := sqlc.New(fmr.q)
+	opened, err := q.OpenFleetAlert(ctx, sqlc.OpenFleetAlertParams{
+		FleetID:   alert.FleetID,
+		Plate:     alert.Plate,
+		AlertType: string(alert.Type),
+		Message:   alert.Message,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error open fleet alert")
+		return false, err
+	}
+	return opened, nil
+}
+
+func (fmr *FleetMonitorRepository) ResolveAlertsByType(ctx context.Context, fleetID uuid.UUID, plate string, types []domain.AlertType) error {
+	alertTypes := make([]string, len(types))
+	for i, t := range types {
+		alertTypes[i] = string(t)
+	}
+	q := sqlc.New(fmr.q)
+	err := q.ResolveFleetAlertsByType(ctx, sqlc.ResolveFleetAlertsByTypeParams{
+		FleetID:    fleetID,
+		Plate:      plate,
+		AlertTypes: alertTypes,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error resolve fleet alerts")
+		return err
+	}
+	return nil
+}
+
+func (fmr *FleetMonitorRepository) ResolveAlert(ctx context.Context, fleetID uuid.UUID, alertID uuid.UUID) error {
+	q := sqlc.New(fmr.q)
+	rows, err := q.ResolveFleetAlert(ctx, sqlc.ResolveFleetAlertParams{
+		ID:      alertID,
+		FleetID: fleetID,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error resolve fleet alert")
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (fmr *FleetMonitorRepository) GetOpenAlerts(ctx context.Context, fleetID uuid.UUID) ([]*domain.FleetAlert, error) {
+	q := sqlc.New(fmr.q)
+	results, err := q.GetOpenFleetAlerts(ctx, fleetID)
+	if err != nil {
+		log.Error().Err(err).Msg("Error get open fleet alerts")
+		return nil, err
+	}
+	alerts := make([]*domain.FleetAlert, len(results))
+	for i, r := range results {
+		alerts[i] = &domain.FleetAlert{
+			ID:        r.ID,
+			FleetID:   r.FleetID,
+			Plate:     r.Plate,
+			Type:      domain.AlertType(r.AlertType),
+			Message:   r.Message,
+			CreatedAt: r.CreatedAt.Time,
+			UpdatedAt: r.UpdatedAt.Time,
+		}
+	}
+	return alerts, nil
+}
+
+func optionalTimestamptz(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: !t.IsZero()}
+}
+
+func NewFleetMonitorRepository(pool *pgxpool.Pool) *FleetMonitorRepository {
+	return &FleetMonitorRepository{q: pool}
+}
@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+type FleetRepository struct {
+	q *pgxpool.Pool
+}
+
+func (fr *FleetRepository) CreateFleet(ctx context.Context, fleet domain.Fleet) (*domain.Fleet, error) {
+	q := sqlc.New(fr.q)
+	result, err := q.CreateFleet(ctx, sqlc.CreateFleetParams{
+		Name:        fleet.Name,
+		Description: optionalText(fleet.Description),
+		TenantID:    optionalUUIDPtr(fleet.TenantID),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error create fleet")
+		return nil, err
+	}
+	return toDomainFleet(result), nil
+}
+
+func (fr *FleetRepository) GetFleets(ctx context.Context, scope domain.FleetScope) ([]*domain.Fleet, error) {
+	q := sqlc.New(fr.q)
+	results, err := q.GetFleets(ctx, sqlc.GetFleetsParams{
+		AllTenants: scope.AllTenants,
+		TenantID:   optionalUUIDPtr(scope.TenantID),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error get fleets")
+		return nil, err
+	}
+	fleets := make([]*domain.Fleet, len(results))
+	for i, result := range results {
+		fleets[i] = toDomainFleet(result)
+	}
+	return fleets, nil
+}
+
+func (fr *FleetRepository) GetFleet(ctx context.Context, scope domain.FleetScope, id uuid.UUID) (*domain.Fleet, error) {
+	q := sqlc.New(fr.q)
+	result, err := q.GetFleet(ctx, sqlc.GetFleetParams{
+		ID:         id,
+		AllTenants: scope.AllTenants,
+		TenantID:   optionalUUIDPtr(scope.TenantID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error get fleet")
+		return nil, err
+	}
+	return toDomainFleet(result), nil
+}
+
+func (fr *FleetRepository) UpdateFleet(ctx context.Context, scope domain.FleetScope, fleet domain.Fleet) (*domain.Fleet, error) {
+	q := sqlc.New(fr.q)
+	result, err := q.UpdateFleet(ctx, sqlc.UpdateFleetParams{
+		ID:          fleet.ID,
+		Name:        fleet.Name,
+		Description: optionalText(fleet.Description),
+		AllTenants:  scope.AllTenants,
+		TenantID:    optionalUUIDPtr(scope.TenantID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error update fleet")
+		return nil, err
+	}
+	return toDomainFleet(result), nil
+}
+
+func (fr *FleetRepository) DeleteFleet(ctx context.Context, scope domain.FleetScope, id uuid.UUID) error {
+	q := sqlc.New(fr.q)
+	rows, err := q.DeleteFleet(ctx, sqlc.DeleteFleetParams{
+		ID:         id,
+		AllTenants: scope.AllTenants,
+		TenantID:   optionalUUIDPtr(scope.TenantID),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error delete fleet")
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (fr *FleetRepository) UpsertFleetVehicles(ctx context.Context, scope domain.FleetScope, fleetID uuid.UUID, vehicles []domain.FleetVehicle) (int64, error) {
+	plates := make([]string, len(vehicles))
+	labels := make([]string, len(vehicles))
+	owners := make([]string, len(vehicles))
+	for i, v := range vehicles {
+		plates[i] = v.Plate
+		labels[i] = strings.Join(v.Labels, ",")
+		owners[i] = v.OwnerReference
+	}
+
+	q := sqlc.New(fr.q)
+	rows, err := q.UpsertFleetVehicles(ctx, sqlc.UpsertFleetVehiclesParams{
+		FleetID:        fleetID,
+		Plate:          plates,
+		Labels:         labels,
+		OwnerReference: owners,
+		AllTenants:     scope.AllTenants,
+		TenantID:       optionalUUIDPtr(scope.TenantID),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error upsert fleet vehicles")
+		return 0, err
+	}
+	return rows, nil
+}
+
+func (fr *FleetRepository) GetFleetVehicles(ctx context.Context, scope domain.FleetScope, fleetID uuid.UUID) ([]*domain.FleetVehicle, error) {
+	q := sqlc.New(fr.q)
+	results, err := q.GetFleetVehicles(ctx, sqlc.GetFleetVehiclesParams{
+		FleetID:    fleetID,
+		AllTenants: scope.AllTenants,
+		TenantID:   optionalUUIDPtr(scope.TenantID),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error get fleet vehicles")
+		return nil, err
+	}
+	vehicles := make([]*domain.FleetVehicle, len(results))
+	for i, result := range results {
+		vehicles[i] = &domain.FleetVehicle{
+			ID:             result.ID,
+			FleetID:        result.FleetID,
+			Plate:          result.Plate,
+			Labels:         result.Labels,
+			OwnerReference: result.OwnerReference.String,
+			CreatedAt:      result.CreatedAt.Time,
+			UpdatedAt:      result.UpdatedAt.Time,
+		}
+	}
+	return vehicles, nil
+}
+
+func (fr *FleetRepository) DeleteFleetVehicle(ctx context.Context, scope domain.FleetScope, fleetID uuid.UUID, plate string) error {
+	q := sqlc.New(fr.q)
+	rows, err := q.DeleteFleetVehicle(ctx, sqlc.DeleteFleetVehicleParams{
+		FleetID:    fleetID,
+		Plate:      plate,
+		AllTenants: scope.AllTenants,
+		TenantID:   optionalUUIDPtr(scope.TenantID),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error delete fleet vehicle")
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func toDomainFleet(f sqlc.Fleets) *domain.Fleet {
+	return &domain.Fleet{
+		ID:          f.ID,
+		Name:        f.Name,
+		Description: f.Description.String,
+		TenantID:    uuidPtr(f.TenantID),
+		CreatedAt:   f.CreatedAt.Time,
+		UpdatedAt:   f.UpdatedAt.Time,
+	}
+}
+
+func optionalText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+func NewFleetRepository(pool *pgxpool.Pool) *FleetRepository {
+	return &FleetRepository{q: pool}
+}
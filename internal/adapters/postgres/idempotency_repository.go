@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+type IdempotencyRepository struct {
+	q *pgxpool.Pool
+}
+
+// ClaimIdempotencyKey records key as pending for a request with the given
+// hash. It reports false when the key is held by a request created after
+// expiresBefore, or one still pending that was created after staleBefore.
+func (ir *IdempotencyRepository) ClaimIdempotencyKey(ctx context.Context, key domain.IdempotencyKey, requestHash string, expiresBefore, staleBefore time.Time) (bool, error) {
+	q := sqlc.New(ir.q)
+	_, err := q.ClaimIdempotencyKey(ctx, sqlc.ClaimIdempotencyKeyParams{
+		ClientID:      key.ClientID,
+		Endpoint:      key.Endpoint,
+		Key:           key.Key,
+		RequestHash:   requestHash,
+		ExpiresBefore: optionalTimestamptz(expiresBefore),
+		StaleBefore:   optionalTimestamptz(staleBefore),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		log.Error().Err(err).Msg("Error claim idempotency key")
+		return false, err
+	}
+	return true, nil
+}
+
+func (ir *IdempotencyRepository) GetIdempotencyKey(ctx context.Context, key domain.IdempotencyKey) (*domain.IdempotencyRecord, error) {
+	q := sqlc.New(ir.q)
+	result, err := q.GetIdempotencyKey(ctx, sqlc.GetIdempotencyKeyParams{
+		ClientID: key.ClientID,
+		Endpoint: key.Endpoint,
+		Key:      key.Key,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error get idempotency key")
+		return nil, err
+	}
+	return &domain.IdempotencyRecord{
+		IdempotencyKey: key,
+		RequestHash:    result.RequestHash,
+		StatusCode:     int(result.StatusCode.Int32),
+		Response:       result.Response,
+		CreatedAt:      result.CreatedAt.Time,
+		CompletedAt:    timePtr(result.CompletedAt),
+	}, nil
+}
+
+// CompleteIdempotencyKey stores the response to a pending key's request.
+func (ir *IdempotencyRepository) CompleteIdempotencyKey(ctx context.Context, key domain.IdempotencyKey, statusCode int, response []byte) error {
+	q := sqlc.New(ir.q)
+	if err := q.CompleteIdempotencyKey(ctx, sqlc.CompleteIdempotencyKeyParams{
+		StatusCode: pgtype.Int4{Int32: int32(statusCode), Valid: true},
+		Response:   response,
+		ClientID:   key.ClientID,
+		Endpoint:   key.Endpoint,
+		Key:        key.Key,
+	}); err != nil {
+		log.Error().Err(err).Msg("Error complete idempotency key")
+		return err
+	}
+	return nil
+}
+
+// DeleteIdempotencyKey frees a pending key. Completed keys are kept.
+func (ir *IdempotencyRepository) DeleteIdempotencyKey(ctx context.Context, key domain.IdempotencyKey) error {
+	q := sqlc.New(ir.q)
+	if err := q.DeleteIdempotencyKey(ctx, sqlc.DeleteIdempotencyKeyParams{
+		ClientID: key.ClientID,
+		Endpoint: key.Endpoint,
+		Key:      key.Key,
+	}); err != nil {
+		log.Error().Err(err).Msg("Error delete idempotency key")
+		return err
+	}
+	return nil
+}
+
+func NewIdempotencyRepository(pool *pgxpool.Pool) *IdempotencyRepository {
+	return &IdempotencyRepository{q: pool}
+}
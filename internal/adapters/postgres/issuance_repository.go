@@ -0,0 +1,272 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+type IssuanceRepository struct {
+	q *pgxpool.Pool
+}
+
+// CreateIssuanceRequest stores a submitted batch as pending, together with
+// the event recording its submission.
+func (ir *IssuanceRepository) CreateIssuanceRequest(ctx context.Context, request domain.IssuanceRequest) (*domain.IssuanceRequest, error) {
+	var riskTypeID pgtype.Int4
+	if request.RiskTypeID != nil {
+		riskTypeID = pgtype.Int4{Int32: int32(*request.RiskTypeID), Valid: true}
+	}
+	var result sqlc.IssuanceRequests
+	err := ir.inTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+		result, err = q.CreateIssuanceRequest(ctx, sqlc.CreateIssuanceRequestParams{
+			Kind:            string(request.Kind),
+			MakerID:         request.MakerID,
+			TenantID:        optionalUUIDPtr(request.TenantID),
+			Plates:          request.Plates,
+			RiskTypeID:      riskTypeID,
+			Note:            request.Note,
+			EstimatedAmount: int64(request.EstimatedAmount),
+			Branch:          request.Branch,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("Error create issuance request")
+			return err
+		}
+		return ir.createEvent(ctx, q, result.ID, "", domain.IssuancePending, &request.MakerID, request.Note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toDomainIssuanceRequest(result), nil
+}
+
+func (ir *IssuanceRepository) GetIssuanceRequest(ctx context.Context, id uuid.UUID) (*domain.IssuanceRequest, error) {
+	q := sqlc.New(ir.q)
+	result, err := q.GetIssuanceRequest(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error get issuance request")
+		return nil, err
+	}
+	events, err := q.GetIssuanceRequestEvents(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Msg("Error get issuance request events")
+		return nil, err
+	}
+	request := toDomainIssuanceRequest(result)
+	request.Events = make([]domain.IssuanceEvent, len(events))
+	for i, e := range events {
+		request.Events[i] = domain.IssuanceEvent{
+			From:      domain.IssuanceStatus(e.FromStatus),
+			To:        domain.IssuanceStatus(e.ToStatus),
+			ClientID:  uuidPtr(e.ClientID),
+			Note:      e.Note,
+			CreatedAt: e.CreatedAt.Time,
+		}
+	}
+	return request, nil
+}
+
+// GetIssuanceRequests lists requests without their results or events.
+func (ir *IssuanceRepository) GetIssuanceRequests(ctx context.Context, filter domain.IssuanceFilter) ([]*domain.IssuanceRequest, error) {
+	q := sqlc.New(ir.q)
+	results, err := q.GetIssuanceRequests(ctx, sqlc.GetIssuanceRequestsParams{
+		AllTenants: filter.AllTenants,
+		TenantID:   optionalUUIDPtr(filter.TenantID),
+		Status:     optionalText(string(filter.Status)),
+		Kind:       optionalText(string(filter.Kind)),
+		RowLimit:   int32(filter.Limit),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error get issuance requests")
+		return nil, err
+	}
+	requests := make([]*domain.IssuanceRequest, len(results))
+	for i, result := range results {
+		requests[i] = toDomainIssuanceRequest(result)
+		requests[i].Results = nil
+	}
+	return requests, nil
+}
+
+// DecideIssuanceRequest approves or rejects a pending request. It returns
+// domain.ErrConflict when the request has already been decided.
+func (ir *IssuanceRepository) DecideIssuanceRequest(ctx context.Context, id uuid.UUID, status domain.IssuanceStatus, approverID uuid.UUID, note string) (*domain.IssuanceRequest, error) {
+	var result sqlc.IssuanceRequests
+	err := ir.inTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+		result, err = q.DecideIssuanceRequest(ctx, sqlc.DecideIssuanceRequestParams{
+			Status:       string(status),
+			ApproverID:   optionalUUID(approverID),
+			DecisionNote: note,
+			ID:           id,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrConflict
+			}
+			log.Error().Err(err).Msg("Error decide issuance request")
+			return err
+		}
+		return ir.createEvent(ctx, q, id, domain.IssuancePending, status, &approverID, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toDomainIssuanceRequest(result), nil
+}
+
+// StartIssuanceRequest claims an approved request for execution. It returns
+// domain.ErrConflict when the request is not approved, including when it has
+// already been claimed.
+func (ir *IssuanceRepository) StartIssuanceRequest(ctx context.Context, id uuid.UUID, clientID uuid.UUID) (*domain.IssuanceRequest, error) {
+	var result sqlc.IssuanceRequests
+	err := ir.inTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+		result, err = q.StartIssuanceRequest(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrConflict
+			}
+			log.Error().Err(err).Msg("Error start issuance request")
+			return err
+		}
+		return ir.createEvent(ctx, q, id, domain.IssuanceApproved, domain.IssuanceExecuting, &clientID, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toDomainIssuanceRequest(result), nil
+}
+
+// CompleteIssuanceRequest stores the outcome of executing a claimed request.
+// It returns domain.ErrConflict when the request is not executing.
+func (ir *IssuanceRepository) CompleteIssuanceRequest(ctx context.Context, id uuid.UUID, status domain.IssuanceStatus, issued int, results []byte, message string, clientID uuid.UUID) (*domain.IssuanceRequest, error) {
+	var result sqlc.IssuanceRequests
+	err := ir.inTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+		result, err = q.CompleteIssuanceRequest(ctx, sqlc.CompleteIssuanceRequestParams{
+			Status:  string(status),
+			Issued:  int32(issued),
+			Results: results,
+			Error:   message,
+			ID:      id,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrConflict
+			}
+			log.Error().Err(err).Msg("Error complete issuance request")
+			return err
+		}
+		return ir.createEvent(ctx, q, id, domain.IssuanceExecuting, status, &clientID, message)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toDomainIssuanceRequest(result), nil
+}
+
+// FailInterruptedIssuanceRequests fails the requests claimed before
+// startedBefore that are still executing, and returns their ids.
+func (ir *IssuanceRepository) FailInterruptedIssuanceRequests(ctx context.Context, startedBefore time.Time, message string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := ir.inTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+		ids, err = q.FailInterruptedIssuanceRequests(ctx, sqlc.FailInterruptedIssuanceRequestsParams{
+			Error:         message,
+			StartedBefore: pgtype.Timestamptz{Time: startedBefore, Valid: true},
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("Error fail interrupted issuance requests")
+			return err
+		}
+		for _, id := range ids {
+			if err := ir.createEvent(ctx, q, id, domain.IssuanceExecuting, domain.IssuanceFailed, nil, message); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (ir *IssuanceRepository) createEvent(ctx context.Context, q *sqlc.Queries, id uuid.UUID, from, to domain.IssuanceStatus, clientID *uuid.UUID, note string) error {
+	if err := q.CreateIssuanceRequestEvent(ctx, sqlc.CreateIssuanceRequestEventParams{
+		RequestID:  id,
+		FromStatus: string(from),
+		ToStatus:   string(to),
+		ClientID:   optionalUUIDPtr(clientID),
+		Note:       note,
+	}); err != nil {
+		log.Error().Err(err).Msg("Error create issuance request event")
+		return err
+	}
+	return nil
+}
+
+// inTx runs fn in a transaction, so a status change and the event recording
+// it are stored together or not at all.
+func (ir *IssuanceRepository) inTx(ctx context.Context, fn func(q *sqlc.Queries) error) error {
+	tx, err := ir.q.Begin(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error begin issuance transaction")
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if err := fn(sqlc.New(ir.q).WithTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Error().Err(err).Msg("Error commit issuance transaction")
+		return err
+	}
+	return nil
+}
+
+func toDomainIssuanceRequest(r sqlc.IssuanceRequests) *domain.IssuanceRequest {
+	request := &domain.IssuanceRequest{
+		ID:              r.ID,
+		Kind:            domain.IssuanceKind(r.Kind),
+		Status:          domain.IssuanceStatus(r.Status),
+		MakerID:         r.MakerID,
+		TenantID:        uuidPtr(r.TenantID),
+		Plates:          r.Plates,
+		Note:            r.Note,
+		Branch:          r.Branch,
+		EstimatedAmount: domain.Money(r.EstimatedAmount),
+		ApproverID:      uuidPtr(r.ApproverID),
+		DecisionNote:    r.DecisionNote,
+		Issued:          int(r.Issued),
+		Results:         r.Results,
+		Error:           r.Error,
+		CreatedAt:       r.CreatedAt.Time,
+		DecidedAt:       timePtr(r.DecidedAt),
+		StartedAt:       timePtr(r.StartedAt),
+		ExecutedAt:      timePtr(r.ExecutedAt),
+	}
+	if r.RiskTypeID.Valid {
+		id := int(r.RiskTypeID.Int32)
+		request.RiskTypeID = &id
+	}
+	return request
+}
+
+func NewIssuanceRepository(pool *pgxpool.Pool) *IssuanceRepository {
+	return &IssuanceRepository{q: pool}
+}
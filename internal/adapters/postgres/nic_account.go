@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/godsent-code/midtools/configs"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+)
+
+// nicAccount is the NIC account a call is made with: the calling tenant's
+// when the context carries one, and otherwise the one in the config.
+type nicAccount struct {
+	tenantID  uuid.UUID
+	endpoint  string
+	apiKey    string
+	rateLimit domain.NICRateLimit
+}
+
+// tenantLimiters holds one limiter per tenant, shared by every request the
+// tenant makes, so concurrent batches together stay within its NIC limits.
+var tenantLimiters sync.Map
+
+func nicAccountFor(ctx context.Context, config configs.Config) (nicAccount, error) {
+	tenant := domain.TenantFromContext(ctx)
+	if tenant == nil {
+		return configuredNICAccount(config), nil
+	}
+	endpoint := config.NICProductionEndPoint
+	if endpoint == "" {
+		endpoint = config.ApiEndPoint
+	}
+	if tenant.NICEnvironment == domain.NICSandbox {
+		endpoint = config.NICSandboxEndPoint
+		if endpoint == "" {
+			return nicAccount{}, fmt.Errorf("tenant %s uses the NIC sandbox but NIC_SANDBOX_ENDPOINT is not configured", tenant.Name)
+		}
+	}
+	return nicAccount{
+		tenantID:  tenant.ID,
+		endpoint:  endpoint,
+		apiKey:    tenant.NICAPIKey,
+		rateLimit: tenant.NICRateLimit,
+	}, nil
+}
+
+// configuredNICAccount is the production account in the config. The product
+// and risk type catalogs are shared by every tenant, so they are always
+// synced with it, whichever tenant asked: a sandbox tenant must not
+// reconcile them against sandbox data.
+func configuredNICAccount(config configs.Config) nicAccount {
+	return nicAccount{
+		endpoint:  config.ApiEndPoint,
+		apiKey:    config.ApiKey,
+		rateLimit: domain.DefaultNICRateLimit,
+	}
+}
+
+func (a nicAccount) url(path string) string {
+	return a.endpoint + path
+}
+
+// authorize signs a request with the account's key. Every NIC call goes
+// through here, so it is also where calls are counted for metering.
+func (a nicAccount) authorize(req *http.Request) {
+	req.Header.Set("Authorization", "x-api-key "+a.apiKey)
+	domain.CountNICCall(req.Context())
+}
+
+func (a nicAccount) workers() int {
+	return max(a.rateLimit.Workers, 1)
+}
+
+// limiter paces calls made with the account. Calls with the configured
+// account get a limiter of their own, as they always have; tenants share
+// theirs, picking up changed limits on the next call.
+func (a nicAccount) limiter() *rate.Limiter {
+	limit, burst := rate.Every(a.rateLimit.Interval()), max(a.rateLimit.Burst, 1)
+	if a.tenantID == uuid.Nil {
+		return rate.NewLimiter(limit, burst)
+	}
+	value, _ := tenantLimiters.LoadOrStore(a.tenantID, rate.NewLimiter(limit, burst))
+	limiter := value.(*rate.Limiter)
+	if limiter.Limit() != limit {
+		limiter.SetLimit(limit)
+	}
+	if limiter.Burst() != burst {
+		limiter.SetBurst(burst)
+	}
+	return limiter
+}
+
+// plan lists the calls a batch of cars would make to path and estimates how
+// long the account's limiter would hold them back. It only reads the
+// limiter's tokens, so planning costs the account nothing.
+func (a nicAccount) plan(method, path string, cars []string) *domain.NICPlan {
+	calls := make([]domain.NICCall, len(cars))
+	for i, car := range cars {
+		calls[i] = domain.NICCall{Method: method, URL: a.url(path), Plate: car}
+	}
+	waiting := float64(len(cars)) - a.limiter().Tokens()
+	var estimate time.Duration
+	if waiting > 0 {
+		estimate = time.Duration(math.Ceil(waiting)) * a.rateLimit.Interval()
+	}
+	return &domain.NICPlan{Calls: calls, RateLimit: a.rateLimit, EstimatedDuration: estimate}
+}
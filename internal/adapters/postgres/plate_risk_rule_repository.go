@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+type PlateRiskRuleRepository struct {
+	q *pgxpool.Pool
+}
+
+func (pr *PlateRiskRuleRepository) CreatePlateRiskRule(ctx context.Context, rule domain.PlateRiskRule) (*domain.PlateRiskRule, error) {
+	var defaultRiskType pgtype.Int4
+	if rule.DefaultRiskTypeId != nil {
+		defaultRiskType = pgtype.Int4{Int32: int32(*rule.DefaultRiskTypeId), Valid: true}
+	}
+
+	q := sqlc.New(pr.q)
+	result, err := q.CreatePlateRiskRule(ctx, sqlc.CreatePlateRiskRuleParams{
+		PlateKind:         rule.PlateKind,
+		Code:              rule.Code,
+		RiskCategoryID:    rule.RiskCategoryID,
+		DefaultRiskTypeID: defaultRiskType,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, domain.ErrConflict
+		}
+		log.Error().Err(err).Msg("Error create plate risk rule")
+		return nil, err
+	}
+	return toDomainPlateRiskRule(sqlc.GetPlateRiskRulesRow(result)), nil
+}
+
+func (pr *PlateRiskRuleRepository) GetPlateRiskRules(ctx context.Context) (domain.PlateRiskRules, error) {
+	q := sqlc.New(pr.q)
+	results, err := q.GetPlateRiskRules(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error get plate risk rules")
+		return nil, err
+	}
+	rules := make(domain.PlateRiskRules, len(results))
+	for i, result := range results {
+		rules[i] = toDomainPlateRiskRule(result)
+	}
+	return rules, nil
+}
+
+func (pr *PlateRiskRuleRepository) DeletePlateRiskRule(ctx context.Context, id uuid.UUID) error {
+	q := sqlc.New(pr.q)
+	rows, err := q.DeletePlateRiskRule(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Msg("Error delete plate risk rule")
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (pr *PlateRiskRuleRepository) GetActiveRiskTypesInCategories(ctx context.Context, categoryIDs []uuid.UUID) ([]*domain.RiskType, error) {
+	q := sqlc.New(pr.q)
+	results, err := q.GetActiveRiskTypesInCategories(ctx, categoryIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("Error get risk types in categories")
+		return nil, err
+	}
+	riskTypes := make([]*domain.RiskType, len(results))
+	for i, result := range results {
+		riskTypes[i] = toDomainRiskType(result)
+	}
+	return riskTypes, nil
+}
+
+func toDomainPlateRiskRule(r sqlc.GetPlateRiskRulesRow) *domain.PlateRiskRule {
+	rule := &domain.PlateRiskRule{
+		ID:             r.ID,
+		PlateKind:      r.PlateKind,
+		Code:           r.Code,
+		RiskCategoryID: r.RiskCategoryID,
+		RiskCategory:   r.RiskCategory,
+		CreatedAt:      r.CreatedAt.Time,
+	}
+	if r.DefaultRiskTypeID.Valid {
+		id := int(r.DefaultRiskTypeID.Int32)
+		rule.DefaultRiskTypeId = &id
+	}
+	return rule
+}
+
+func NewPlateRiskRuleRepository(pool *pgxpool.Pool) *PlateRiskRuleRepository {
+	return &PlateRiskRuleRepository{q: pool}
+}
@@ -12,7 +12,8 @@ import (
 
 	"github.com/godsent-code/midtools/configs"
 	"github.com/godsent-code/midtools/internal/domain"
-	"golang.org/x/time/rate"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/rs/zerolog/log"
 )
 
 type PolicyVerificationRepository struct {
@@ -29,13 +30,18 @@ type nicPolicyVerificationResponse struct {
 }
 
 func (r *PolicyVerificationRepository) GetPolicyVerification(ctx context.Context, cars []string) ([]domain.PolicyVerification, error) {
+	account, err := nicAccountFor(ctx, r.config)
+	if err != nil {
+		return nil, err
+	}
+
 	results := make([]domain.PolicyVerification, 0, len(cars))
 	var mu sync.Mutex
 
-	workerCount := 5
+	workerCount := account.workers()
 	jobs := make(chan string)
 
-	limiter := rate.NewLimiter(rate.Every(300*time.Millisecond), 2)
+	limiter := account.limiter()
 
 	client := &http.Client{
 		Timeout: 5 * time.Second,
@@ -51,14 +57,17 @@ func (r *PolicyVerificationRepository) GetPolicyVerification(ctx context.Context
 
 			for car := range jobs {
 
-				if err := limiter.Wait(ctx); err != nil {
-					return
-				}
-
 				result := domain.PolicyVerification{
 					RegistrationNumber: car,
 				}
 
+				if err := limiter.Wait(ctx); err != nil {
+					result.Success = false
+					result.Message = err.Error()
+					r.appendResult(&mu, &results, result)
+					continue
+				}
+
 				payload := map[string]interface{}{
 					"data": map[string]string{
 						"registrationNumber": strings.TrimSpace(car),
@@ -76,7 +85,7 @@ func (r *PolicyVerificationRepository) GetPolicyVerification(ctx context.Context
 				req, err := http.NewRequestWithContext(
 					ctx,
 					http.MethodPost,
-					r.config.ApiEndPoint+"/public-api/policy-verification",
+					account.url("/public-api/policy-verification"),
 					bytes.NewBuffer(jsonData),
 				)
 				if err != nil {
@@ -86,7 +95,7 @@ func (r *PolicyVerificationRepository) GetPolicyVerification(ctx context.Context
 					continue
 				}
 
-				req.Header.Set("Authorization", "x-api-key "+r.config.ApiKey)
+				account.authorize(req)
 				req.Header.Set("Content-Type", "application/json")
 
 				resp, err := client.Do(req)
@@ -112,8 +121,8 @@ func (r *PolicyVerificationRepository) GetPolicyVerification(ctx context.Context
 				if nicResp.Success {
 					result.Success = true
 					result.ProductName = nicResp.Data.ProductName
-					result.StartDate = nicResp.Data.StartDate
-					result.EndDate = nicResp.Data.EndDate
+					result.StartDate = parseNICPolicyDate(car, nicResp.Data.StartDate, false)
+					result.EndDate = parseNICPolicyDate(car, nicResp.Data.EndDate, true)
 					result.Message = "policy generated successfully."
 				} else {
 					result.Success = false
@@ -140,6 +149,20 @@ func (r *PolicyVerificationRepository) appendResult(mu *sync.Mutex, results *[]d
 	mu.Unlock()
 }
 
+// parseNICPolicyDate converts a NIC policy date. A date without a time of day
+// covers the whole day, so end dates are moved to the last instant of it.
+func parseNICPolicyDate(car, value string, isEnd bool) time.Time {
+	t, dateOnly, err := pkg.ParseDate(value)
+	if err != nil {
+		log.Warn().Err(err).Str("car", car).Msg("Error parsing policy date")
+		return time.Time{}
+	}
+	if dateOnly && isEnd {
+		return pkg.EndOfDay(t)
+	}
+	return t
+}
+
 func NewPolicyVerificationRepository(config configs.Config) *PolicyVerificationRepository {
 	return &PolicyVerificationRepository{config: config}
 }
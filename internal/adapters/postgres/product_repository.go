@@ -2,11 +2,13 @@ package postgres
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
@@ -25,42 +27,177 @@ type ProductRepository struct {
 type productNICResponse struct {
 	Success bool `json:"success"`
 	Data    struct {
-		Products []struct {
-			ID          string `json:"id"`
-			Name        string `json:"name"`
-			ProductCode string `json:"productCode"`
-			Description string `json:"description"`
-		} `json:"products"`
+		Products []nicProduct `json:"products"`
 	} `json:"data"`
 }
 
-func (pr *ProductRepository) CreateProduct(ctx context.Context) error {
-	tx, err := pr.q.BeginTx(ctx, pgx.TxOptions{})
+type nicProduct struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	ProductCode string           `json:"productCode"`
+	Description string           `json:"description"`
+	RiskTypes   []nicRiskTypeRef `json:"riskTypes"`
+}
+
+type nicRiskTypeRef struct {
+	ID string `json:"id"`
+}
+
+// CreateProduct reconciles the products table with the NIC catalog, or with
+// opts.Source when importing a file: new products are inserted, changed ones
+// updated, and products no longer listed are marked inactive. A dry run
+// computes the same result without writing anything, and a sync whose payload
+// checksum matches opts.SkipIfChecksum is skipped. A payload without usable
+// records is refused unless opts.Force is set.
+func (pr *ProductRepository) CreateProduct(ctx context.Context, opts domain.SyncOptions) (*domain.SyncResult, error) {
+	incoming, rejected, upstreamCount, err := pr.loadProducts(ctx, opts.Source)
 	if err != nil {
-		log.Error().Err(err).Msg("Error begin transaction")
-		err = tx.Rollback(ctx)
+		return nil, err
+	}
+	// An empty payload, such as a file in the wrong shape or a failed NIC
+	// response, would deactivate every entry.
+	if len(incoming) == 0 && !opts.Force {
+		return nil, fmt.Errorf("%w: %d products read, none usable", domain.ErrEmptyCatalog, upstreamCount)
+	}
+	checksum, err := catalogChecksum(incoming, rejected)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.SkipIfChecksum != "" && opts.SkipIfChecksum == checksum {
+		return &domain.SyncResult{
+			Skipped:       true,
+			Checksum:      checksum,
+			UpstreamCount: upstreamCount,
+			Changes:       make([]domain.SyncChange, 0),
+			Rejected:      rejected,
+		}, nil
+	}
+
+	if opts.DryRun {
+		existing, err := sqlc.New(pr.q).GetProducts(ctx)
 		if err != nil {
-			return err
+			log.Error().Err(err).Msg("Error get products")
+			return nil, err
 		}
-		return err
+		_, _, result := diffProducts(incoming, existing)
+		result.DryRun = true
+		result.Checksum = checksum
+		result.UpstreamCount = upstreamCount
+		result.Rejected = rejected
+		return &result, nil
+	}
+
+	tx, err := pr.q.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		log.Error().Err(err).Msg("Error begin transaction")
+		return nil, err
 	}
 
 	q := sqlc.New(tx)
+	existing, err := q.GetProducts(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error get products")
+		if err := tx.Rollback(ctx); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	changed, removed, result := diffProducts(incoming, existing)
+	result.Checksum = checksum
+	result.UpstreamCount = upstreamCount
+	result.Rejected = rejected
+
+	if len(changed) > 0 {
+		params := sqlc.UpsertProductsParams{
+			ProductID:   make([]int32, len(changed)),
+			Name:        make([]string, len(changed)),
+			ProductCode: make([]string, len(changed)),
+			Description: make([]string, len(changed)),
+		}
+		for i, p := range changed {
+			params.ProductID[i] = int32(p.ProductId)
+			params.Name[i] = p.Name
+			params.ProductCode[i] = p.ProductCode
+			params.Description[i] = p.Description
+		}
+		if err := q.UpsertProducts(ctx, params); err != nil {
+			log.Error().Err(err).Msg("Error upsert products")
+			if err := tx.Rollback(ctx); err != nil {
+				return nil, err
+			}
+			return nil, err
+		}
+	}
+
+	if len(removed) > 0 {
+		if err := q.DeactivateProducts(ctx, removed); err != nil {
+			log.Error().Err(err).Msg("Error deactivate products")
+			if err := tx.Rollback(ctx); err != nil {
+				return nil, err
+			}
+			return nil, err
+		}
+	}
+
+	if err := replaceNICProductRiskTypes(ctx, q, incoming); err != nil {
+		if err := tx.Rollback(ctx); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	// Close the current version of every entry the sync touched and open a
+	// new one from the row as it now stands.
+	versioned := make([]int32, 0, len(changed)+len(removed))
+	for _, p := range changed {
+		versioned = append(versioned, int32(p.ProductId))
+	}
+	versioned = append(versioned, removed...)
+	if len(versioned) > 0 {
+		if err := q.CloseProductVersions(ctx, versioned); err != nil {
+			log.Error().Err(err).Msg("Error close product versions")
+			if err := tx.Rollback(ctx); err != nil {
+				return nil, err
+			}
+			return nil, err
+		}
+		if err := q.OpenProductVersions(ctx, sqlc.OpenProductVersionsParams{
+			SyncRunID: optionalUUID(opts.RunID),
+			ProductID: versioned,
+		}); err != nil {
+			log.Error().Err(err).Msg("Error open product versions")
+			if err := tx.Rollback(ctx); err != nil {
+				return nil, err
+			}
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Error().Err(err).Msg("Error commit transaction")
+		return nil, err
+	}
+	return &result, nil
+}
+
+// fetchNICProducts downloads the NIC product catalog with the configured
+// account, whatever tenant ctx carries. Records that cannot be imported are
+// returned as rejections rather than silently dropped.
+func (pr *ProductRepository) fetchNICProducts(ctx context.Context) ([]domain.Product, []domain.SyncRejection, int, error) {
+	account := configuredNICAccount(pr.config)
 	req, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodGet,
-		pr.config.ApiEndPoint+"/public-api/products",
+		account.url("/public-api/products"),
 		bytes.NewBuffer([]byte{}),
 	)
 	if err != nil {
 		log.Error().Err(err).Msg("Error create request")
-		err = tx.Rollback(ctx)
-		if err != nil {
-			return err
-		}
-		return err
+		return nil, nil, 0, err
 	}
-	req.Header.Set("Authorization", "x-api-key "+pr.config.ApiKey)
+	account.authorize(req)
 	req.Header.Set("Content-Type", "application/json")
 	client := &http.Client{
 		Timeout: 5 * time.Second,
@@ -68,92 +205,213 @@ func (pr *ProductRepository) CreateProduct(ctx context.Context) error {
 	resp, err := client.Do(req)
 	if err != nil {
 		log.Error().Err(err).Msg("Error execute request")
-		err = tx.Rollback(ctx)
-		if err != nil {
-			return err
-		}
-		return err
+		return nil, nil, 0, err
 	}
 	bodyBytes, _ := io.ReadAll(resp.Body)
 	resp.Body.Close()
 	var nicResp productNICResponse
 	if err := json.Unmarshal(bodyBytes, &nicResp); err != nil {
 		log.Error().Err(err).Msg("Error unmarshal response")
-		err = tx.Rollback(ctx)
-		if err != nil {
-			return err
-		}
-		return err
+		return nil, nil, 0, err
 	}
 	if !nicResp.Success {
 		log.Error().Msg("Error getting products from NIC")
-		err := tx.Rollback(ctx)
-		if err != nil {
-			return err
-		}
-		return fmt.Errorf("error getting products from NIC")
+		return nil, nil, 0, fmt.Errorf("error getting products from NIC")
+	}
+
+	products, rejected, upstreamCount := nicProducts(nicResp)
+	return products, rejected, upstreamCount, nil
+}
+
+// loadProducts reads the catalog from source, or downloads it from NIC when
+// there is none.
+func (pr *ProductRepository) loadProducts(ctx context.Context, source *domain.CatalogFile) ([]domain.Product, []domain.SyncRejection, int, error) {
+	if source == nil {
+		return pr.fetchNICProducts(ctx)
+	}
+	resp, err := decodeProductFile(*source)
+	if err != nil {
+		return nil, nil, 0, err
 	}
-	productIDs := make([]int32, len(nicResp.Data.Products))
-	ProductName := make([]string, len(nicResp.Data.Products))
-	ProductCode := make([]string, len(nicResp.Data.Products))
-	ProductDescription := make([]string, len(nicResp.Data.Products))
-	for i := range nicResp.Data.Products {
-		ids, err := strconv.Atoi(nicResp.Data.Products[i].ID)
+	products, rejected, upstreamCount := nicProducts(resp)
+	return products, rejected, upstreamCount, nil
+}
+
+// nicProducts converts NIC product records. Records that cannot be imported
+// are returned as rejections rather than silently dropped.
+func nicProducts(resp productNICResponse) ([]domain.Product, []domain.SyncRejection, int) {
+	products := make([]domain.Product, 0, len(resp.Data.Products))
+	rejected := make([]domain.SyncRejection, 0)
+	for i := range resp.Data.Products {
+		record := resp.Data.Products[i]
+		id, err := strconv.Atoi(record.ID)
 		if err != nil {
 			log.Error().Err(err).Msg("Error convert id to int")
+			rejected = append(rejected, domain.SyncRejection{
+				ExternalID: record.ID,
+				Name:       record.Name,
+				Reason:     "id is not an integer",
+			})
+			continue
+		}
+		if record.Name == "" {
+			rejected = append(rejected, domain.SyncRejection{
+				ExternalID: record.ID,
+				Reason:     "name is empty",
+			})
 			continue
 		}
-		productIDs[i] = int32(ids)
-		ProductName[i] = nicResp.Data.Products[i].Name
-		ProductCode[i] = nicResp.Data.Products[i].ProductCode
-		ProductDescription[i] = nicResp.Data.Products[i].Description
+		product := domain.Product{
+			ProductId:   id,
+			Name:        record.Name,
+			ProductCode: record.ProductCode,
+			Description: record.Description,
+		}
+		if record.RiskTypes != nil {
+			product.RiskTypeIds = make([]int, 0, len(record.RiskTypes))
+			for _, rt := range record.RiskTypes {
+				riskTypeID, err := strconv.Atoi(rt.ID)
+				if err != nil {
+					log.Warn().Str("product", record.ID).Str("riskType", rt.ID).Msg("Skipping non-integer risk type id")
+					continue
+				}
+				product.RiskTypeIds = append(product.RiskTypeIds, riskTypeID)
+			}
+		}
+		products = append(products, product)
 	}
+	return products, rejected, len(resp.Data.Products)
+}
 
-	err = q.CreateProducts(ctx, sqlc.CreateProductsParams{
-		Name:        ProductName,
-		ProductCode: ProductCode,
-		ProductID:   productIDs,
-		Description: ProductDescription,
-	})
-	if err != nil {
-		log.Error().Err(err).Msg("Error create products")
-		err = tx.Rollback(ctx)
-		if err != nil {
-			return err
+// replaceNICProductRiskTypes swaps the NIC-sourced risk type mappings of every
+// product NIC listed risk types for. Mappings added by admins are kept, and
+// risk types that have not been synced yet are skipped.
+func replaceNICProductRiskTypes(ctx context.Context, q *sqlc.Queries, incoming []domain.Product) error {
+	productIDs := make([]int32, 0)
+	params := sqlc.InsertNICProductRiskTypesParams{
+		ProductID:  make([]int32, 0),
+		RiskTypeID: make([]int32, 0),
+	}
+	for _, p := range incoming {
+		if p.RiskTypeIds == nil {
+			continue
 		}
+		productIDs = append(productIDs, int32(p.ProductId))
+		for _, id := range p.RiskTypeIds {
+			params.ProductID = append(params.ProductID, int32(p.ProductId))
+			params.RiskTypeID = append(params.RiskTypeID, int32(id))
+		}
+	}
+	if len(productIDs) == 0 {
+		return nil
+	}
+	if err := q.DeleteNICProductRiskTypes(ctx, productIDs); err != nil {
+		log.Error().Err(err).Msg("Error delete product risk types")
 		return err
 	}
+	if len(params.ProductID) == 0 {
+		return nil
+	}
+	if err := q.InsertNICProductRiskTypes(ctx, params); err != nil {
+		log.Error().Err(err).Msg("Error insert product risk types")
+		return err
+	}
+	return nil
+}
 
-	if err := tx.Commit(ctx); err != nil {
-		log.Error().Err(err).Msg("Error commit transaction")
-		err = tx.Rollback(ctx)
-		if err != nil {
-			return err
+// diffProducts compares the NIC catalog against the stored rows and returns
+// the products to upsert, the product IDs to deactivate and the resulting
+// changes. If NIC lists an ID twice, the last entry wins.
+func diffProducts(incoming []domain.Product, existing []sqlc.Products) ([]domain.Product, []int32, domain.SyncResult) {
+	result := domain.SyncResult{Changes: make([]domain.SyncChange, 0)}
+
+	current := make(map[int]sqlc.Products, len(existing))
+	for _, e := range existing {
+		current[int(e.ProductID)] = e
+	}
+
+	latest := make(map[int]domain.Product, len(incoming))
+	order := make([]int, 0, len(incoming))
+	for _, p := range incoming {
+		if _, ok := latest[p.ProductId]; !ok {
+			order = append(order, p.ProductId)
 		}
+		latest[p.ProductId] = p
 	}
-	return nil
 
+	changed := make([]domain.Product, 0)
+	for _, id := range order {
+		p := latest[id]
+		e, ok := current[id]
+		if !ok {
+			result.Summary.Added++
+			result.Changes = append(result.Changes, domain.SyncChange{ExternalID: id, Name: p.Name, Action: domain.SyncActionAdded})
+			changed = append(changed, p)
+			continue
+		}
+		fields := fieldChanges(
+			"name", e.Name, p.Name,
+			"productCode", e.ProductCode, p.ProductCode,
+			"description", e.Description.String, p.Description,
+			"active", strconv.FormatBool(e.Active), "true",
+		)
+		if len(fields) == 0 {
+			result.Summary.Unchanged++
+			continue
+		}
+		result.Summary.Updated++
+		result.Changes = append(result.Changes, domain.SyncChange{ExternalID: id, Name: p.Name, Action: domain.SyncActionUpdated, Fields: fields})
+		changed = append(changed, p)
+	}
+
+	removed := make([]int32, 0)
+	for _, e := range existing {
+		if _, ok := latest[int(e.ProductID)]; !ok && e.Active {
+			result.Summary.Deactivated++
+			result.Changes = append(result.Changes, domain.SyncChange{ExternalID: int(e.ProductID), Name: e.Name, Action: domain.SyncActionDeactivated})
+			removed = append(removed, e.ProductID)
+		}
+	}
+
+	return changed, removed, result
 }
 
-func (pr *ProductRepository) GetProducts(ctx context.Context) ([]*domain.Product, error) {
+// ExportProducts writes the active products and their NIC risk type mappings
+// as a catalog file that CreateProduct can import again. Mappings added by
+// admins are local and left out.
+func (pr *ProductRepository) ExportProducts(ctx context.Context, format domain.CatalogFileFormat) ([]byte, error) {
 	q := sqlc.New(pr.q)
-	results, err := q.GetProducts(ctx)
+	rows, err := q.GetProducts(ctx)
 	if err != nil {
 		log.Error().Err(err).Msg("Error get products")
 		return nil, err
 	}
-	products := make([]*domain.Product, len(results))
-	for i, result := range results {
-		products[i] = &domain.Product{
-			ID:          result.ID,
-			Name:        result.Name,
-			ProductCode: result.ProductCode,
-			ProductId:   int(result.ProductID),
-			Description: result.Description.String,
-			CreatedAt:   result.CreatedAt.Time,
+	mappings, err := q.GetNICProductRiskTypes(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error get product risk types")
+		return nil, err
+	}
+	riskTypes := make(map[int32][]nicRiskTypeRef)
+	for _, m := range mappings {
+		riskTypes[m.ProductID] = append(riskTypes[m.ProductID], nicRiskTypeRef{ID: strconv.Itoa(int(m.RiskTypeID))})
+	}
+
+	slices.SortFunc(rows, func(a, b sqlc.Products) int { return cmp.Compare(a.ProductID, b.ProductID) })
+	resp := productNICResponse{Success: true}
+	resp.Data.Products = make([]nicProduct, 0, len(rows))
+	for _, row := range rows {
+		if !row.Active {
+			continue
 		}
+		resp.Data.Products = append(resp.Data.Products, nicProduct{
+			ID:          strconv.Itoa(int(row.ProductID)),
+			Name:        row.Name,
+			ProductCode: row.ProductCode,
+			Description: row.Description.String,
+			RiskTypes:   nonNil(riskTypes[row.ProductID]),
+		})
 	}
-	return products, nil
+	return encodeProductFile(resp, format)
 }
 
 func NewProductRepository(pool *pgxpool.Pool, config configs.Config) *ProductRepository {
@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestDiffProducts(t *testing.T) {
+	stored := func(id int32, name, code, description string, active bool) sqlc.Products {
+		return sqlc.Products{
+			ProductID:   id,
+			Name:        name,
+			ProductCode: code,
+			Description: pgtype.Text{String: description, Valid: description != ""},
+			Active:      active,
+		}
+	}
+	product := func(id int, name, code, description string) domain.Product {
+		return domain.Product{ProductId: id, Name: name, ProductCode: code, Description: description}
+	}
+
+	tests := []struct {
+		name        string
+		incoming    []domain.Product
+		existing    []sqlc.Products
+		wantChanged []int
+		wantRemoved []int32
+		wantSummary domain.SyncSummary
+		wantChanges []domain.SyncChange
+	}{
+		{
+			name:        "new product",
+			incoming:    []domain.Product{product(1, "Private", "P1", "")},
+			wantChanged: []int{1},
+			wantRemoved: []int32{},
+			wantSummary: domain.SyncSummary{Added: 1},
+			wantChanges: []domain.SyncChange{{ExternalID: 1, Name: "Private", Action: domain.SyncActionAdded}},
+		},
+		{
+			name:        "unchanged product",
+			incoming:    []domain.Product{product(1, "Private", "P1", "Cars")},
+			existing:    []sqlc.Products{stored(1, "Private", "P1", "Cars", true)},
+			wantChanged: []int{},
+			wantRemoved: []int32{},
+			wantSummary: domain.SyncSummary{Unchanged: 1},
+			wantChanges: []domain.SyncChange{},
+		},
+		{
+			name:        "renamed product",
+			incoming:    []domain.Product{product(1, "Private use", "P1", "")},
+			existing:    []sqlc.Products{stored(1, "Private", "P1", "", true)},
+			wantChanged: []int{1},
+			wantRemoved: []int32{},
+			wantSummary: domain.SyncSummary{Updated: 1},
+			wantChanges: []domain.SyncChange{{
+				ExternalID: 1, Name: "Private use", Action: domain.SyncActionUpdated,
+				Fields: []domain.FieldChange{{Field: "name", Before: "Private", After: "Private use"}},
+			}},
+		},
+		{
+			name:        "inactive product listed again",
+			incoming:    []domain.Product{product(1, "Private", "P1", "")},
+			existing:    []sqlc.Products{stored(1, "Private", "P1", "", false)},
+			wantChanged: []int{1},
+			wantRemoved: []int32{},
+			wantSummary: domain.SyncSummary{Updated: 1},
+			wantChanges: []domain.SyncChange{{
+				ExternalID: 1, Name: "Private", Action: domain.SyncActionUpdated,
+				Fields: []domain.FieldChange{{Field: "active", Before: "false", After: "true"}},
+			}},
+		},
+		{
+			name:        "product no longer listed",
+			existing:    []sqlc.Products{stored(1, "Private", "P1", "", true)},
+			wantChanged: []int{},
+			wantRemoved: []int32{1},
+			wantSummary: domain.SyncSummary{Deactivated: 1},
+			wantChanges: []domain.SyncChange{{ExternalID: 1, Name: "Private", Action: domain.SyncActionDeactivated}},
+		},
+		{
+			name:        "inactive product still not listed",
+			existing:    []sqlc.Products{stored(1, "Private", "P1", "", false)},
+			wantChanged: []int{},
+			wantRemoved: []int32{},
+			wantSummary: domain.SyncSummary{},
+			wantChanges: []domain.SyncChange{},
+		},
+		{
+			name: "product listed twice",
+			incoming: []domain.Product{
+				product(1, "Private", "P1", ""),
+				product(1, "Private use", "P1", ""),
+			},
+			existing:    []sqlc.Products{stored(1, "Private use", "P1", "", true)},
+			wantChanged: []int{},
+			wantRemoved: []int32{},
+			wantSummary: domain.SyncSummary{Unchanged: 1},
+			wantChanges: []domain.SyncChange{},
+		},
+		{
+			name: "mixed catalog",
+			incoming: []domain.Product{
+				product(3, "Commercial", "C1", ""),
+				product(1, "Private", "P1", ""),
+				product(4, "Motor cycle", "M1", ""),
+			},
+			existing: []sqlc.Products{
+				stored(1, "Private", "P1", "", true),
+				stored(2, "Taxi", "T1", "", true),
+				stored(3, "Commercial", "C0", "", true),
+			},
+			wantChanged: []int{3, 4},
+			wantRemoved: []int32{2},
+			wantSummary: domain.SyncSummary{Added: 1, Updated: 1, Deactivated: 1, Unchanged: 1},
+			wantChanges: []domain.SyncChange{
+				{
+					ExternalID: 3, Name: "Commercial", Action: domain.SyncActionUpdated,
+					Fields: []domain.FieldChange{{Field: "productCode", Before: "C0", After: "C1"}},
+				},
+				{ExternalID: 4, Name: "Motor cycle", Action: domain.SyncActionAdded},
+				{ExternalID: 2, Name: "Taxi", Action: domain.SyncActionDeactivated},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed, removed, result := diffProducts(tt.incoming, tt.existing)
+			changedIDs := make([]int, len(changed))
+			for i, p := range changed {
+				changedIDs[i] = p.ProductId
+			}
+			if !reflect.DeepEqual(changedIDs, tt.wantChanged) {
+				t.Errorf("changed = %v, want %v", changedIDs, tt.wantChanged)
+			}
+			if !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("removed = %v, want %v", removed, tt.wantRemoved)
+			}
+			if result.Summary != tt.wantSummary {
+				t.Errorf("summary = %+v, want %+v", result.Summary, tt.wantSummary)
+			}
+			if !reflect.DeepEqual(result.Changes, tt.wantChanges) {
+				t.Errorf("changes = %+v, want %+v", result.Changes, tt.wantChanges)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+type QuotaRepository struct {
+	q *pgxpool.Pool
+}
+
+// ConsumeQuota atomically adds amount to a client's usage in the window that
+// starts at windowStart, so concurrent requests on any instance cannot
+// overshoot limit together. It reports false, without consuming anything,
+// when the quota has too little left.
+func (qr *QuotaRepository) ConsumeQuota(ctx context.Context, clientID uuid.UUID, quota domain.Quota, windowStart time.Time, amount, limit int) (int, bool, error) {
+	q := sqlc.New(qr.q)
+	used, err := q.ConsumeQuota(ctx, sqlc.ConsumeQuotaParams{
+		ClientID:    clientID,
+		Quota:       string(quota),
+		WindowStart: optionalTimestamptz(windowStart),
+		Amount:      int32(amount),
+		QuotaLimit:  int32(limit),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+		log.Error().Err(err).Msg("Error consume quota")
+		return 0, false, err
+	}
+	return int(used), true, nil
+}
+
+// GetQuotaUsage returns what a client has used in the window starting at
+// windowStart; nothing recorded for it means nothing used yet.
+func (qr *QuotaRepository) GetQuotaUsage(ctx context.Context, clientID uuid.UUID, quota domain.Quota, windowStart time.Time) (int, error) {
+	q := sqlc.New(qr.q)
+	used, err := q.GetQuotaUsage(ctx, sqlc.GetQuotaUsageParams{
+		ClientID:    clientID,
+		Quota:       string(quota),
+		WindowStart: optionalTimestamptz(windowStart),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		log.Error().Err(err).Msg("Error get quota usage")
+		return 0, err
+	}
+	return int(used), nil
+}
+
+// RefundQuota gives back amount of what a client consumed in the window that
+// starts at windowStart, for work that was counted but then refused.
+func (qr *QuotaRepository) RefundQuota(ctx context.Context, clientID uuid.UUID, quota domain.Quota, windowStart time.Time, amount int) error {
+	q := sqlc.New(qr.q)
+	err := q.RefundQuota(ctx, sqlc.RefundQuotaParams{
+		Amount:      int32(amount),
+		ClientID:    clientID,
+		Quota:       string(quota),
+		WindowStart: optionalTimestamptz(windowStart),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error refund quota")
+		return err
+	}
+	return nil
+}
+
+func NewQuotaRepository(pool *pgxpool.Pool) *QuotaRepository {
+	return &QuotaRepository{q: pool}
+}
@@ -0,0 +1,264 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+// uniqueViolation is the Postgres error code for a broken unique constraint.
+const uniqueViolation = "23505"
+
+type QuoteRepository struct {
+	q *pgxpool.Pool
+}
+
+func (qr *QuoteRepository) CreateRateTable(ctx context.Context, table domain.RateTable) (*domain.RateTable, error) {
+	bands, loadings, levies, err := marshalRates(table)
+	if err != nil {
+		return nil, err
+	}
+
+	q := sqlc.New(qr.q)
+	result, err := q.CreateRateTable(ctx, sqlc.CreateRateTableParams{
+		ProductID:            int32(table.ProductId),
+		RiskTypeID:           int32(table.RiskTypeId),
+		Currency:             table.Currency,
+		BaseRate:             table.BaseRate,
+		SumInsuredBands:      bands,
+		VehicleClassLoadings: loadings,
+		MinimumPremium:       int64(table.MinimumPremium),
+		Levies:               levies,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, domain.ErrConflict
+		}
+		log.Error().Err(err).Msg("Error create rate table")
+		return nil, err
+	}
+	return toDomainRateTable(result)
+}
+
+func (qr *QuoteRepository) GetRateTables(ctx context.Context) ([]*domain.RateTable, error) {
+	q := sqlc.New(qr.q)
+	results, err := q.GetRateTables(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error get rate tables")
+		return nil, err
+	}
+	tables := make([]*domain.RateTable, len(results))
+	for i, result := range results {
+		if tables[i], err = toDomainRateTable(result); err != nil {
+			return nil, err
+		}
+	}
+	return tables, nil
+}
+
+func (qr *QuoteRepository) GetRateTable(ctx context.Context, id uuid.UUID) (*domain.RateTable, error) {
+	q := sqlc.New(qr.q)
+	result, err := q.GetRateTable(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error get rate table")
+		return nil, err
+	}
+	return toDomainRateTable(result)
+}
+
+func (qr *QuoteRepository) GetRateTableFor(ctx context.Context, productID, riskTypeID int) (*domain.RateTable, error) {
+	q := sqlc.New(qr.q)
+	result, err := q.GetRateTableFor(ctx, sqlc.GetRateTableForParams{
+		ProductID:  int32(productID),
+		RiskTypeID: int32(riskTypeID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error get rate table for product and risk type")
+		return nil, err
+	}
+	return toDomainRateTable(result)
+}
+
+func (qr *QuoteRepository) UpdateRateTable(ctx context.Context, table domain.RateTable) (*domain.RateTable, error) {
+	bands, loadings, levies, err := marshalRates(table)
+	if err != nil {
+		return nil, err
+	}
+
+	q := sqlc.New(qr.q)
+	result, err := q.UpdateRateTable(ctx, sqlc.UpdateRateTableParams{
+		Currency:             table.Currency,
+		BaseRate:             table.BaseRate,
+		SumInsuredBands:      bands,
+		VehicleClassLoadings: loadings,
+		MinimumPremium:       int64(table.MinimumPremium),
+		Levies:               levies,
+		ID:                   table.ID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error update rate table")
+		return nil, err
+	}
+	return toDomainRateTable(result)
+}
+
+func (qr *QuoteRepository) DeleteRateTable(ctx context.Context, id uuid.UUID) error {
+	q := sqlc.New(qr.q)
+	rows, err := q.DeleteRateTable(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Msg("Error delete rate table")
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (qr *QuoteRepository) CreateQuote(ctx context.Context, quote domain.Quote) (*domain.Quote, error) {
+	lines, err := json.Marshal(quote.Lines)
+	if err != nil {
+		return nil, err
+	}
+	warnings, err := json.Marshal(nonNil(quote.Warnings))
+	if err != nil {
+		return nil, err
+	}
+	var rateTableID uuid.UUID
+	if quote.RateTableID != nil {
+		rateTableID = *quote.RateTableID
+	}
+
+	q := sqlc.New(qr.q)
+	result, err := q.CreateQuote(ctx, sqlc.CreateQuoteParams{
+		Plate:        quote.Plate,
+		ProductID:    int32(quote.ProductId),
+		RiskTypeID:   int32(quote.RiskTypeId),
+		RateTableID:  optionalUUID(rateTableID),
+		VehicleClass: quote.VehicleClass,
+		SumInsured:   int64(quote.SumInsured),
+		CoverStart:   optionalTimestamptz(quote.CoverStart),
+		CoverEnd:     optionalTimestamptz(quote.CoverEnd),
+		CoverDays:    int32(quote.CoverDays),
+		Currency:     quote.Currency,
+		Rate:         quote.Rate,
+		Lines:        lines,
+		Premium:      int64(quote.Premium),
+		Levies:       int64(quote.Levies),
+		Total:        int64(quote.Total),
+		Warnings:     warnings,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error create quote")
+		return nil, err
+	}
+	return toDomainQuote(result)
+}
+
+func (qr *QuoteRepository) GetQuote(ctx context.Context, id uuid.UUID) (*domain.Quote, error) {
+	q := sqlc.New(qr.q)
+	result, err := q.GetQuote(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error get quote")
+		return nil, err
+	}
+	return toDomainQuote(result)
+}
+
+func marshalRates(table domain.RateTable) (bands, loadings, levies []byte, err error) {
+	if bands, err = json.Marshal(nonNil(table.SumInsuredBands)); err != nil {
+		return nil, nil, nil, err
+	}
+	if loadings, err = json.Marshal(nonNil(table.VehicleClassLoadings)); err != nil {
+		return nil, nil, nil, err
+	}
+	if levies, err = json.Marshal(nonNil(table.Levies)); err != nil {
+		return nil, nil, nil, err
+	}
+	return bands, loadings, levies, nil
+}
+
+func toDomainRateTable(r sqlc.RateTables) (*domain.RateTable, error) {
+	table := &domain.RateTable{
+		ID:             r.ID,
+		ProductId:      int(r.ProductID),
+		RiskTypeId:     int(r.RiskTypeID),
+		Currency:       r.Currency,
+		BaseRate:       r.BaseRate,
+		MinimumPremium: domain.Money(r.MinimumPremium),
+		CreatedAt:      r.CreatedAt.Time,
+		UpdatedAt:      r.UpdatedAt.Time,
+	}
+	if err := json.Unmarshal(r.SumInsuredBands, &table.SumInsuredBands); err != nil {
+		log.Error().Err(err).Str("rateTable", r.ID.String()).Msg("Error decode sum insured bands")
+		return nil, err
+	}
+	if err := json.Unmarshal(r.VehicleClassLoadings, &table.VehicleClassLoadings); err != nil {
+		log.Error().Err(err).Str("rateTable", r.ID.String()).Msg("Error decode vehicle class loadings")
+		return nil, err
+	}
+	if err := json.Unmarshal(r.Levies, &table.Levies); err != nil {
+		log.Error().Err(err).Str("rateTable", r.ID.String()).Msg("Error decode levies")
+		return nil, err
+	}
+	return table, nil
+}
+
+func toDomainQuote(r sqlc.Quotes) (*domain.Quote, error) {
+	quote := &domain.Quote{
+		ID:           r.ID,
+		Plate:        r.Plate,
+		ProductId:    int(r.ProductID),
+		RiskTypeId:   int(r.RiskTypeID),
+		RateTableID:  uuidPtr(r.RateTableID),
+		VehicleClass: r.VehicleClass,
+		SumInsured:   domain.Money(r.SumInsured),
+		CoverStart:   r.CoverStart.Time,
+		CoverEnd:     r.CoverEnd.Time,
+		CoverDays:    int(r.CoverDays),
+		Currency:     r.Currency,
+		Rate:         r.Rate,
+		Premium:      domain.Money(r.Premium),
+		Levies:       domain.Money(r.Levies),
+		Total:        domain.Money(r.Total),
+		CreatedAt:    r.CreatedAt.Time,
+	}
+	if err := json.Unmarshal(r.Lines, &quote.Lines); err != nil {
+		log.Error().Err(err).Str("quote", r.ID.String()).Msg("Error decode quote lines")
+		return nil, err
+	}
+	if err := json.Unmarshal(r.Warnings, &quote.Warnings); err != nil {
+		log.Error().Err(err).Str("quote", r.ID.String()).Msg("Error decode quote warnings")
+		return nil, err
+	}
+	return quote, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+func NewQuoteRepository(pool *pgxpool.Pool) *QuoteRepository {
+	return &QuoteRepository{q: pool}
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+func (rtr *RiskTypeRepository) GetRiskCategories(ctx context.Context) ([]*domain.RiskCategory, error) {
+	q := sqlc.New(rtr.q)
+	results, err := q.GetRiskCategories(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error get risk categories")
+		return nil, err
+	}
+	categories := make([]*domain.RiskCategory, len(results))
+	for i, result := range results {
+		categories[i] = &domain.RiskCategory{
+			ID:              result.ID,
+			Name:            result.Name,
+			ActiveRiskTypes: int(result.ActiveRiskTypes),
+			CreatedAt:       result.CreatedAt.Time,
+		}
+	}
+	return categories, nil
+}
+
+// GetProductRiskTypes returns the active risk types allowed for a product.
+func (pr *ProductRepository) GetProductRiskTypes(ctx context.Context, productID int) ([]*domain.ProductRiskType, error) {
+	q := sqlc.New(pr.q)
+	results, err := q.GetProductRiskTypes(ctx, int32(productID))
+	if err != nil {
+		log.Error().Err(err).Msg("Error get product risk types")
+		return nil, err
+	}
+	riskTypes := make([]*domain.ProductRiskType, len(results))
+	for i, result := range results {
+		riskTypes[i] = &domain.ProductRiskType{
+			RiskType: domain.RiskType{
+				ID:           result.ID,
+				Name:         result.Name,
+				RiskTypeId:   int(result.RiskTypeID),
+				Description:  result.Description.String,
+				RiskCategory: result.RiskCategory,
+				RiskTypeCode: result.RiskTypeCode,
+				Active:       result.Active,
+				CreatedAt:    result.CreatedAt.Time,
+				UpdatedAt:    result.UpdatedAt.Time,
+			},
+			Source: domain.MappingSource(result.Source),
+		}
+	}
+	return riskTypes, nil
+}
+
+// AddProductRiskType allows a risk type for a product. Adding a mapping that
+// already exists is not an error and leaves its source unchanged.
+func (pr *ProductRepository) AddProductRiskType(ctx context.Context, productID, riskTypeID int) error {
+	q := sqlc.New(pr.q)
+	if _, err := q.GetRiskTypeByRiskTypeID(ctx, int32(riskTypeID)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrUnknownRiskType
+		}
+		log.Error().Err(err).Msg("Error get risk type")
+		return err
+	}
+	err := q.AddProductRiskType(ctx, sqlc.AddProductRiskTypeParams{
+		ProductID:  int32(productID),
+		RiskTypeID: int32(riskTypeID),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error add product risk type")
+		return err
+	}
+	return nil
+}
+
+func (pr *ProductRepository) RemoveProductRiskType(ctx context.Context, productID, riskTypeID int) error {
+	q := sqlc.New(pr.q)
+	rows, err := q.RemoveProductRiskType(ctx, sqlc.RemoveProductRiskTypeParams{
+		ProductID:  int32(productID),
+		RiskTypeID: int32(riskTypeID),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error remove product risk type")
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
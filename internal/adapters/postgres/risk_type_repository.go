@@ -2,11 +2,13 @@ package postgres
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
@@ -26,65 +28,183 @@ type RiskTypeRepository struct {
 type riskTypeNICResponse struct {
 	Success bool `json:"success"`
 	Data    struct {
-		RiskTypes []struct {
-			ID           string `json:"id"`
-			Name         string `json:"name"`
-			RiskCategory string `json:"riskCategory"`
-			RiskTypeCode string `json:"riskTypeCode"`
-			Description  string `json:"description"`
-		} `json:"riskTypes"`
+		RiskTypes []nicRiskType `json:"riskTypes"`
 	} `json:"data"`
 }
 
-func (rtr *RiskTypeRepository) GetRiskTypes(ctx context.Context) ([]*domain.RiskType, error) {
-	q := sqlc.New(rtr.q)
-	results, err := q.GetRiskType(ctx)
+type nicRiskType struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	RiskCategory string `json:"riskCategory"`
+	RiskTypeCode string `json:"riskTypeCode"`
+	Description  string `json:"description"`
+}
+
+// CreateRiskType reconciles the risk_types table with the NIC catalog, or
+// with opts.Source when importing a file: new risk types are inserted, changed
+// ones updated, and risk types no longer listed are marked inactive. A dry run
+// computes the same result without writing anything, and a sync whose payload
+// checksum matches opts.SkipIfChecksum is skipped. A payload without usable
+// records is refused unless opts.Force is set.
+func (rtr *RiskTypeRepository) CreateRiskType(ctx context.Context, opts domain.SyncOptions) (*domain.SyncResult, error) {
+	incoming, rejected, upstreamCount, err := rtr.loadRiskTypes(ctx, opts.Source)
+	if err != nil {
+		return nil, err
+	}
+	// An empty payload, such as a file in the wrong shape or a failed NIC
+	// response, would deactivate every entry.
+	if len(incoming) == 0 && !opts.Force {
+		return nil, fmt.Errorf("%w: %d risk types read, none usable", domain.ErrEmptyCatalog, upstreamCount)
+	}
+	checksum, err := catalogChecksum(incoming, rejected)
 	if err != nil {
-		log.Error().Err(err).Msg("error getting risk types")
 		return nil, err
 	}
-	riskTypes := make([]*domain.RiskType, len(results))
-	for i, result := range results {
-		riskTypes[i] = &domain.RiskType{
-			Description:  result.Description.String,
-			Name:         result.Name,
-			ID:           result.ID,
-			RiskCategory: result.RiskCategory,
-			RiskTypeCode: result.RiskTypeCode,
-			RiskTypeId:   int(result.RiskTypeID),
-			CreatedAt:    result.CreatedAt.Time,
+
+	if opts.SkipIfChecksum != "" && opts.SkipIfChecksum == checksum {
+		return &domain.SyncResult{
+			Skipped:       true,
+			Checksum:      checksum,
+			UpstreamCount: upstreamCount,
+			Changes:       make([]domain.SyncChange, 0),
+			Rejected:      rejected,
+		}, nil
+	}
+
+	if opts.DryRun {
+		existing, err := sqlc.New(rtr.q).GetRiskType(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("error getting risk types")
+			return nil, err
 		}
+		_, _, result := diffRiskTypes(incoming, existing)
+		result.DryRun = true
+		result.Checksum = checksum
+		result.UpstreamCount = upstreamCount
+		result.Rejected = rejected
+		return &result, nil
 	}
-	return riskTypes, nil
-}
 
-func (rtr *RiskTypeRepository) CreateRiskType(ctx context.Context) error {
 	tx, err := rtr.q.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
 		log.Error().Err(err).Msg("Error begin transaction")
-		err = tx.Rollback(ctx)
-		if err != nil {
-			return err
-		}
-		return err
+		return nil, err
 	}
 
 	q := sqlc.New(tx)
+	existing, err := q.GetRiskType(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error getting risk types")
+		if err := tx.Rollback(ctx); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	changed, removed, result := diffRiskTypes(incoming, existing)
+	result.Checksum = checksum
+	result.UpstreamCount = upstreamCount
+	result.Rejected = rejected
+
+	if len(changed) > 0 {
+		params := sqlc.UpsertRiskTypesParams{
+			RiskTypeID:   make([]int32, len(changed)),
+			Name:         make([]string, len(changed)),
+			RiskCategory: make([]string, len(changed)),
+			RiskTypeCode: make([]string, len(changed)),
+			Description:  make([]string, len(changed)),
+		}
+		for i, r := range changed {
+			params.RiskTypeID[i] = int32(r.RiskTypeId)
+			params.Name[i] = r.Name
+			params.RiskCategory[i] = r.RiskCategory
+			params.RiskTypeCode[i] = r.RiskTypeCode
+			params.Description[i] = r.Description
+		}
+		if err := q.UpsertRiskTypes(ctx, params); err != nil {
+			log.Error().Err(err).Msg("Error upsert risk types")
+			if err := tx.Rollback(ctx); err != nil {
+				return nil, err
+			}
+			return nil, err
+		}
+	}
+
+	if len(removed) > 0 {
+		if err := q.DeactivateRiskTypes(ctx, removed); err != nil {
+			log.Error().Err(err).Msg("Error deactivate risk types")
+			if err := tx.Rollback(ctx); err != nil {
+				return nil, err
+			}
+			return nil, err
+		}
+	}
+
+	if err := q.RefreshRiskCategories(ctx); err != nil {
+		log.Error().Err(err).Msg("Error refresh risk categories")
+		if err := tx.Rollback(ctx); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+	if err := q.LinkRiskTypeCategories(ctx); err != nil {
+		log.Error().Err(err).Msg("Error link risk type categories")
+		if err := tx.Rollback(ctx); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	// Close the current version of every entry the sync touched and open a
+	// new one from the row as it now stands.
+	versioned := make([]int32, 0, len(changed)+len(removed))
+	for _, r := range changed {
+		versioned = append(versioned, int32(r.RiskTypeId))
+	}
+	versioned = append(versioned, removed...)
+	if len(versioned) > 0 {
+		if err := q.CloseRiskTypeVersions(ctx, versioned); err != nil {
+			log.Error().Err(err).Msg("Error close risk type versions")
+			if err := tx.Rollback(ctx); err != nil {
+				return nil, err
+			}
+			return nil, err
+		}
+		if err := q.OpenRiskTypeVersions(ctx, sqlc.OpenRiskTypeVersionsParams{
+			SyncRunID:  optionalUUID(opts.RunID),
+			RiskTypeID: versioned,
+		}); err != nil {
+			log.Error().Err(err).Msg("Error open risk type versions")
+			if err := tx.Rollback(ctx); err != nil {
+				return nil, err
+			}
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Error().Err(err).Msg("Error commit transaction")
+		return nil, err
+	}
+	return &result, nil
+}
+
+// fetchNICRiskTypes downloads the NIC risk type catalog with the configured
+// account, whatever tenant ctx carries. Records that cannot be imported are
+// returned as rejections rather than silently dropped.
+func (rtr *RiskTypeRepository) fetchNICRiskTypes(ctx context.Context) ([]domain.RiskType, []domain.SyncRejection, int, error) {
+	account := configuredNICAccount(rtr.config)
 	req, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodGet,
-		rtr.config.ApiEndPoint+"/public-api/risk-types",
+		account.url("/public-api/risk-types"),
 		bytes.NewBuffer([]byte{}),
 	)
 	if err != nil {
 		log.Error().Err(err).Msg("Error create request")
-		err = tx.Rollback(ctx)
-		if err != nil {
-			return err
-		}
-		return err
+		return nil, nil, 0, err
 	}
-	req.Header.Set("Authorization", "x-api-key "+rtr.config.ApiKey)
+	account.authorize(req)
 	req.Header.Set("Content-Type", "application/json")
 	client := &http.Client{
 		Timeout: 5 * time.Second,
@@ -92,74 +212,156 @@ func (rtr *RiskTypeRepository) CreateRiskType(ctx context.Context) error {
 	resp, err := client.Do(req)
 	if err != nil {
 		log.Error().Err(err).Msg("Error execute request")
-		err = tx.Rollback(ctx)
-		if err != nil {
-			return err
-		}
-		return err
+		return nil, nil, 0, err
 	}
 	bodyBytes, _ := io.ReadAll(resp.Body)
 	resp.Body.Close()
 	var nicResp riskTypeNICResponse
 	if err := json.Unmarshal(bodyBytes, &nicResp); err != nil {
 		log.Error().Err(err).Msg("Error unmarshal response")
-		err = tx.Rollback(ctx)
-		if err != nil {
-			return err
-		}
-		return err
+		return nil, nil, 0, err
 	}
 	if !nicResp.Success {
-		log.Error().Msg("Error getting products from NIC")
-		err := tx.Rollback(ctx)
-		if err != nil {
-			return err
-		}
-		return fmt.Errorf("error getting products from NIC")
+		log.Error().Msg("Error getting risk types from NIC")
+		return nil, nil, 0, fmt.Errorf("error getting risk types from NIC")
 	}
-	IDs := make([]int32, len(nicResp.Data.RiskTypes))
-	name := make([]string, len(nicResp.Data.RiskTypes))
-	riskCategory := make([]string, len(nicResp.Data.RiskTypes))
-	riskTypeCode := make([]string, len(nicResp.Data.RiskTypes))
-	description := make([]string, len(nicResp.Data.RiskTypes))
-	for i := range nicResp.Data.RiskTypes {
-		ids, err := strconv.Atoi(nicResp.Data.RiskTypes[i].ID)
+
+	riskTypes, rejected, upstreamCount := nicRiskTypes(nicResp)
+	return riskTypes, rejected, upstreamCount, nil
+}
+
+// loadRiskTypes reads the catalog from source, or downloads it from NIC when
+// there is none.
+func (rtr *RiskTypeRepository) loadRiskTypes(ctx context.Context, source *domain.CatalogFile) ([]domain.RiskType, []domain.SyncRejection, int, error) {
+	if source == nil {
+		return rtr.fetchNICRiskTypes(ctx)
+	}
+	resp, err := decodeRiskTypeFile(*source)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	riskTypes, rejected, upstreamCount := nicRiskTypes(resp)
+	return riskTypes, rejected, upstreamCount, nil
+}
+
+// nicRiskTypes converts NIC risk type records. Records that cannot be
+// imported are returned as rejections rather than silently dropped.
+func nicRiskTypes(resp riskTypeNICResponse) ([]domain.RiskType, []domain.SyncRejection, int) {
+	riskTypes := make([]domain.RiskType, 0, len(resp.Data.RiskTypes))
+	rejected := make([]domain.SyncRejection, 0)
+	for i := range resp.Data.RiskTypes {
+		record := resp.Data.RiskTypes[i]
+		id, err := strconv.Atoi(record.ID)
 		if err != nil {
 			log.Error().Err(err).Msg("Error convert id to int")
+			rejected = append(rejected, domain.SyncRejection{
+				ExternalID: record.ID,
+				Name:       record.Name,
+				Reason:     "id is not an integer",
+			})
+			continue
+		}
+		if record.Name == "" {
+			rejected = append(rejected, domain.SyncRejection{
+				ExternalID: record.ID,
+				Reason:     "name is empty",
+			})
 			continue
 		}
-		IDs[i] = int32(ids)
-		name[i] = nicResp.Data.RiskTypes[i].Name
-		riskCategory[i] = nicResp.Data.RiskTypes[i].RiskCategory
-		riskTypeCode[i] = nicResp.Data.RiskTypes[i].RiskTypeCode
-		description[i] = nicResp.Data.RiskTypes[i].Description
+		riskTypes = append(riskTypes, domain.RiskType{
+			RiskTypeId:   id,
+			Name:         record.Name,
+			RiskCategory: record.RiskCategory,
+			RiskTypeCode: record.RiskTypeCode,
+			Description:  record.Description,
+		})
 	}
+	return riskTypes, rejected, len(resp.Data.RiskTypes)
+}
 
-	err = q.CreateRiskType(ctx, sqlc.CreateRiskTypeParams{
-		Name:         name,
-		RiskTypeID:   IDs,
-		RiskCategory: riskCategory,
-		RiskTypeCode: riskTypeCode,
-		Description:  description,
-	})
-	if err != nil {
-		log.Error().Err(err).Msg("Error create products")
-		err = tx.Rollback(ctx)
-		if err != nil {
-			return err
+// diffRiskTypes compares the NIC catalog against the stored rows and returns
+// the risk types to upsert, the IDs to deactivate and the resulting changes.
+// If NIC lists an ID twice, the last entry wins.
+func diffRiskTypes(incoming []domain.RiskType, existing []sqlc.RiskTypes) ([]domain.RiskType, []int32, domain.SyncResult) {
+	result := domain.SyncResult{Changes: make([]domain.SyncChange, 0)}
+
+	current := make(map[int]sqlc.RiskTypes, len(existing))
+	for _, e := range existing {
+		current[int(e.RiskTypeID)] = e
+	}
+
+	latest := make(map[int]domain.RiskType, len(incoming))
+	order := make([]int, 0, len(incoming))
+	for _, r := range incoming {
+		if _, ok := latest[r.RiskTypeId]; !ok {
+			order = append(order, r.RiskTypeId)
 		}
-		return err
+		latest[r.RiskTypeId] = r
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		log.Error().Err(err).Msg("Error commit transaction")
-		err = tx.Rollback(ctx)
-		if err != nil {
-			return err
+	changed := make([]domain.RiskType, 0)
+	for _, id := range order {
+		r := latest[id]
+		e, ok := current[id]
+		if !ok {
+			result.Summary.Added++
+			result.Changes = append(result.Changes, domain.SyncChange{ExternalID: id, Name: r.Name, Action: domain.SyncActionAdded})
+			changed = append(changed, r)
+			continue
+		}
+		fields := fieldChanges(
+			"name", e.Name, r.Name,
+			"riskCategory", e.RiskCategory, r.RiskCategory,
+			"riskTypeCode", e.RiskTypeCode, r.RiskTypeCode,
+			"description", e.Description.String, r.Description,
+			"active", strconv.FormatBool(e.Active), "true",
+		)
+		if len(fields) == 0 {
+			result.Summary.Unchanged++
+			continue
+		}
+		result.Summary.Updated++
+		result.Changes = append(result.Changes, domain.SyncChange{ExternalID: id, Name: r.Name, Action: domain.SyncActionUpdated, Fields: fields})
+		changed = append(changed, r)
+	}
+
+	removed := make([]int32, 0)
+	for _, e := range existing {
+		if _, ok := latest[int(e.RiskTypeID)]; !ok && e.Active {
+			result.Summary.Deactivated++
+			result.Changes = append(result.Changes, domain.SyncChange{ExternalID: int(e.RiskTypeID), Name: e.Name, Action: domain.SyncActionDeactivated})
+			removed = append(removed, e.RiskTypeID)
 		}
 	}
-	return nil
 
+	return changed, removed, result
+}
+
+// ExportRiskTypes writes the active risk types as a catalog file that
+// CreateRiskType can import again.
+func (rtr *RiskTypeRepository) ExportRiskTypes(ctx context.Context, format domain.CatalogFileFormat) ([]byte, error) {
+	rows, err := sqlc.New(rtr.q).GetRiskType(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error get risk types")
+		return nil, err
+	}
+
+	slices.SortFunc(rows, func(a, b sqlc.RiskTypes) int { return cmp.Compare(a.RiskTypeID, b.RiskTypeID) })
+	resp := riskTypeNICResponse{Success: true}
+	resp.Data.RiskTypes = make([]nicRiskType, 0, len(rows))
+	for _, row := range rows {
+		if !row.Active {
+			continue
+		}
+		resp.Data.RiskTypes = append(resp.Data.RiskTypes, nicRiskType{
+			ID:           strconv.Itoa(int(row.RiskTypeID)),
+			Name:         row.Name,
+			RiskCategory: row.RiskCategory,
+			RiskTypeCode: row.RiskTypeCode,
+			Description:  row.Description.String,
+		})
+	}
+	return encodeRiskTypeFile(resp, format)
 }
 
 func NewRiskTypeRepository(q *pgxpool.Pool, config configs.Config) *RiskTypeRepository {
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+)
+
+func TestDiffRiskTypes(t *testing.T) {
+	stored := func(id int32, name, category string, active bool) sqlc.RiskTypes {
+		return sqlc.RiskTypes{RiskTypeID: id, Name: name, RiskCategory: category, RiskTypeCode: "RT", Active: active}
+	}
+	riskType := func(id int, name, category string) domain.RiskType {
+		return domain.RiskType{RiskTypeId: id, Name: name, RiskCategory: category, RiskTypeCode: "RT"}
+	}
+
+	tests := []struct {
+		name        string
+		incoming    []domain.RiskType
+		existing    []sqlc.RiskTypes
+		wantChanged []int
+		wantRemoved []int32
+		wantSummary domain.SyncSummary
+	}{
+		{
+			name:        "new risk type",
+			incoming:    []domain.RiskType{riskType(1, "Own damage", "Private")},
+			wantChanged: []int{1},
+			wantRemoved: []int32{},
+			wantSummary: domain.SyncSummary{Added: 1},
+		},
+		{
+			name:        "unchanged risk type",
+			incoming:    []domain.RiskType{riskType(1, "Own damage", "Private")},
+			existing:    []sqlc.RiskTypes{stored(1, "Own damage", "Private", true)},
+			wantChanged: []int{},
+			wantRemoved: []int32{},
+			wantSummary: domain.SyncSummary{Unchanged: 1},
+		},
+		{
+			name:        "moved to another category",
+			incoming:    []domain.RiskType{riskType(1, "Own damage", "Commercial")},
+			existing:    []sqlc.RiskTypes{stored(1, "Own damage", "Private", true)},
+			wantChanged: []int{1},
+			wantRemoved: []int32{},
+			wantSummary: domain.SyncSummary{Updated: 1},
+		},
+		{
+			name:        "risk type no longer listed",
+			existing:    []sqlc.RiskTypes{stored(1, "Own damage", "Private", true), stored(2, "Third party", "Private", false)},
+			wantChanged: []int{},
+			wantRemoved: []int32{1},
+			wantSummary: domain.SyncSummary{Deactivated: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed, removed, result := diffRiskTypes(tt.incoming, tt.existing)
+			changedIDs := make([]int, len(changed))
+			for i, r := range changed {
+				changedIDs[i] = r.RiskTypeId
+			}
+			if !reflect.DeepEqual(changedIDs, tt.wantChanged) {
+				t.Errorf("changed = %v, want %v", changedIDs, tt.wantChanged)
+			}
+			if !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("removed = %v, want %v", removed, tt.wantRemoved)
+			}
+			if result.Summary != tt.wantSummary {
+				t.Errorf("summary = %+v, want %+v", result.Summary, tt.wantSummary)
+			}
+		})
+	}
+}
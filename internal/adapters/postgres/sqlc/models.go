@@ -9,6 +9,167 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AccessDenials struct {
+	ID           uuid.UUID          `json:"id"`
+	ClientID     uuid.UUID          `json:"client_id"`
+	Method       string             `json:"method"`
+	Path         string             `json:"path"`
+	RequiredRole string             `json:"required_role"`
+	Roles        []string           `json:"roles"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type ApiClients struct {
+	ID                uuid.UUID          `json:"id"`
+	Name              string             `json:"name"`
+	KeyPrefix         string             `json:"key_prefix"`
+	KeyHash           string             `json:"key_hash"`
+	Roles             []string           `json:"roles"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	LastUsedAt        pgtype.Timestamptz `json:"last_used_at"`
+	RotatedAt         pgtype.Timestamptz `json:"rotated_at"`
+	RevokedAt         pgtype.Timestamptz `json:"revoked_at"`
+	TenantID          pgtype.UUID        `json:"tenant_id"`
+	RequestsPerMinute int32              `json:"requests_per_minute"`
+	PlatesPerDay      int32              `json:"plates_per_day"`
+	MaxBatchSize      int32              `json:"max_batch_size"`
+}
+
+type ApiClientQuotaUsage struct {
+	ClientID    uuid.UUID          `json:"client_id"`
+	Quota       string             `json:"quota"`
+	WindowStart pgtype.Timestamptz `json:"window_start"`
+	Used        int32              `json:"used"`
+}
+
+type AuditLog struct {
+	Seq        int64              `json:"seq"`
+	OccurredAt pgtype.Timestamptz `json:"occurred_at"`
+	ClientID   pgtype.UUID        `json:"client_id"`
+	Actor      string             `json:"actor"`
+	Action     string             `json:"action"`
+	Target     string             `json:"target"`
+	Plates     []string           `json:"plates"`
+	Outcome    string             `json:"outcome"`
+	Detail     []byte             `json:"detail"`
+	PrevHash   string             `json:"prev_hash"`
+	Hash       string             `json:"hash"`
+}
+
+type FleetAlerts struct {
+	ID         uuid.UUID          `json:"id"`
+	FleetID    uuid.UUID          `json:"fleet_id"`
+	Plate      string             `json:"plate"`
+	AlertType  string             `json:"alert_type"`
+	Message    string             `json:"message"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+	ResolvedAt pgtype.Timestamptz `json:"resolved_at"`
+}
+
+type FleetVehicleChecks struct {
+	ID              uuid.UUID          `json:"id"`
+	FleetID         uuid.UUID          `json:"fleet_id"`
+	Plate           string             `json:"plate"`
+	Success         bool               `json:"success"`
+	ProductName     string             `json:"product_name"`
+	StartDate       pgtype.Timestamptz `json:"start_date"`
+	EndDate         pgtype.Timestamptz `json:"end_date"`
+	InsuranceStatus string             `json:"insurance_status"`
+	Message         string             `json:"message"`
+	CheckedAt       pgtype.Timestamptz `json:"checked_at"`
+}
+
+type FleetVehicles struct {
+	ID             uuid.UUID        `json:"id"`
+	FleetID        uuid.UUID        `json:"fleet_id"`
+	Plate          string           `json:"plate"`
+	Labels         []string         `json:"labels"`
+	OwnerReference pgtype.Text      `json:"owner_reference"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+}
+
+type Fleets struct {
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	Description pgtype.Text      `json:"description"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	TenantID    pgtype.UUID      `json:"tenant_id"`
+}
+
+type IdempotencyKeys struct {
+	ClientID    uuid.UUID          `json:"client_id"`
+	Endpoint    string             `json:"endpoint"`
+	Key         string             `json:"key"`
+	RequestHash string             `json:"request_hash"`
+	StatusCode  pgtype.Int4        `json:"status_code"`
+	Response    []byte             `json:"response"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+}
+
+type IssuanceRequestEvents struct {
+	ID         uuid.UUID          `json:"id"`
+	RequestID  uuid.UUID          `json:"request_id"`
+	FromStatus string             `json:"from_status"`
+	ToStatus   string             `json:"to_status"`
+	ClientID   pgtype.UUID        `json:"client_id"`
+	Note       string             `json:"note"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type IssuanceRequests struct {
+	ID              uuid.UUID          `json:"id"`
+	Kind            string             `json:"kind"`
+	Status          string             `json:"status"`
+	MakerID         uuid.UUID          `json:"maker_id"`
+	TenantID        pgtype.UUID        `json:"tenant_id"`
+	Plates          []string           `json:"plates"`
+	RiskTypeID      pgtype.Int4        `json:"risk_type_id"`
+	Note            string             `json:"note"`
+	EstimatedAmount int64              `json:"estimated_amount"`
+	ApproverID      pgtype.UUID        `json:"approver_id"`
+	DecisionNote    string             `json:"decision_note"`
+	Issued          int32              `json:"issued"`
+	Results         []byte             `json:"results"`
+	Error           string             `json:"error"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	DecidedAt       pgtype.Timestamptz `json:"decided_at"`
+	ExecutedAt      pgtype.Timestamptz `json:"executed_at"`
+	Branch          string             `json:"branch"`
+	StartedAt       pgtype.Timestamptz `json:"started_at"`
+}
+
+type PlateRiskRules struct {
+	ID                uuid.UUID          `json:"id"`
+	PlateKind         string             `json:"plate_kind"`
+	Code              string             `json:"code"`
+	RiskCategoryID    uuid.UUID          `json:"risk_category_id"`
+	DefaultRiskTypeID pgtype.Int4        `json:"default_risk_type_id"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+}
+
+type ProductHistory struct {
+	ID          uuid.UUID          `json:"id"`
+	ProductID   int32              `json:"product_id"`
+	Name        string             `json:"name"`
+	ProductCode string             `json:"product_code"`
+	Description pgtype.Text        `json:"description"`
+	Active      bool               `json:"active"`
+	SyncRunID   pgtype.UUID        `json:"sync_run_id"`
+	ValidFrom   pgtype.Timestamptz `json:"valid_from"`
+	ValidTo     pgtype.Timestamptz `json:"valid_to"`
+}
+
+type ProductRiskTypes struct {
+	ProductID  int32              `json:"product_id"`
+	RiskTypeID int32              `json:"risk_type_id"`
+	Source     string             `json:"source"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
 type Products struct {
 	ID          uuid.UUID        `json:"id"`
 	ProductID   int32            `json:"product_id"`
@@ -16,14 +177,138 @@ type Products struct {
 	ProductCode string           `json:"product_code"`
 	Description pgtype.Text      `json:"description"`
 	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	Active      bool             `json:"active"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
+type Quotes struct {
+	ID           uuid.UUID          `json:"id"`
+	Plate        string             `json:"plate"`
+	ProductID    int32              `json:"product_id"`
+	RiskTypeID   int32              `json:"risk_type_id"`
+	RateTableID  pgtype.UUID        `json:"rate_table_id"`
+	VehicleClass string             `json:"vehicle_class"`
+	SumInsured   int64              `json:"sum_insured"`
+	CoverStart   pgtype.Timestamptz `json:"cover_start"`
+	CoverEnd     pgtype.Timestamptz `json:"cover_end"`
+	CoverDays    int32              `json:"cover_days"`
+	Currency     string             `json:"currency"`
+	Rate         float64            `json:"rate"`
+	Lines        []byte             `json:"lines"`
+	Premium      int64              `json:"premium"`
+	Levies       int64              `json:"levies"`
+	Total        int64              `json:"total"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	Warnings     []byte             `json:"warnings"`
+}
+
+type RateTables struct {
+	ID                   uuid.UUID          `json:"id"`
+	ProductID            int32              `json:"product_id"`
+	RiskTypeID           int32              `json:"risk_type_id"`
+	Currency             string             `json:"currency"`
+	BaseRate             float64            `json:"base_rate"`
+	SumInsuredBands      []byte             `json:"sum_insured_bands"`
+	VehicleClassLoadings []byte             `json:"vehicle_class_loadings"`
+	MinimumPremium       int64              `json:"minimum_premium"`
+	Levies               []byte             `json:"levies"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+}
+
+type RiskCategories struct {
+	ID        uuid.UUID          `json:"id"`
+	Name      string             `json:"name"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type RiskTypeHistory struct {
+	ID           uuid.UUID          `json:"id"`
+	RiskTypeID   int32              `json:"risk_type_id"`
+	Name         string             `json:"name"`
+	RiskCategory string             `json:"risk_category"`
+	RiskTypeCode string             `json:"risk_type_code"`
+	Description  pgtype.Text        `json:"description"`
+	Active       bool               `json:"active"`
+	SyncRunID    pgtype.UUID        `json:"sync_run_id"`
+	ValidFrom    pgtype.Timestamptz `json:"valid_from"`
+	ValidTo      pgtype.Timestamptz `json:"valid_to"`
 }
 
 type RiskTypes struct {
-	ID           uuid.UUID        `json:"id"`
-	RiskTypeID   int32            `json:"risk_type_id"`
-	Name         string           `json:"name"`
-	RiskCategory string           `json:"risk_category"`
-	RiskTypeCode string           `json:"risk_type_code"`
-	Description  pgtype.Text      `json:"description"`
-	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	ID             uuid.UUID        `json:"id"`
+	RiskTypeID     int32            `json:"risk_type_id"`
+	Name           string           `json:"name"`
+	RiskCategory   string           `json:"risk_category"`
+	RiskTypeCode   string           `json:"risk_type_code"`
+	Description    pgtype.Text      `json:"description"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	Active         bool             `json:"active"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+	RiskCategoryID pgtype.UUID      `json:"risk_category_id"`
+}
+
+type ServicePrices struct {
+	Service      string             `json:"service"`
+	PlatePrice   int64              `json:"plate_price"`
+	NicCallPrice int64              `json:"nic_call_price"`
+	IssuedPrice  int64              `json:"issued_price"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+type StickerRegistry struct {
+	ID              uuid.UUID          `json:"id"`
+	Plate           string             `json:"plate"`
+	CanonicalPlate  string             `json:"canonical_plate"`
+	StickerNumber   string             `json:"sticker_number"`
+	StickerLink     string             `json:"sticker_link"`
+	ClientID        pgtype.UUID        `json:"client_id"`
+	TenantID        pgtype.UUID        `json:"tenant_id"`
+	Branch          string             `json:"branch"`
+	Status          string             `json:"status"`
+	StatusNote      string             `json:"status_note"`
+	IssuedAt        pgtype.Timestamptz `json:"issued_at"`
+	StatusChangedAt pgtype.Timestamptz `json:"status_changed_at"`
+}
+
+type SyncRuns struct {
+	ID              uuid.UUID          `json:"id"`
+	Catalog         string             `json:"catalog"`
+	TriggerSource   string             `json:"trigger_source"`
+	DryRun          bool               `json:"dry_run"`
+	StartedAt       pgtype.Timestamptz `json:"started_at"`
+	FinishedAt      pgtype.Timestamptz `json:"finished_at"`
+	Outcome         string             `json:"outcome"`
+	Error           pgtype.Text        `json:"error"`
+	UpstreamCount   int32              `json:"upstream_count"`
+	Added           int32              `json:"added"`
+	Updated         int32              `json:"updated"`
+	Deactivated     int32              `json:"deactivated"`
+	Unchanged       int32              `json:"unchanged"`
+	Changes         []byte             `json:"changes"`
+	Rejected        []byte             `json:"rejected"`
+	PayloadChecksum pgtype.Text        `json:"payload_checksum"`
+}
+
+type Tenants struct {
+	ID                   uuid.UUID          `json:"id"`
+	Name                 string             `json:"name"`
+	NicEnvironment       string             `json:"nic_environment"`
+	NicApiKeyEncrypted   []byte             `json:"nic_api_key_encrypted"`
+	NicRequestIntervalMs int32              `json:"nic_request_interval_ms"`
+	NicBurst             int32              `json:"nic_burst"`
+	NicWorkers           int32              `json:"nic_workers"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+}
+
+type UsageRecords struct {
+	ID        uuid.UUID          `json:"id"`
+	ClientID  uuid.UUID          `json:"client_id"`
+	Service   string             `json:"service"`
+	Plates    int32              `json:"plates"`
+	NicCalls  int32              `json:"nic_calls"`
+	Issued    int32              `json:"issued"`
+	Amount    int64              `json:"amount"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
@@ -7,58 +7,95 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/google/uuid"
 )
 
-const createProducts = `-- name: CreateProducts :exec
-INSERT INTO products(product_id, name, product_code, description)
- SELECT unnest($1::int[]),unnest($2::text[]),unnest($3::text[]),
-        unnest($4::text[]) ON CONFLICT DO NOTHING
+const deactivateProducts = `-- name: DeactivateProducts :exec
+UPDATE products SET active = FALSE, updated_at = NOW()
+WHERE product_id = ANY($1::int[]) AND active
 `
 
-type CreateProductsParams struct {
-	ProductID   []int32  `json:"product_id"`
-	Name        []string `json:"name"`
-	ProductCode []string `json:"product_code"`
-	Description []string `json:"description"`
+func (q *Queries) DeactivateProducts(ctx context.Context, productID []int32) error {
+	_, err := q.db.Exec(ctx, deactivateProducts, productID)
+	return err
 }
 
-func (q *Queries) CreateProducts(ctx context.Context, arg CreateProductsParams) error {
-	_, err := q.db.Exec(ctx, createProducts,
-		arg.ProductID,
-		arg.Name,
-		arg.ProductCode,
-		arg.Description,
-	)
+const deactivateRiskTypes = `-- name: DeactivateRiskTypes :exec
+UPDATE risk_types SET active = FALSE, updated_at = NOW()
+WHERE risk_type_id = ANY($1::int[]) AND active
+`
+
+func (q *Queries) DeactivateRiskTypes(ctx context.Context, riskTypeID []int32) error {
+	_, err := q.db.Exec(ctx, deactivateRiskTypes, riskTypeID)
 	return err
 }
 
-const createRiskType = `-- name: CreateRiskType :exec
-INSERT INTO risk_types(risk_type_id, name, risk_category, risk_type_code, description)
-SELECT unnest($1::int[]),unnest($2::text[]),unnest($3::text[]),
-       unnest($4::text[]),unnest($5::text[]) ON CONFLICT DO NOTHING
+const getProduct = `-- name: GetProduct :one
+SELECT id, product_id, name, product_code, description, created_at, active, updated_at FROM products WHERE id = $1
 `
 
-type CreateRiskTypeParams struct {
-	RiskTypeID   []int32  `json:"risk_type_id"`
-	Name         []string `json:"name"`
-	RiskCategory []string `json:"risk_category"`
-	RiskTypeCode []string `json:"risk_type_code"`
-	Description  []string `json:"description"`
+func (q *Queries) GetProduct(ctx context.Context, id uuid.UUID) (Products, error) {
+	row := q.db.QueryRow(ctx, getProduct, id)
+	var i Products
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Name,
+		&i.ProductCode,
+		&i.Description,
+		&i.CreatedAt,
+		&i.Active,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-func (q *Queries) CreateRiskType(ctx context.Context, arg CreateRiskTypeParams) error {
-	_, err := q.db.Exec(ctx, createRiskType,
-		arg.RiskTypeID,
-		arg.Name,
-		arg.RiskCategory,
-		arg.RiskTypeCode,
-		arg.Description,
+const getProductByCode = `-- name: GetProductByCode :one
+SELECT id, product_id, name, product_code, description, created_at, active, updated_at FROM products
+WHERE lower(product_code) = lower($1::text)
+ORDER BY active DESC, product_id
+LIMIT 1
+`
+
+func (q *Queries) GetProductByCode(ctx context.Context, productCode string) (Products, error) {
+	row := q.db.QueryRow(ctx, getProductByCode, productCode)
+	var i Products
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Name,
+		&i.ProductCode,
+		&i.Description,
+		&i.CreatedAt,
+		&i.Active,
+		&i.UpdatedAt,
 	)
-	return err
+	return i, err
+}
+
+const getProductByProductID = `-- name: GetProductByProductID :one
+SELECT id, product_id, name, product_code, description, created_at, active, updated_at FROM products WHERE product_id = $1
+`
+
+func (q *Queries) GetProductByProductID(ctx context.Context, productID int32) (Products, error) {
+	row := q.db.QueryRow(ctx, getProductByProductID, productID)
+	var i Products
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Name,
+		&i.ProductCode,
+		&i.Description,
+		&i.CreatedAt,
+		&i.Active,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
 const getProducts = `-- name: GetProducts :many
-SELECT id, product_id, name, product_code, description, created_at FROM products
+SELECT id, product_id, name, product_code, description, created_at, active, updated_at FROM products
 `
 
 func (q *Queries) GetProducts(ctx context.Context) ([]Products, error) {
@@ -77,6 +114,8 @@ func (q *Queries) GetProducts(ctx context.Context) ([]Products, error) {
 			&i.ProductCode,
 			&i.Description,
 			&i.CreatedAt,
+			&i.Active,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -89,7 +128,7 @@ func (q *Queries) GetProducts(ctx context.Context) ([]Products, error) {
 }
 
 const getRiskType = `-- name: GetRiskType :many
-SELECT id, risk_type_id, name, risk_category, risk_type_code, description, created_at FROM risk_types
+SELECT id, risk_type_id, name, risk_category, risk_type_code, description, created_at, active, updated_at, risk_category_id FROM risk_types
 `
 
 func (q *Queries) GetRiskType(ctx context.Context) ([]RiskTypes, error) {
@@ -109,6 +148,9 @@ func (q *Queries) GetRiskType(ctx context.Context) ([]RiskTypes, error) {
 			&i.RiskTypeCode,
 			&i.Description,
 			&i.CreatedAt,
+			&i.Active,
+			&i.UpdatedAt,
+			&i.RiskCategoryID,
 		); err != nil {
 			return nil, err
 		}
@@ -119,3 +161,126 @@ func (q *Queries) GetRiskType(ctx context.Context) ([]RiskTypes, error) {
 	}
 	return items, nil
 }
+
+const getRiskTypeByCode = `-- name: GetRiskTypeByCode :one
+SELECT id, risk_type_id, name, risk_category, risk_type_code, description, created_at, active, updated_at, risk_category_id FROM risk_types
+WHERE lower(risk_type_code) = lower($1::text)
+ORDER BY active DESC, risk_type_id
+LIMIT 1
+`
+
+func (q *Queries) GetRiskTypeByCode(ctx context.Context, riskTypeCode string) (RiskTypes, error) {
+	row := q.db.QueryRow(ctx, getRiskTypeByCode, riskTypeCode)
+	var i RiskTypes
+	err := row.Scan(
+		&i.ID,
+		&i.RiskTypeID,
+		&i.Name,
+		&i.RiskCategory,
+		&i.RiskTypeCode,
+		&i.Description,
+		&i.CreatedAt,
+		&i.Active,
+		&i.UpdatedAt,
+		&i.RiskCategoryID,
+	)
+	return i, err
+}
+
+const getRiskTypeByID = `-- name: GetRiskTypeByID :one
+SELECT id, risk_type_id, name, risk_category, risk_type_code, description, created_at, active, updated_at, risk_category_id FROM risk_types WHERE id = $1
+`
+
+func (q *Queries) GetRiskTypeByID(ctx context.Context, id uuid.UUID) (RiskTypes, error) {
+	row := q.db.QueryRow(ctx, getRiskTypeByID, id)
+	var i RiskTypes
+	err := row.Scan(
+		&i.ID,
+		&i.RiskTypeID,
+		&i.Name,
+		&i.RiskCategory,
+		&i.RiskTypeCode,
+		&i.Description,
+		&i.CreatedAt,
+		&i.Active,
+		&i.UpdatedAt,
+		&i.RiskCategoryID,
+	)
+	return i, err
+}
+
+const getRiskTypeByRiskTypeID = `-- name: GetRiskTypeByRiskTypeID :one
+SELECT id, risk_type_id, name, risk_category, risk_type_code, description, created_at, active, updated_at, risk_category_id FROM risk_types WHERE risk_type_id = $1
+`
+
+func (q *Queries) GetRiskTypeByRiskTypeID(ctx context.Context, riskTypeID int32) (RiskTypes, error) {
+	row := q.db.QueryRow(ctx, getRiskTypeByRiskTypeID, riskTypeID)
+	var i RiskTypes
+	err := row.Scan(
+		&i.ID,
+		&i.RiskTypeID,
+		&i.Name,
+		&i.RiskCategory,
+		&i.RiskTypeCode,
+		&i.Description,
+		&i.CreatedAt,
+		&i.Active,
+		&i.UpdatedAt,
+		&i.RiskCategoryID,
+	)
+	return i, err
+}
+
+const upsertProducts = `-- name: UpsertProducts :exec
+INSERT INTO products(product_id, name, product_code, description)
+ SELECT unnest($1::int[]),unnest($2::text[]),unnest($3::text[]),
+        unnest($4::text[])
+ON CONFLICT (product_id) DO UPDATE
+    SET name = EXCLUDED.name, product_code = EXCLUDED.product_code, description = EXCLUDED.description,
+        active = TRUE, updated_at = NOW()
+`
+
+type UpsertProductsParams struct {
+	ProductID   []int32  `json:"product_id"`
+	Name        []string `json:"name"`
+	ProductCode []string `json:"product_code"`
+	Description []string `json:"description"`
+}
+
+func (q *Queries) UpsertProducts(ctx context.Context, arg UpsertProductsParams) error {
+	_, err := q.db.Exec(ctx, upsertProducts,
+		arg.ProductID,
+		arg.Name,
+		arg.ProductCode,
+		arg.Description,
+	)
+	return err
+}
+
+const upsertRiskTypes = `-- name: UpsertRiskTypes :exec
+INSERT INTO risk_types(risk_type_id, name, risk_category, risk_type_code, description)
+SELECT unnest($1::int[]),unnest($2::text[]),unnest($3::text[]),
+       unnest($4::text[]),unnest($5::text[])
+ON CONFLICT (risk_type_id) DO UPDATE
+    SET name = EXCLUDED.name, risk_category = EXCLUDED.risk_category, risk_type_code = EXCLUDED.risk_type_code,
+        description = EXCLUDED.description, active = TRUE, updated_at = NOW()
+`
+
+type UpsertRiskTypesParams struct {
+	RiskTypeID   []int32  `json:"risk_type_id"`
+	Name         []string `json:"name"`
+	RiskCategory []string `json:"risk_category"`
+	RiskTypeCode []string `json:"risk_type_code"`
+	Description  []string `json:"description"`
+}
+
+func (q *Queries) UpsertRiskTypes(ctx context.Context, arg UpsertRiskTypesParams) error {
+	_, err := q.db.Exec(ctx, upsertRiskTypes,
+		arg.RiskTypeID,
+		arg.Name,
+		arg.RiskCategory,
+		arg.RiskTypeCode,
+		arg.Description,
+	)
+	return err
+}
@@ -6,13 +6,122 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
-	CreateProducts(ctx context.Context, arg CreateProductsParams) error
-	CreateRiskType(ctx context.Context, arg CreateRiskTypeParams) error
+	AddProductRiskType(ctx context.Context, arg AddProductRiskTypeParams) error
+	ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (IdempotencyKeys, error)
+	CloseProductVersions(ctx context.Context, productID []int32) error
+	CloseRiskTypeVersions(ctx context.Context, riskTypeID []int32) error
+	CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) error
+	CompleteIssuanceRequest(ctx context.Context, arg CompleteIssuanceRequestParams) (IssuanceRequests, error)
+	ConsumeQuota(ctx context.Context, arg ConsumeQuotaParams) (int32, error)
+	CountProducts(ctx context.Context, arg CountProductsParams) (int64, error)
+	CountRiskTypes(ctx context.Context, arg CountRiskTypesParams) (int64, error)
+	CreateAPIClient(ctx context.Context, arg CreateAPIClientParams) (ApiClients, error)
+	CreateAccessDenial(ctx context.Context, arg CreateAccessDenialParams) error
+	CreateAuditEntry(ctx context.Context, arg CreateAuditEntryParams) (AuditLog, error)
+	CreateFleet(ctx context.Context, arg CreateFleetParams) (Fleets, error)
+	CreateFleetVehicleChecks(ctx context.Context, arg CreateFleetVehicleChecksParams) error
+	CreateIssuanceRequest(ctx context.Context, arg CreateIssuanceRequestParams) (IssuanceRequests, error)
+	CreateIssuanceRequestEvent(ctx context.Context, arg CreateIssuanceRequestEventParams) error
+	CreatePlateRiskRule(ctx context.Context, arg CreatePlateRiskRuleParams) (CreatePlateRiskRuleRow, error)
+	CreateQuote(ctx context.Context, arg CreateQuoteParams) (Quotes, error)
+	CreateRateTable(ctx context.Context, arg CreateRateTableParams) (RateTables, error)
+	CreateStickerRegistryEntries(ctx context.Context, arg CreateStickerRegistryEntriesParams) error
+	CreateSyncRun(ctx context.Context, arg CreateSyncRunParams) (SyncRuns, error)
+	CreateTenant(ctx context.Context, arg CreateTenantParams) (Tenants, error)
+	CreateUsageRecord(ctx context.Context, arg CreateUsageRecordParams) error
+	DeactivateProducts(ctx context.Context, productID []int32) error
+	DeactivateRiskTypes(ctx context.Context, riskTypeID []int32) error
+	DecideIssuanceRequest(ctx context.Context, arg DecideIssuanceRequestParams) (IssuanceRequests, error)
+	DeleteFleet(ctx context.Context, arg DeleteFleetParams) (int64, error)
+	DeleteFleetVehicle(ctx context.Context, arg DeleteFleetVehicleParams) (int64, error)
+	DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error
+	DeleteNICProductRiskTypes(ctx context.Context, productID []int32) error
+	DeletePlateRiskRule(ctx context.Context, id uuid.UUID) (int64, error)
+	DeleteRateTable(ctx context.Context, id uuid.UUID) (int64, error)
+	FailInterruptedIssuanceRequests(ctx context.Context, arg FailInterruptedIssuanceRequestsParams) ([]uuid.UUID, error)
+	FinishSyncRun(ctx context.Context, arg FinishSyncRunParams) error
+	GetAPIClient(ctx context.Context, id uuid.UUID) (ApiClients, error)
+	GetAPIClientByKeyPrefix(ctx context.Context, keyPrefix string) (ApiClients, error)
+	GetAPIClients(ctx context.Context) ([]ApiClients, error)
+	GetAccessDenials(ctx context.Context, arg GetAccessDenialsParams) ([]GetAccessDenialsRow, error)
+	GetActiveRiskTypesInCategories(ctx context.Context, riskCategoryIds []uuid.UUID) ([]RiskTypes, error)
+	GetAuditChain(ctx context.Context, arg GetAuditChainParams) ([]AuditLog, error)
+	GetAuditEntries(ctx context.Context, arg GetAuditEntriesParams) ([]AuditLog, error)
+	GetDuplicateStickerNumbers(ctx context.Context, arg GetDuplicateStickerNumbersParams) ([]GetDuplicateStickerNumbersRow, error)
+	GetFleet(ctx context.Context, arg GetFleetParams) (Fleets, error)
+	GetFleetVehicles(ctx context.Context, arg GetFleetVehiclesParams) ([]FleetVehicles, error)
+	GetFleets(ctx context.Context, arg GetFleetsParams) ([]Fleets, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKeys, error)
+	GetIssuanceRequest(ctx context.Context, id uuid.UUID) (IssuanceRequests, error)
+	GetIssuanceRequestEvents(ctx context.Context, requestID uuid.UUID) ([]GetIssuanceRequestEventsRow, error)
+	GetIssuanceRequests(ctx context.Context, arg GetIssuanceRequestsParams) ([]IssuanceRequests, error)
+	GetLastAuditEntry(ctx context.Context) (GetLastAuditEntryRow, error)
+	GetLastSuccessfulSyncRun(ctx context.Context, catalog string) (SyncRuns, error)
+	GetLatestFleetVehicleChecks(ctx context.Context, fleetID uuid.UUID) ([]FleetVehicleChecks, error)
+	GetNICProductRiskTypes(ctx context.Context) ([]GetNICProductRiskTypesRow, error)
+	GetOpenFleetAlerts(ctx context.Context, fleetID uuid.UUID) ([]FleetAlerts, error)
+	GetPlateRiskRules(ctx context.Context) ([]GetPlateRiskRulesRow, error)
+	GetPlatesWithMultipleActiveStickers(ctx context.Context, arg GetPlatesWithMultipleActiveStickersParams) ([]GetPlatesWithMultipleActiveStickersRow, error)
+	GetProduct(ctx context.Context, id uuid.UUID) (Products, error)
+	GetProductByCode(ctx context.Context, productCode string) (Products, error)
+	GetProductByProductID(ctx context.Context, productID int32) (Products, error)
+	GetProductHistory(ctx context.Context, productID int32) ([]ProductHistory, error)
+	GetProductRiskTypes(ctx context.Context, productID int32) ([]GetProductRiskTypesRow, error)
 	GetProducts(ctx context.Context) ([]Products, error)
+	GetProductsAsOf(ctx context.Context, asOf pgtype.Timestamptz) ([]GetProductsAsOfRow, error)
+	GetQuotaUsage(ctx context.Context, arg GetQuotaUsageParams) (int32, error)
+	GetQuote(ctx context.Context, id uuid.UUID) (Quotes, error)
+	GetRateTable(ctx context.Context, id uuid.UUID) (RateTables, error)
+	GetRateTableFor(ctx context.Context, arg GetRateTableForParams) (RateTables, error)
+	GetRateTables(ctx context.Context) ([]RateTables, error)
+	GetRiskCategories(ctx context.Context) ([]GetRiskCategoriesRow, error)
 	GetRiskType(ctx context.Context) ([]RiskTypes, error)
+	GetRiskTypeByCode(ctx context.Context, riskTypeCode string) (RiskTypes, error)
+	GetRiskTypeByID(ctx context.Context, id uuid.UUID) (RiskTypes, error)
+	GetRiskTypeByRiskTypeID(ctx context.Context, riskTypeID int32) (RiskTypes, error)
+	GetRiskTypeHistory(ctx context.Context, riskTypeID int32) ([]RiskTypeHistory, error)
+	GetRiskTypesAsOf(ctx context.Context, asOf pgtype.Timestamptz) ([]GetRiskTypesAsOfRow, error)
+	GetServicePrices(ctx context.Context) ([]ServicePrices, error)
+	GetStickerRegistryEntries(ctx context.Context, arg GetStickerRegistryEntriesParams) ([]StickerRegistry, error)
+	GetStickerRegistryEntry(ctx context.Context, id uuid.UUID) (StickerRegistry, error)
+	GetSyncRuns(ctx context.Context, arg GetSyncRunsParams) ([]SyncRuns, error)
+	GetTenant(ctx context.Context, id uuid.UUID) (Tenants, error)
+	GetTenants(ctx context.Context) ([]Tenants, error)
+	GetUsageTotals(ctx context.Context, arg GetUsageTotalsParams) ([]GetUsageTotalsRow, error)
+	InsertNICProductRiskTypes(ctx context.Context, arg InsertNICProductRiskTypesParams) error
+	LinkRiskTypeCategories(ctx context.Context) error
+	LockAuditLog(ctx context.Context) error
+	OpenFleetAlert(ctx context.Context, arg OpenFleetAlertParams) (bool, error)
+	OpenProductVersions(ctx context.Context, arg OpenProductVersionsParams) error
+	OpenRiskTypeVersions(ctx context.Context, arg OpenRiskTypeVersionsParams) error
+	RefreshRiskCategories(ctx context.Context) error
+	RefundQuota(ctx context.Context, arg RefundQuotaParams) error
+	RemoveProductRiskType(ctx context.Context, arg RemoveProductRiskTypeParams) (int64, error)
+	ResolveFleetAlert(ctx context.Context, arg ResolveFleetAlertParams) (int64, error)
+	ResolveFleetAlertsByType(ctx context.Context, arg ResolveFleetAlertsByTypeParams) error
+	RevokeAPIClient(ctx context.Context, id uuid.UUID) (ApiClients, error)
+	RotateAPIClientKey(ctx context.Context, arg RotateAPIClientKeyParams) (ApiClients, error)
+	SearchProducts(ctx context.Context, arg SearchProductsParams) ([]SearchProductsRow, error)
+	SearchRiskTypes(ctx context.Context, arg SearchRiskTypesParams) ([]SearchRiskTypesRow, error)
+	SetAPIClientLimits(ctx context.Context, arg SetAPIClientLimitsParams) (ApiClients, error)
+	SetAPIClientRoles(ctx context.Context, arg SetAPIClientRolesParams) (ApiClients, error)
+	SetAPIClientTenant(ctx context.Context, arg SetAPIClientTenantParams) (ApiClients, error)
+	SetStickerRegistryStatus(ctx context.Context, arg SetStickerRegistryStatusParams) (StickerRegistry, error)
+	StartIssuanceRequest(ctx context.Context, id uuid.UUID) (IssuanceRequests, error)
+	TouchAPIClient(ctx context.Context, id uuid.UUID) error
+	UpdateFleet(ctx context.Context, arg UpdateFleetParams) (Fleets, error)
+	UpdateRateTable(ctx context.Context, arg UpdateRateTableParams) (RateTables, error)
+	UpdateTenant(ctx context.Context, arg UpdateTenantParams) (Tenants, error)
+	UpsertFleetVehicles(ctx context.Context, arg UpsertFleetVehiclesParams) (int64, error)
+	UpsertProducts(ctx context.Context, arg UpsertProductsParams) error
+	UpsertRiskTypes(ctx context.Context, arg UpsertRiskTypesParams) error
+	UpsertServicePrice(ctx context.Context, arg UpsertServicePriceParams) (ServicePrices, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+type StickerRegistryRepository struct {
+	q *pgxpool.Pool
+}
+
+func (sr *StickerRegistryRepository) RecordStickers(ctx context.Context, clientID, tenantID *uuid.UUID, branch string, stickers []domain.IssuedSticker) error {
+	params := sqlc.CreateStickerRegistryEntriesParams{
+		ClientID:        optionalUUIDPtr(clientID),
+		TenantID:        optionalUUIDPtr(tenantID),
+		Branch:          branch,
+		Plates:          make([]string, len(stickers)),
+		CanonicalPlates: make([]string, len(stickers)),
+		StickerNumbers:  make([]string, len(stickers)),
+		StickerLinks:    make([]string, len(stickers)),
+	}
+	for i, sticker := range stickers {
+		params.Plates[i] = sticker.Plate
+		params.CanonicalPlates[i] = pkg.CanonicalPlate(sticker.Plate)
+		params.StickerNumbers[i] = sticker.StickerNumber
+		params.StickerLinks[i] = sticker.StickerLink
+	}
+	q := sqlc.New(sr.q)
+	if err := q.CreateStickerRegistryEntries(ctx, params); err != nil {
+		log.Error().Err(err).Msg("Error create sticker registry entries")
+		return err
+	}
+	return nil
+}
+
+func (sr *StickerRegistryRepository) GetSticker(ctx context.Context, id uuid.UUID) (*domain.StickerRecord, error) {
+	q := sqlc.New(sr.q)
+	result, err := q.GetStickerRegistryEntry(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error get sticker registry entry")
+		return nil, err
+	}
+	return toDomainStickerRecord(result), nil
+}
+
+func (sr *StickerRegistryRepository) GetStickers(ctx context.Context, filter domain.StickerFilter) ([]*domain.StickerRecord, error) {
+	q := sqlc.New(sr.q)
+	results, err := q.GetStickerRegistryEntries(ctx, sqlc.GetStickerRegistryEntriesParams{
+		AllTenants:     filter.AllTenants,
+		TenantID:       optionalUUIDPtr(filter.TenantID),
+		CanonicalPlate: optionalText(filter.Plate),
+		StickerNumber:  optionalText(filter.StickerNumber),
+		Status:         optionalText(string(filter.Status)),
+		Branch:         optionalText(filter.Branch),
+		RowLimit:       int32(filter.Limit),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error get sticker registry entries")
+		return nil, err
+	}
+	records := make([]*domain.StickerRecord, len(results))
+	for i, result := range results {
+		records[i] = toDomainStickerRecord(result)
+	}
+	return records, nil
+}
+
+func (sr *StickerRegistryRepository) SetStickerStatus(ctx context.Context, id uuid.UUID, status domain.StickerStatus, note string) (*domain.StickerRecord, error) {
+	q := sqlc.New(sr.q)
+	result, err := q.SetStickerRegistryStatus(ctx, sqlc.SetStickerRegistryStatusParams{
+		Status:     string(status),
+		StatusNote: note,
+		ID:         id,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error set sticker registry status")
+		return nil, err
+	}
+	return toDomainStickerRecord(result), nil
+}
+
+func (sr *StickerRegistryRepository) GetStickerReconciliation(ctx context.Context, allTenants bool, tenantID *uuid.UUID) (*domain.StickerReconciliation, error) {
+	q := sqlc.New(sr.q)
+	duplicates, err := q.GetDuplicateStickerNumbers(ctx, sqlc.GetDuplicateStickerNumbersParams{
+		AllTenants: allTenants,
+		TenantID:   optionalUUIDPtr(tenantID),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error get duplicate sticker numbers")
+		return nil, err
+	}
+	multiple, err := q.GetPlatesWithMultipleActiveStickers(ctx, sqlc.GetPlatesWithMultipleActiveStickersParams{
+		AllTenants: allTenants,
+		TenantID:   optionalUUIDPtr(tenantID),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error get plates with multiple active stickers")
+		return nil, err
+	}
+
+	report := &domain.StickerReconciliation{
+		GeneratedAt:      time.Now(),
+		DuplicateNumbers: make([]domain.DuplicateStickerNumber, len(duplicates)),
+		MultipleActive:   make([]domain.PlateStickers, len(multiple)),
+	}
+	for i, d := range duplicates {
+		report.DuplicateNumbers[i] = domain.DuplicateStickerNumber{
+			StickerNumber: d.StickerNumber,
+			Plates:        d.Plates,
+			Entries:       int(d.Entries),
+			FirstIssuedAt: d.FirstIssuedAt.Time,
+			LastIssuedAt:  d.LastIssuedAt.Time,
+		}
+	}
+	for i, m := range multiple {
+		report.MultipleActive[i] = domain.PlateStickers{
+			Plate:          m.CanonicalPlate,
+			StickerNumbers: m.StickerNumbers,
+			Entries:        int(m.Entries),
+			LastIssuedAt:   m.LastIssuedAt.Time,
+		}
+	}
+	return report, nil
+}
+
+func toDomainStickerRecord(r sqlc.StickerRegistry) *domain.StickerRecord {
+	return &domain.StickerRecord{
+		ID:              r.ID,
+		Plate:           r.Plate,
+		CanonicalPlate:  r.CanonicalPlate,
+		StickerNumber:   r.StickerNumber,
+		StickerLink:     r.StickerLink,
+		ClientID:        uuidPtr(r.ClientID),
+		TenantID:        uuidPtr(r.TenantID),
+		Branch:          r.Branch,
+		Status:          domain.StickerStatus(r.Status),
+		StatusNote:      r.StatusNote,
+		IssuedAt:        r.IssuedAt.Time,
+		StatusChangedAt: timePtr(r.StatusChangedAt),
+	}
+}
+
+func NewStickerRegistryRepository(pool *pgxpool.Pool) *StickerRegistryRepository {
+	return &StickerRegistryRepository{q: pool}
+}
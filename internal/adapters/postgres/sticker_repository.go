@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 	"sync"
@@ -12,9 +11,12 @@ import (
 
 	"github.com/godsent-code/midtools/configs"
 	"github.com/godsent-code/midtools/internal/domain"
-	"golang.org/x/time/rate"
 )
 
+// nicStickerPath is NIC's sticker generate endpoint. Stickers used to be
+// requested from the brown card endpoint by mistake.
+const nicStickerPath = "/public-api/generate-sticker"
+
 type StickerRepository struct {
 	config configs.Config
 }
@@ -31,13 +33,18 @@ type nicStickerResponse struct {
 }
 
 func (r *StickerRepository) GetStickers(ctx context.Context, cars []string) ([]domain.Sticker, error) {
+	account, err := nicAccountFor(ctx, r.config)
+	if err != nil {
+		return nil, err
+	}
+
 	results := make([]domain.Sticker, 0, len(cars))
 	var mu sync.Mutex
 
-	workerCount := 5
+	workerCount := account.workers()
 	jobs := make(chan string)
 
-	limiter := rate.NewLimiter(rate.Every(300*time.Millisecond), 2)
+	limiter := account.limiter()
 
 	client := &http.Client{
 		Timeout: 5 * time.Second,
@@ -53,14 +60,19 @@ func (r *StickerRepository) GetStickers(ctx context.Context, cars []string) ([]d
 
 			for car := range jobs {
 
-				if err := limiter.Wait(ctx); err != nil {
-					return
-				}
-
 				result := domain.Sticker{
 					RegistrationNumber: car,
 				}
 
+				// A failed wait still yields a result, so the workers keep
+				// draining jobs and the sender is never left blocked.
+				if err := limiter.Wait(ctx); err != nil {
+					result.Success = false
+					result.Message = err.Error()
+					r.appendResult(&mu, &results, result)
+					continue
+				}
+
 				payload := map[string]interface{}{
 					"data": map[string]string{
 						"registrationNumber": car,
@@ -78,7 +90,7 @@ func (r *StickerRepository) GetStickers(ctx context.Context, cars []string) ([]d
 				req, err := http.NewRequestWithContext(
 					ctx,
 					http.MethodPost,
-					r.config.ApiEndPoint+"/public-api/generate-browncard",
+					account.url(nicStickerPath),
 					bytes.NewBuffer(jsonData),
 				)
 				if err != nil {
@@ -88,7 +100,7 @@ func (r *StickerRepository) GetStickers(ctx context.Context, cars []string) ([]d
 					continue
 				}
 
-				req.Header.Set("Authorization", "x-api-key "+r.config.ApiKey)
+				account.authorize(req)
 				req.Header.Set("Content-Type", "application/json")
 
 				resp, err := client.Do(req)
@@ -132,7 +144,6 @@ func (r *StickerRepository) GetStickers(ctx context.Context, cars []string) ([]d
 	close(jobs)
 
 	wg.Wait()
-	fmt.Println(results)
 	return results, nil
 }
 
@@ -142,6 +153,16 @@ func (r *StickerRepository) appendResult(mu *sync.Mutex, results *[]domain.Stick
 	mu.Unlock()
 }
 
+// PlanStickers lists the NIC calls GetStickers would make for cars, without making
+// them.
+func (r *StickerRepository) PlanStickers(ctx context.Context, cars []string) (*domain.NICPlan, error) {
+	account, err := nicAccountFor(ctx, r.config)
+	if err != nil {
+		return nil, err
+	}
+	return account.plan(http.MethodPost, nicStickerPath, cars), nil
+}
+
 func NewStickerRepository(config configs.Config) *StickerRepository {
 	return &StickerRepository{config: config}
 }
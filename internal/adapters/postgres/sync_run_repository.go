@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+type SyncRunRepository struct {
+	q *pgxpool.Pool
+}
+
+func (srr *SyncRunRepository) StartSyncRun(ctx context.Context, run domain.SyncRun) (*domain.SyncRun, error) {
+	q := sqlc.New(srr.q)
+	result, err := q.CreateSyncRun(ctx, sqlc.CreateSyncRunParams{
+		Catalog:       string(run.Catalog),
+		TriggerSource: run.TriggerSource,
+		DryRun:        run.DryRun,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error create sync run")
+		return nil, err
+	}
+	return toDomainSyncRun(result), nil
+}
+
+func (srr *SyncRunRepository) FinishSyncRun(ctx context.Context, run domain.SyncRun) error {
+	changes, err := json.Marshal(nonNil(run.Changes))
+	if err != nil {
+		return err
+	}
+	rejected, err := json.Marshal(nonNil(run.Rejected))
+	if err != nil {
+		return err
+	}
+
+	q := sqlc.New(srr.q)
+	err = q.FinishSyncRun(ctx, sqlc.FinishSyncRunParams{
+		ID:              run.ID,
+		Outcome:         string(run.Outcome),
+		Error:           optionalText(run.Error),
+		UpstreamCount:   int32(run.UpstreamCount),
+		Added:           int32(run.Summary.Added),
+		Updated:         int32(run.Summary.Updated),
+		Deactivated:     int32(run.Summary.Deactivated),
+		Unchanged:       int32(run.Summary.Unchanged),
+		Changes:         changes,
+		Rejected:        rejected,
+		PayloadChecksum: optionalText(run.Checksum),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error finish sync run")
+		return err
+	}
+	return nil
+}
+
+func (srr *SyncRunRepository) GetSyncRuns(ctx context.Context, catalog domain.Catalog, limit int) ([]*domain.SyncRun, error) {
+	q := sqlc.New(srr.q)
+	results, err := q.GetSyncRuns(ctx, sqlc.GetSyncRunsParams{
+		Catalog:  string(catalog),
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error get sync runs")
+		return nil, err
+	}
+	runs := make([]*domain.SyncRun, len(results))
+	for i, result := range results {
+		runs[i] = toDomainSyncRun(result)
+	}
+	return runs, nil
+}
+
+// GetLastSuccessfulSyncRun returns the most recent run that left the catalog
+// in line with NIC, either by applying changes or by finding none to apply.
+func (srr *SyncRunRepository) GetLastSuccessfulSyncRun(ctx context.Context, catalog domain.Catalog) (*domain.SyncRun, error) {
+	q := sqlc.New(srr.q)
+	result, err := q.GetLastSuccessfulSyncRun(ctx, string(catalog))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error get last successful sync run")
+		return nil, err
+	}
+	return toDomainSyncRun(result), nil
+}
+
+func toDomainSyncRun(r sqlc.SyncRuns) *domain.SyncRun {
+	run := &domain.SyncRun{
+		ID:            r.ID,
+		Catalog:       domain.Catalog(r.Catalog),
+		TriggerSource: r.TriggerSource,
+		DryRun:        r.DryRun,
+		StartedAt:     r.StartedAt.Time,
+		Outcome:       domain.SyncOutcome(r.Outcome),
+		Error:         r.Error.String,
+		UpstreamCount: int(r.UpstreamCount),
+		Summary: domain.SyncSummary{
+			Added:       int(r.Added),
+			Updated:     int(r.Updated),
+			Deactivated: int(r.Deactivated),
+			Unchanged:   int(r.Unchanged),
+		},
+		Changes:  make([]domain.SyncChange, 0),
+		Rejected: make([]domain.SyncRejection, 0),
+		Checksum: r.PayloadChecksum.String,
+	}
+	if r.FinishedAt.Valid {
+		run.FinishedAt = &r.FinishedAt.Time
+	}
+	if err := json.Unmarshal(r.Changes, &run.Changes); err != nil {
+		log.Warn().Err(err).Str("run", r.ID.String()).Msg("Error decode sync run changes")
+	}
+	if err := json.Unmarshal(r.Rejected, &run.Rejected); err != nil {
+		log.Warn().Err(err).Str("run", r.ID.String()).Msg("Error decode sync run rejections")
+	}
+	return run
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return make([]T, 0)
+	}
+	return s
+}
+
+// catalogChecksum fingerprints the parsed NIC payload so that an unchanged
+// catalog can be recognised without diffing it against the database.
+func catalogChecksum(records any, rejected []domain.SyncRejection) (string, error) {
+	payload, err := json.Marshal(struct {
+		Records  any                    `json:"records"`
+		Rejected []domain.SyncRejection `json:"rejected"`
+	}{records, rejected})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func NewSyncRunRepository(pool *pgxpool.Pool) *SyncRunRepository {
+	return &SyncRunRepository{q: pool}
+}
+
+// fieldChanges takes (field, before, after) triples and returns the ones
+// whose values differ.
+func fieldChanges(values ...string) []domain.FieldChange {
+	changes := make([]domain.FieldChange, 0)
+	for i := 0; i+2 < len(values); i += 3 {
+		if values[i+1] != values[i+2] {
+			changes = append(changes, domain.FieldChange{
+				Field:  values[i],
+				Before: values[i+1],
+				After:  values[i+2],
+			})
+		}
+	}
+	return changes
+}
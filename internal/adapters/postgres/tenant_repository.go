@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg/secretbox"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+var errNoTenantSecretKey = errors.New("TENANT_SECRET_KEY is not configured, so tenant NIC keys cannot be stored or read")
+
+// TenantRepository stores tenants with their NIC API keys sealed by box.
+// Without a box tenants can still be listed, but not created, updated or
+// used for NIC calls.
+type TenantRepository struct {
+	q   *pgxpool.Pool
+	box *secretbox.Box
+}
+
+func (tr *TenantRepository) CreateTenant(ctx context.Context, tenant domain.Tenant) (*domain.Tenant, error) {
+	sealed, err := tr.seal(tenant.NICAPIKey)
+	if err != nil {
+		return nil, err
+	}
+	q := sqlc.New(tr.q)
+	result, err := q.CreateTenant(ctx, sqlc.CreateTenantParams{
+		Name:                 tenant.Name,
+		NicEnvironment:       string(tenant.NICEnvironment),
+		NicApiKeyEncrypted:   sealed,
+		NicRequestIntervalMs: int32(tenant.NICRateLimit.RequestIntervalMs),
+		NicBurst:             int32(tenant.NICRateLimit.Burst),
+		NicWorkers:           int32(tenant.NICRateLimit.Workers),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, domain.ErrConflict
+		}
+		log.Error().Err(err).Msg("Error create tenant")
+		return nil, err
+	}
+	return toDomainTenant(result), nil
+}
+
+// GetTenants lists tenants without their NIC keys.
+func (tr *TenantRepository) GetTenants(ctx context.Context) ([]*domain.Tenant, error) {
+	q := sqlc.New(tr.q)
+	results, err := q.GetTenants(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error get tenants")
+		return nil, err
+	}
+	tenants := make([]*domain.Tenant, len(results))
+	for i, result := range results {
+		tenants[i] = toDomainTenant(result)
+	}
+	return tenants, nil
+}
+
+// GetTenant returns a tenant with its NIC key decrypted.
+func (tr *TenantRepository) GetTenant(ctx context.Context, id uuid.UUID) (*domain.Tenant, error) {
+	q := sqlc.New(tr.q)
+	result, err := q.GetTenant(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		log.Error().Err(err).Msg("Error get tenant")
+		return nil, err
+	}
+	tenant := toDomainTenant(result)
+	if tenant.NICAPIKey, err = tr.open(result.NicApiKeyEncrypted); err != nil {
+		log.Error().Err(err).Str("tenant", id.String()).Msg("Error decrypt tenant NIC key")
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// UpdateTenant replaces every field of a tenant, re-sealing its NIC key.
+func (tr *TenantRepository) UpdateTenant(ctx context.Context, tenant domain.Tenant) (*domain.Tenant, error) {
+	sealed, err := tr.seal(tenant.NICAPIKey)
+	if err != nil {
+		return nil, err
+	}
+	q := sqlc.New(tr.q)
+	result, err := q.UpdateTenant(ctx, sqlc.UpdateTenantParams{
+		Name:                 tenant.Name,
+		NicEnvironment:       string(tenant.NICEnvironment),
+		NicApiKeyEncrypted:   sealed,
+		NicRequestIntervalMs: int32(tenant.NICRateLimit.RequestIntervalMs),
+		NicBurst:             int32(tenant.NICRateLimit.Burst),
+		NicWorkers:           int32(tenant.NICRateLimit.Workers),
+		ID:                   tenant.ID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, domain.ErrConflict
+		}
+		log.Error().Err(err).Msg("Error update tenant")
+		return nil, err
+	}
+	return toDomainTenant(result), nil
+}
+
+func (tr *TenantRepository) seal(key string) ([]byte, error) {
+	if tr.box == nil {
+		return nil, errNoTenantSecretKey
+	}
+	return tr.box.Seal([]byte(key))
+}
+
+func (tr *TenantRepository) open(sealed []byte) (string, error) {
+	if tr.box == nil {
+		return "", errNoTenantSecretKey
+	}
+	key, err := tr.box.Open(sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(key), nil
+}
+
+func toDomainTenant(t sqlc.Tenants) *domain.Tenant {
+	return &domain.Tenant{
+		ID:             t.ID,
+		Name:           t.Name,
+		NICEnvironment: domain.NICEnvironment(t.NicEnvironment),
+		NICRateLimit: domain.NICRateLimit{
+			RequestIntervalMs: int(t.NicRequestIntervalMs),
+			Burst:             int(t.NicBurst),
+			Workers:           int(t.NicWorkers),
+		},
+		CreatedAt: t.CreatedAt.Time,
+		UpdatedAt: t.UpdatedAt.Time,
+	}
+}
+
+func NewTenantRepository(pool *pgxpool.Pool, box *secretbox.Box) *TenantRepository {
+	return &TenantRepository{q: pool, box: box}
+}
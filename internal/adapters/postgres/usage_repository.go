@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/adapters/postgres/sqlc"
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+type UsageRepository struct {
+	q *pgxpool.Pool
+}
+
+// RecordUsage stores a metered request, priced at the service's current
+// prices.
+func (ur *UsageRepository) RecordUsage(ctx context.Context, record domain.UsageRecord) error {
+	q := sqlc.New(ur.q)
+	if err := q.CreateUsageRecord(ctx, sqlc.CreateUsageRecordParams{
+		ClientID: record.ClientID,
+		Service:  string(record.Service),
+		Plates:   int32(record.Plates),
+		NicCalls: int32(record.NICCalls),
+		Issued:   int32(record.Issued),
+	}); err != nil {
+		log.Error().Err(err).Msg("Error create usage record")
+		return err
+	}
+	return nil
+}
+
+// GetStatements totals usage recorded in [start, end) per client and
+// service, for one client or for all of them when clientID is uuid.Nil.
+// Clients without usage in the period are left out.
+func (ur *UsageRepository) GetStatements(ctx context.Context, start, end time.Time, clientID uuid.UUID) ([]*domain.Statement, error) {
+	q := sqlc.New(ur.q)
+	results, err := q.GetUsageTotals(ctx, sqlc.GetUsageTotalsParams{
+		PeriodStart: optionalTimestamptz(start),
+		PeriodEnd:   optionalTimestamptz(end),
+		ClientID:    optionalUUID(clientID),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error get usage totals")
+		return nil, err
+	}
+	statements := make([]*domain.Statement, 0)
+	var current *domain.Statement
+	for _, result := range results {
+		if current == nil || current.ClientID != result.ClientID {
+			current = &domain.Statement{
+				ClientID:   result.ClientID,
+				ClientName: result.ClientName,
+				Lines:      make([]domain.StatementLine, 0),
+			}
+			statements = append(statements, current)
+		}
+		line := domain.StatementLine{
+			Service:  domain.MeteredService(result.Service),
+			Requests: int(result.Requests),
+			Plates:   int(result.Plates),
+			NICCalls: int(result.NicCalls),
+			Issued:   int(result.Issued),
+			Amount:   domain.Money(result.Amount),
+		}
+		current.Lines = append(current.Lines, line)
+		current.Total += line.Amount
+	}
+	return statements, nil
+}
+
+func (ur *UsageRepository) GetServicePrices(ctx context.Context) ([]*domain.ServicePrice, error) {
+	q := sqlc.New(ur.q)
+	results, err := q.GetServicePrices(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error get service prices")
+		return nil, err
+	}
+	prices := make([]*domain.ServicePrice, len(results))
+	for i, result := range results {
+		prices[i] = toDomainServicePrice(result)
+	}
+	return prices, nil
+}
+
+func (ur *UsageRepository) SetServicePrice(ctx context.Context, price domain.ServicePrice) (*domain.ServicePrice, error) {
+	q := sqlc.New(ur.q)
+	result, err := q.UpsertServicePrice(ctx, sqlc.UpsertServicePriceParams{
+		Service:      string(price.Service),
+		PlatePrice:   int64(price.PlatePrice),
+		NicCallPrice: int64(price.NICCallPrice),
+		IssuedPrice:  int64(price.IssuedPrice),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error upsert service price")
+		return nil, err
+	}
+	return toDomainServicePrice(result), nil
+}
+
+func toDomainServicePrice(p sqlc.ServicePrices) *domain.ServicePrice {
+	return &domain.ServicePrice{
+		Service:      domain.MeteredService(p.Service),
+		PlatePrice:   domain.Money(p.PlatePrice),
+		NICCallPrice: domain.Money(p.NicCallPrice),
+		IssuedPrice:  domain.Money(p.IssuedPrice),
+		UpdatedAt:    p.UpdatedAt.Time,
+	}
+}
+
+func NewUsageRepository(pool *pgxpool.Pool) *UsageRepository {
+	return &UsageRepository{q: pool}
+}
@@ -11,7 +11,6 @@ import (
 
 	"github.com/godsent-code/midtools/configs"
 	"github.com/godsent-code/midtools/internal/domain"
-	"golang.org/x/time/rate"
 )
 
 type USSDCheckRepository struct {
@@ -26,13 +25,18 @@ type nicUSSDCheckResponse struct {
 }
 
 func (r *USSDCheckRepository) GetUSSDCheck(ctx context.Context, cars []string) ([]domain.USSDChecker, error) {
+	account, err := nicAccountFor(ctx, r.config)
+	if err != nil {
+		return nil, err
+	}
+
 	results := make([]domain.USSDChecker, 0, len(cars))
 	var mu sync.Mutex
 
-	workerCount := 5
+	workerCount := account.workers()
 	jobs := make(chan string)
 
-	limiter := rate.NewLimiter(rate.Every(300*time.Millisecond), 2)
+	limiter := account.limiter()
 
 	client := &http.Client{
 		Timeout: 5 * time.Second,
@@ -48,14 +52,17 @@ func (r *USSDCheckRepository) GetUSSDCheck(ctx context.Context, cars []string) (
 
 			for car := range jobs {
 
-				if err := limiter.Wait(ctx); err != nil {
-					return
-				}
-
 				result := domain.USSDChecker{
 					RegistrationNumber: car,
 				}
 
+				if err := limiter.Wait(ctx); err != nil {
+					result.Success = false
+					result.Message = err.Error()
+					r.appendResult(&mu, &results, result)
+					continue
+				}
+
 				payload := map[string]interface{}{
 					"registrationNumber": car,
 					"USERID":             "1",
@@ -75,7 +82,7 @@ func (r *USSDCheckRepository) GetUSSDCheck(ctx context.Context, cars []string) (
 				req, err := http.NewRequestWithContext(
 					ctx,
 					http.MethodPost,
-					r.config.ApiEndPoint+"/public-api/vehicle-insurance-ussd-check",
+					account.url("/public-api/vehicle-insurance-ussd-check"),
 					bytes.NewBuffer(jsonData),
 				)
 				if err != nil {
@@ -85,7 +92,7 @@ func (r *USSDCheckRepository) GetUSSDCheck(ctx context.Context, cars []string) (
 					continue
 				}
 
-				req.Header.Set("Authorization", "x-api-key "+r.config.ApiKey)
+				account.authorize(req)
 				req.Header.Set("Content-Type", "application/json")
 
 				resp, err := client.Do(req)
@@ -0,0 +1,27 @@
+package api_client
+
+import (
+	"context"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+)
+
+type APIClientPorts interface {
+	CreateAPIClient(ctx context.Context, client domain.APIClient) (*domain.APIClient, error)
+	GetAPIClients(ctx context.Context) ([]*domain.APIClient, error)
+	GetAPIClient(ctx context.Context, id uuid.UUID) (*domain.APIClient, error)
+	GetAPIClientByKeyPrefix(ctx context.Context, prefix string) (*domain.APIClient, error)
+	RotateAPIClientKey(ctx context.Context, id uuid.UUID, prefix, hash string) (*domain.APIClient, error)
+	RevokeAPIClient(ctx context.Context, id uuid.UUID) (*domain.APIClient, error)
+	SetAPIClientTenant(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) (*domain.APIClient, error)
+	SetAPIClientLimits(ctx context.Context, id uuid.UUID, limits domain.ClientLimits) (*domain.APIClient, error)
+	SetAPIClientRoles(ctx context.Context, id uuid.UUID, roles []domain.Role) (*domain.APIClient, error)
+	TouchAPIClient(ctx context.Context, id uuid.UUID) error
+	RecordAccessDenial(ctx context.Context, denial domain.AccessDenial) error
+	GetAccessDenials(ctx context.Context, clientID uuid.UUID, limit int) ([]*domain.AccessDenial, error)
+}
+
+type TenantPort interface {
+	GetTenant(ctx context.Context, id uuid.UUID) (*domain.Tenant, error)
+}
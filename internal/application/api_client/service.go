@@ -0,0 +1,406 @@
+package api_client
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultDenialLimit = 50
+	maxDenialLimit     = 500
+)
+
+var (
+	ErrInvalidAPIKey = errors.New("invalid or revoked API key")
+	ErrRevoked       = errors.New("api client is revoked")
+	ErrForbidden     = errors.New("api client lacks the required role")
+	ErrUnknownTenant = errors.New("tenant not found")
+)
+
+type APIClientService struct {
+	repo    APIClientPorts
+	tenants TenantPort
+}
+
+type APIClientInput struct {
+	Name     string
+	Roles    []string
+	TenantID string
+	// Limits defaults to domain.DefaultClientLimits when nil.
+	Limits *APIClientLimitsInput
+}
+
+// APIClientLimitsInput sets a client's limits. Zero means unlimited.
+type APIClientLimitsInput struct {
+	RequestsPerMinute int
+	PlatesPerDay      int
+	MaxBatchSize      int
+}
+
+// APIClientTenantInput moves a client to a tenant. An empty TenantID moves it
+// back to the configured NIC account.
+type APIClientTenantInput struct {
+	TenantID string
+}
+
+// APIClientUpdateInput changes an existing client. Roles replaces the
+// client's roles and is the only field that can be changed this way.
+type APIClientUpdateInput struct {
+	Roles []string
+}
+
+type AccessDenialsInput struct {
+	ClientID string
+	Limit    int
+}
+
+type APIClientOutput struct {
+	ID         uuid.UUID           `json:"id"`
+	Name       string              `json:"name"`
+	KeyPrefix  string              `json:"keyPrefix"`
+	Roles      []domain.Role       `json:"roles"`
+	TenantID   *uuid.UUID          `json:"tenantId"`
+	Limits     domain.ClientLimits `json:"limits"`
+	CreatedAt  time.Time           `json:"createdAt"`
+	LastUsedAt *time.Time          `json:"lastUsedAt"`
+	RotatedAt  *time.Time          `json:"rotatedAt"`
+	RevokedAt  *time.Time          `json:"revokedAt"`
+}
+
+// APIClientKeyOutput is returned when a key is issued. Key is only ever
+// available in this response.
+type APIClientKeyOutput struct {
+	APIClientOutput
+	Key string `json:"key"`
+}
+
+func (ai *APIClientInput) Validate() error {
+	if strings.TrimSpace(ai.Name) == "" {
+		return errors.New("name is required")
+	}
+	if err := validateRoles(ai.Roles); err != nil {
+		return err
+	}
+	if ai.Limits != nil {
+		if err := ai.Limits.Validate(); err != nil {
+			return err
+		}
+	}
+	return validateTenantID(ai.TenantID)
+}
+
+func (ui *APIClientUpdateInput) Validate() error {
+	return validateRoles(ui.Roles)
+}
+
+func validateRoles(values []string) error {
+	if len(values) == 0 {
+		return fmt.Errorf("roles must include at least one of %v", domain.Roles)
+	}
+	for _, role := range values {
+		if !slices.Contains(domain.Roles, domain.Role(strings.ToLower(strings.TrimSpace(role)))) {
+			return fmt.Errorf("unknown role %q; roles are %v", role, domain.Roles)
+		}
+	}
+	return nil
+}
+
+// toRoles normalises validated role names, dropping repeats.
+func toRoles(values []string) []domain.Role {
+	roles := make([]domain.Role, 0, len(values))
+	for _, value := range values {
+		role := domain.Role(strings.ToLower(strings.TrimSpace(value)))
+		if !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+func (li *APIClientLimitsInput) Validate() error {
+	if li.RequestsPerMinute < 0 || li.PlatesPerDay < 0 || li.MaxBatchSize < 0 {
+		return errors.New("requestsPerMinute, platesPerDay and maxBatchSize must not be negative")
+	}
+	return nil
+}
+
+func (ti *APIClientTenantInput) Validate() error {
+	return validateTenantID(ti.TenantID)
+}
+
+func validateTenantID(value string) error {
+	if value == "" {
+		return nil
+	}
+	if _, err := uuid.Parse(value); err != nil {
+		return errors.New("tenantId must be a tenant id")
+	}
+	return nil
+}
+
+func (di *AccessDenialsInput) Validate() error {
+	if di.ClientID != "" {
+		if _, err := uuid.Parse(di.ClientID); err != nil {
+			return errors.New("clientId must be an api client id")
+		}
+	}
+	if di.Limit > maxDenialLimit {
+		return fmt.Errorf("limit must be at most %d", maxDenialLimit)
+	}
+	return nil
+}
+
+// CreateAPIClient registers an application and issues its first key.
+func (as *APIClientService) CreateAPIClient(ctx context.Context, input APIClientInput) (*APIClientKeyOutput, error) {
+	key, prefix, err := domain.NewAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	roles := toRoles(input.Roles)
+	tenantID, err := as.tenantID(ctx, input.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	limits := domain.DefaultClientLimits
+	if input.Limits != nil {
+		limits = input.Limits.toDomain()
+	}
+	result, err := as.repo.CreateAPIClient(ctx, domain.APIClient{
+		Name:      strings.TrimSpace(input.Name),
+		KeyPrefix: prefix,
+		KeyHash:   domain.HashAPIKey(key),
+		Roles:     roles,
+		TenantID:  tenantID,
+		Limits:    limits,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &APIClientKeyOutput{APIClientOutput: toAPIClientOutput(result), Key: key}, nil
+}
+
+func (as *APIClientService) GetAPIClients(ctx context.Context) ([]APIClientOutput, error) {
+	results, err := as.repo.GetAPIClients(ctx)
+	if err != nil {
+		return nil, err
+	}
+	clients := make([]APIClientOutput, len(results))
+	for i, result := range results {
+		clients[i] = toAPIClientOutput(result)
+	}
+	return clients, nil
+}
+
+func (as *APIClientService) GetAPIClient(ctx context.Context, id uuid.UUID) (*APIClientOutput, error) {
+	result, err := as.repo.GetAPIClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	output := toAPIClientOutput(result)
+	return &output, nil
+}
+
+// RotateAPIClientKey issues a new key for a client. The old key stops working
+// immediately.
+func (as *APIClientService) RotateAPIClientKey(ctx context.Context, id uuid.UUID) (*APIClientKeyOutput, error) {
+	current, err := as.repo.GetAPIClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Revoked() {
+		return nil, ErrRevoked
+	}
+	key, prefix, err := domain.NewAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	result, err := as.repo.RotateAPIClientKey(ctx, id, prefix, domain.HashAPIKey(key))
+	if err != nil {
+		return nil, err
+	}
+	return &APIClientKeyOutput{APIClientOutput: toAPIClientOutput(result), Key: key}, nil
+}
+
+// RevokeAPIClient disables a client for good. Revoking twice keeps the
+// original revocation time.
+func (as *APIClientService) RevokeAPIClient(ctx context.Context, id uuid.UUID) (*APIClientOutput, error) {
+	result, err := as.repo.RevokeAPIClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	output := toAPIClientOutput(result)
+	return &output, nil
+}
+
+// SetAPIClientTenant moves a client to another tenant. Its NIC calls use the
+// new account from its next request.
+func (as *APIClientService) SetAPIClientTenant(ctx context.Context, id uuid.UUID, input APIClientTenantInput) (*APIClientOutput, error) {
+	tenantID, err := as.tenantID(ctx, input.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := as.repo.SetAPIClientTenant(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	output := toAPIClientOutput(result)
+	return &output, nil
+}
+
+// SetAPIClientLimits replaces a client's limits. Usage already counted in the
+// current windows is kept.
+func (as *APIClientService) SetAPIClientLimits(ctx context.Context, id uuid.UUID, input APIClientLimitsInput) (*APIClientOutput, error) {
+	result, err := as.repo.SetAPIClientLimits(ctx, id, input.toDomain())
+	if err != nil {
+		return nil, err
+	}
+	output := toAPIClientOutput(result)
+	return &output, nil
+}
+
+// UpdateAPIClient replaces a client's roles. The key stays the same and the
+// new roles apply from the client's next request. Revoked clients cannot be
+// changed.
+func (as *APIClientService) UpdateAPIClient(ctx context.Context, id uuid.UUID, input APIClientUpdateInput) (*APIClientOutput, error) {
+	current, err := as.repo.GetAPIClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Revoked() {
+		return nil, ErrRevoked
+	}
+	result, err := as.repo.SetAPIClientRoles(ctx, id, toRoles(input.Roles))
+	if err != nil {
+		return nil, err
+	}
+	output := toAPIClientOutput(result)
+	return &output, nil
+}
+
+func (li APIClientLimitsInput) toDomain() domain.ClientLimits {
+	return domain.ClientLimits{
+		RequestsPerMinute: li.RequestsPerMinute,
+		PlatesPerDay:      li.PlatesPerDay,
+		MaxBatchSize:      li.MaxBatchSize,
+	}
+}
+
+// tenantID parses a tenant id and checks the tenant exists. An empty value
+// is no tenant.
+func (as *APIClientService) tenantID(ctx context.Context, value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := as.tenants.GetTenant(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, ErrUnknownTenant
+		}
+		return nil, err
+	}
+	return &id, nil
+}
+
+// Authenticate returns the client a key belongs to. Unknown, malformed and
+// revoked keys all fail with ErrInvalidAPIKey so callers cannot probe which
+// keys exist. The client's tenant, if any, is loaded with its NIC key.
+func (as *APIClientService) Authenticate(ctx context.Context, key string) (*domain.APIClient, error) {
+	prefix, ok := domain.APIKeyPrefix(key)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+	client, err := as.repo.GetAPIClientByKeyPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(domain.HashAPIKey(key)), []byte(client.KeyHash)) != 1 || client.Revoked() {
+		return nil, ErrInvalidAPIKey
+	}
+	if client.TenantID != nil {
+		if client.Tenant, err = as.tenants.GetTenant(ctx, *client.TenantID); err != nil {
+			return nil, err
+		}
+	}
+	if err := as.repo.TouchAPIClient(ctx, client.ID); err != nil {
+		log.Warn().Err(err).Str("client", client.ID.String()).Msg("Could not record api client use")
+	}
+	return client, nil
+}
+
+// Authorize checks that client may act as required. Refusals are recorded
+// before ErrForbidden is returned; failing to record one does not change the
+// outcome.
+func (as *APIClientService) Authorize(ctx context.Context, client *domain.APIClient, required domain.Role, method, path string) error {
+	if client.Can(required) {
+		return nil
+	}
+	log.Warn().
+		Str("client", client.ID.String()).
+		Str("name", client.Name).
+		Str("method", method).
+		Str("path", path).
+		Str("requiredRole", string(required)).
+		Msg("Access denied")
+	if err := as.repo.RecordAccessDenial(context.WithoutCancel(ctx), domain.AccessDenial{
+		ClientID:     client.ID,
+		Method:       method,
+		Path:         path,
+		RequiredRole: required,
+		Roles:        client.Roles,
+	}); err != nil {
+		log.Error().Err(err).Str("client", client.ID.String()).Msg("Error recording access denial")
+	}
+	return ErrForbidden
+}
+
+// GetAccessDenials lists recent refusals, newest first, optionally for one
+// client.
+func (as *APIClientService) GetAccessDenials(ctx context.Context, input AccessDenialsInput) ([]*domain.AccessDenial, error) {
+	var clientID uuid.UUID
+	if input.ClientID != "" {
+		id, err := uuid.Parse(input.ClientID)
+		if err != nil {
+			return nil, err
+		}
+		clientID = id
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultDenialLimit
+	}
+	return as.repo.GetAccessDenials(ctx, clientID, limit)
+}
+
+func toAPIClientOutput(c *domain.APIClient) APIClientOutput {
+	return APIClientOutput{
+		ID:         c.ID,
+		Name:       c.Name,
+		KeyPrefix:  c.KeyPrefix,
+		Roles:      c.Roles,
+		TenantID:   c.TenantID,
+		Limits:     c.Limits,
+		CreatedAt:  c.CreatedAt,
+		LastUsedAt: c.LastUsedAt,
+		RotatedAt:  c.RotatedAt,
+		RevokedAt:  c.RevokedAt,
+	}
+}
+
+func NewAPIClientService(repo APIClientPorts, tenants TenantPort) APIClientService {
+	return APIClientService{repo: repo, tenants: tenants}
+}
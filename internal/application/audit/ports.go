@@ -0,0 +1,13 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/godsent-code/midtools/internal/domain"
+)
+
+type AuditPort interface {
+	AppendAuditEntry(ctx context.Context, entry domain.AuditEntry) (*domain.AuditEntry, error)
+	GetAuditEntries(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, error)
+	GetAuditChain(ctx context.Context, afterSeq int64, limit int) ([]*domain.AuditEntry, error)
+}
@@ -0,0 +1,196 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultEntryLimit = 50
+	maxEntryLimit     = 500
+	verifyPageSize    = 1000
+
+	// CLIActor is the actor of entries written by the command line tools.
+	CLIActor = "cli"
+)
+
+type AuditService struct {
+	repo AuditPort
+}
+
+// AuditRecord is an action to be audited. Client is nil for the command line
+// tools. Detail is stored as JSON and must not hold secrets.
+type AuditRecord struct {
+	Client  *domain.APIClient
+	Action  domain.AuditAction
+	Target  string
+	Plates  []string
+	Outcome domain.AuditOutcome
+	Detail  any
+}
+
+// AuditEntriesInput filters the log. From and To are RFC 3339 times; Before
+// is the seq of the last entry of the previous page.
+type AuditEntriesInput struct {
+	ClientID string
+	Action   string
+	Plate    string
+	From     string
+	To       string
+	Before   int
+	Limit    int
+}
+
+func (ai *AuditEntriesInput) Validate() error {
+	if ai.ClientID != "" {
+		if _, err := uuid.Parse(ai.ClientID); err != nil {
+			return errors.New("clientId must be an api client id")
+		}
+	}
+	for name, value := range map[string]string{"from": ai.From, "to": ai.To} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return fmt.Errorf("%s must be an RFC 3339 time", name)
+		}
+	}
+	if ai.Limit > maxEntryLimit {
+		return fmt.Errorf("limit must be at most %d", maxEntryLimit)
+	}
+	return nil
+}
+
+// Record appends an action to the audit log. The action has already
+// happened by the time it is recorded, so a failure to record it is logged
+// rather than returned.
+func (as *AuditService) Record(ctx context.Context, record AuditRecord) {
+	entry := domain.AuditEntry{
+		Actor:   CLIActor,
+		Action:  record.Action,
+		Target:  record.Target,
+		Plates:  make([]string, len(record.Plates)),
+		Outcome: record.Outcome,
+	}
+	if record.Client != nil {
+		entry.ClientID = &record.Client.ID
+		entry.Actor = record.Client.Name
+	}
+	for i, plate := range record.Plates {
+		entry.Plates[i] = pkg.CanonicalPlate(plate)
+	}
+	detail, err := json.Marshal(record.Detail)
+	if err != nil {
+		detail, _ = json.Marshal(map[string]string{"error": "detail could not be encoded: " + err.Error()})
+	}
+	entry.Detail = detail
+	if _, err := as.repo.AppendAuditEntry(context.WithoutCancel(ctx), entry); err != nil {
+		log.Error().Err(err).
+			Str("actor", entry.Actor).
+			Str("action", string(entry.Action)).
+			Str("target", entry.Target).
+			Strs("plates", entry.Plates).
+			Str("outcome", string(entry.Outcome)).
+			Msg("Error recording audit entry")
+	}
+}
+
+// GetEntries lists audit entries, newest first. The returned cursor is the
+// Before of the next page, or empty on the last one.
+func (as *AuditService) GetEntries(ctx context.Context, input AuditEntriesInput) ([]*domain.AuditEntry, string, error) {
+	filter := domain.AuditFilter{
+		Action:    domain.AuditAction(input.Action),
+		BeforeSeq: int64(input.Before),
+		Limit:     input.Limit,
+	}
+	if input.Plate != "" {
+		filter.Plate = pkg.CanonicalPlate(input.Plate)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultEntryLimit
+	}
+	var err error
+	if input.ClientID != "" {
+		if filter.ClientID, err = uuid.Parse(input.ClientID); err != nil {
+			return nil, "", err
+		}
+	}
+	if input.From != "" {
+		if filter.From, err = time.Parse(time.RFC3339, input.From); err != nil {
+			return nil, "", err
+		}
+	}
+	if input.To != "" {
+		if filter.To, err = time.Parse(time.RFC3339, input.To); err != nil {
+			return nil, "", err
+		}
+	}
+	entries, err := as.repo.GetAuditEntries(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	next := ""
+	if len(entries) == filter.Limit && entries[len(entries)-1].Seq > 1 {
+		next = strconv.FormatInt(entries[len(entries)-1].Seq, 10)
+	}
+	return entries, next, nil
+}
+
+// Verify walks the whole log from the first entry and checks that entries
+// are numbered without gaps, that each names the hash of the one before it
+// and that each hash matches the entry's contents. With a checkpoint from an
+// earlier run it also checks that the log still reaches the checkpoint's
+// entry, unchanged. It stops at the first entry that fails.
+func (as *AuditService) Verify(ctx context.Context, checkpoint *domain.AuditCheckpoint) (*domain.AuditVerification, error) {
+	result := &domain.AuditVerification{Valid: true, LastHash: domain.AuditGenesisHash}
+	var seq int64
+	for {
+		entries, err := as.repo.GetAuditChain(ctx, seq, verifyPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			problem := ""
+			switch {
+			case entry.Seq == seq+2:
+				problem = fmt.Sprintf("entry %d is missing", seq+1)
+			case entry.Seq != seq+1:
+				problem = fmt.Sprintf("entries %d to %d are missing", seq+1, entry.Seq-1)
+			case entry.PrevHash != result.LastHash:
+				problem = "previous hash does not match the entry before it"
+			case entry.ComputeHash() != entry.Hash:
+				problem = "hash does not match the entry's contents"
+			case checkpoint != nil && entry.Seq == checkpoint.Seq && entry.Hash != checkpoint.Hash:
+				problem = "hash does not match the checkpoint"
+			}
+			if problem != "" {
+				result.Valid, result.BrokenAt, result.Problem = false, entry.Seq, problem
+				return result, nil
+			}
+			seq = entry.Seq
+			result.Entries++
+			result.LastSeq = entry.Seq
+			result.LastHash = entry.Hash
+		}
+		if len(entries) < verifyPageSize {
+			if checkpoint != nil && seq < checkpoint.Seq {
+				result.Valid, result.BrokenAt = false, seq+1
+				result.Problem = fmt.Sprintf("entries %d to %d, covered by the checkpoint, are missing", seq+1, checkpoint.Seq)
+			}
+			return result, nil
+		}
+	}
+}
+
+func NewAuditService(repo AuditPort) AuditService {
+	return AuditService{repo: repo}
+}
@@ -0,0 +1,231 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+)
+
+// chainRepo serves a fixed chain to Verify the way AuditRepository pages
+// through the log: entries after afterSeq, in seq order.
+type chainRepo struct {
+	entries []*domain.AuditEntry
+}
+
+func (cr *chainRepo) AppendAuditEntry(context.Context, domain.AuditEntry) (*domain.AuditEntry, error) {
+	panic("not used")
+}
+
+func (cr *chainRepo) GetAuditEntries(context.Context, domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	panic("not used")
+}
+
+func (cr *chainRepo) GetAuditChain(_ context.Context, afterSeq int64, limit int) ([]*domain.AuditEntry, error) {
+	page := make([]*domain.AuditEntry, 0, limit)
+	for _, entry := range cr.entries {
+		if entry.Seq > afterSeq && len(page) < limit {
+			page = append(page, entry)
+		}
+	}
+	return page, nil
+}
+
+// chain builds n correctly linked entries numbered from 1.
+func chain(n int) []*domain.AuditEntry {
+	clientID := uuid.New()
+	entries := make([]*domain.AuditEntry, n)
+	prev := domain.AuditGenesisHash
+	for i := range entries {
+		entry := &domain.AuditEntry{
+			Seq:        int64(i + 1),
+			OccurredAt: time.Date(2026, 3, 1, 9, 0, i, 0, time.UTC),
+			ClientID:   &clientID,
+			Actor:      "fleet-app",
+			Action:     domain.AuditStickerIssue,
+			Plates:     []string{"GR123421"},
+			Outcome:    domain.AuditSucceeded,
+			Detail:     json.RawMessage(`{"stickerNumber":"S1"}`),
+			PrevHash:   prev,
+		}
+		entry.Hash = entry.ComputeHash()
+		prev = entry.Hash
+		entries[i] = entry
+	}
+	return entries
+}
+
+// rehash recomputes the hashes of entries from i on, as someone rewriting
+// the log would, so only a checkpoint can tell.
+func rehash(entries []*domain.AuditEntry, i int) {
+	for ; i < len(entries); i++ {
+		if i > 0 {
+			entries[i].PrevHash = entries[i-1].Hash
+		}
+		entries[i].Hash = entries[i].ComputeHash()
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name         string
+		entries      func() []*domain.AuditEntry
+		checkpoint   func(entries []*domain.AuditEntry) *domain.AuditCheckpoint
+		wantValid    bool
+		wantBrokenAt int64
+		wantProblem  string
+		wantEntries  int64
+	}{
+		{
+			name:        "empty log",
+			entries:     func() []*domain.AuditEntry { return nil },
+			wantValid:   true,
+			wantEntries: 0,
+		},
+		{
+			name:        "intact log",
+			entries:     func() []*domain.AuditEntry { return chain(5) },
+			wantValid:   true,
+			wantEntries: 5,
+		},
+		{
+			name:        "intact log longer than a page",
+			entries:     func() []*domain.AuditEntry { return chain(verifyPageSize + 2) },
+			wantValid:   true,
+			wantEntries: verifyPageSize + 2,
+		},
+		{
+			name: "detail changed",
+			entries: func() []*domain.AuditEntry {
+				entries := chain(5)
+				entries[2].Detail = json.RawMessage(`{"stickerNumber":"S2"}`)
+				return entries
+			},
+			wantBrokenAt: 3,
+			wantProblem:  "hash does not match the entry's contents",
+			wantEntries:  2,
+		},
+		{
+			name: "plate removed",
+			entries: func() []*domain.AuditEntry {
+				entries := chain(5)
+				entries[4].Plates = nil
+				return entries
+			},
+			wantBrokenAt: 5,
+			wantProblem:  "hash does not match the entry's contents",
+			wantEntries:  4,
+		},
+		{
+			name: "entry rewritten with a fresh hash",
+			entries: func() []*domain.AuditEntry {
+				entries := chain(5)
+				entries[1].Outcome = domain.AuditFailed
+				entries[1].Hash = entries[1].ComputeHash()
+				return entries
+			},
+			wantBrokenAt: 3,
+			wantProblem:  "previous hash does not match the entry before it",
+			wantEntries:  2,
+		},
+		{
+			name: "first entry does not start from the genesis hash",
+			entries: func() []*domain.AuditEntry {
+				entries := chain(3)
+				entries[0].PrevHash = entries[2].Hash
+				rehash(entries, 0)
+				return entries
+			},
+			wantBrokenAt: 1,
+			wantProblem:  "previous hash does not match the entry before it",
+		},
+		{
+			name: "one entry deleted",
+			entries: func() []*domain.AuditEntry {
+				entries := chain(5)
+				return append(entries[:2], entries[3:]...)
+			},
+			wantBrokenAt: 4,
+			wantProblem:  "entry 3 is missing",
+			wantEntries:  2,
+		},
+		{
+			name: "several entries deleted",
+			entries: func() []*domain.AuditEntry {
+				entries := chain(6)
+				return append(entries[:1], entries[4:]...)
+			},
+			wantBrokenAt: 5,
+			wantProblem:  "entries 2 to 4 are missing",
+			wantEntries:  1,
+		},
+		{
+			name: "first entry deleted",
+			entries: func() []*domain.AuditEntry {
+				return chain(3)[1:]
+			},
+			wantBrokenAt: 2,
+			wantProblem:  "entry 1 is missing",
+		},
+		{
+			name:    "checkpoint still matches",
+			entries: func() []*domain.AuditEntry { return chain(5) },
+			checkpoint: func(entries []*domain.AuditEntry) *domain.AuditCheckpoint {
+				return &domain.AuditCheckpoint{Seq: 3, Hash: entries[2].Hash}
+			},
+			wantValid:   true,
+			wantEntries: 5,
+		},
+		{
+			name: "log rewritten after the checkpoint was taken",
+			entries: func() []*domain.AuditEntry {
+				entries := chain(5)
+				entries[1].Target = "someone else"
+				rehash(entries, 1)
+				return entries
+			},
+			checkpoint: func([]*domain.AuditEntry) *domain.AuditCheckpoint {
+				return &domain.AuditCheckpoint{Seq: 3, Hash: chain(5)[2].Hash}
+			},
+			wantBrokenAt: 3,
+			wantProblem:  "hash does not match the checkpoint",
+			wantEntries:  2,
+		},
+		{
+			name: "log truncated before the checkpoint",
+			entries: func() []*domain.AuditEntry {
+				return chain(3)
+			},
+			checkpoint: func([]*domain.AuditEntry) *domain.AuditCheckpoint {
+				return &domain.AuditCheckpoint{Seq: 5, Hash: chain(5)[4].Hash}
+			},
+			wantBrokenAt: 4,
+			wantProblem:  "entries 4 to 5, covered by the checkpoint, are missing",
+			wantEntries:  3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := tt.entries()
+			var checkpoint *domain.AuditCheckpoint
+			if tt.checkpoint != nil {
+				checkpoint = tt.checkpoint(entries)
+			}
+			service := NewAuditService(&chainRepo{entries: entries})
+			result, err := service.Verify(context.Background(), checkpoint)
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if result.Valid != tt.wantValid || result.BrokenAt != tt.wantBrokenAt || result.Problem != tt.wantProblem {
+				t.Errorf("Verify() = valid %t, broken at %d, %q; want valid %t, broken at %d, %q",
+					result.Valid, result.BrokenAt, result.Problem, tt.wantValid, tt.wantBrokenAt, tt.wantProblem)
+			}
+			if result.Entries != tt.wantEntries {
+				t.Errorf("Verify() checked %d entries, want %d", result.Entries, tt.wantEntries)
+			}
+		})
+	}
+}
@@ -8,4 +8,5 @@ import (
 
 type BrownCardService interface {
 	GetBrownCard(ctx context.Context, cars []string) ([]domain.BrownCard, error)
+	PlanBrownCards(ctx context.Context, cars []string) (*domain.NICPlan, error)
 }
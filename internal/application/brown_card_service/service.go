@@ -5,6 +5,7 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/godsent-code/midtools/internal/domain"
 	"github.com/godsent-code/midtools/pkg"
 )
 
@@ -31,9 +32,7 @@ func (bci *BrownCardInput) Validate() error {
 }
 
 func (bc *BrownCard) GetBrownCard(ctx context.Context, input BrownCardInput) ([]BrownCardOutput, error) {
-	parts := strings.FieldsFunc(input.Cars, func(r rune) bool {
-		return r == ',' || r == '\n' || r == '\r' || r == '\t'
-	})
+	parts := pkg.SplitCars(input.Cars)
 
 	if len(parts) == 0 {
 		return nil, errors.New("cars is required")
@@ -57,6 +56,8 @@ func (bc *BrownCard) GetBrownCard(ctx context.Context, input BrownCardInput) ([]
 			correctCars = append(correctCars, parts[i])
 		}
 	}
+	// A plate repeated in any spelling is only generated once.
+	correctCars, _ = pkg.DedupePlates(correctCars)
 
 	results, err := bc.repo.GetBrownCard(ctx, correctCars)
 	if err != nil {
@@ -76,6 +77,30 @@ func (bc *BrownCard) GetBrownCard(ctx context.Context, input BrownCardInput) ([]
 	return brownCards, nil
 }
 
+// PlanBrownCard works out what GetBrownCard would do with input, down to the
+// NIC calls it would make, without calling NIC.
+func (bc *BrownCard) PlanBrownCard(ctx context.Context, input BrownCardInput) (*domain.DryRun, error) {
+	parts := pkg.SplitCars(input.Cars)
+	if len(parts) == 0 {
+		return nil, errors.New("cars is required")
+	}
+	invalid := make([]domain.InvalidPlate, 0)
+	correctCars := make([]string, 0)
+	for _, part := range parts {
+		if exists, num := pkg.ValidateGhanaLicensePlate(part); !exists {
+			invalid = append(invalid, domain.InvalidPlate{CarNumber: part, Message: num})
+		} else {
+			correctCars = append(correctCars, part)
+		}
+	}
+	correctCars, duplicates := pkg.DedupePlates(correctCars)
+	plan, err := bc.repo.PlanBrownCards(ctx, correctCars)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewDryRun(len(parts), invalid, duplicates, plan), nil
+}
+
 func NewBrownCard(repo BrownCardService) BrownCard {
 	return BrownCard{repo: repo}
 }
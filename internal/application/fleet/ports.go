@@ -0,0 +1,19 @@
+package fleet
+
+import (
+	"context"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+)
+
+type FleetPorts interface {
+	CreateFleet(ctx context.Context, fleet domain.Fleet) (*domain.Fleet, error)
+	GetFleets(ctx context.Context, scope domain.FleetScope) ([]*domain.Fleet, error)
+	GetFleet(ctx context.Context, scope domain.FleetScope, id uuid.UUID) (*domain.Fleet, error)
+	UpdateFleet(ctx context.Context, scope domain.FleetScope, fleet domain.Fleet) (*domain.Fleet, error)
+	DeleteFleet(ctx context.Context, scope domain.FleetScope, id uuid.UUID) error
+	UpsertFleetVehicles(ctx context.Context, scope domain.FleetScope, fleetID uuid.UUID, vehicles []domain.FleetVehicle) (int64, error)
+	GetFleetVehicles(ctx context.Context, scope domain.FleetScope, fleetID uuid.UUID) ([]*domain.FleetVehicle, error)
+	DeleteFleetVehicle(ctx context.Context, scope domain.FleetScope, fleetID uuid.UUID, plate string) error
+}
@@ -0,0 +1,320 @@
+package fleet
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+)
+
+var ErrEmptyFleet = errors.New("fleet has no vehicles")
+
+type FleetService struct {
+	repo FleetPorts
+}
+
+type FleetInput struct {
+	Name        string
+	Description string
+}
+
+type FleetOutput struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	TenantID    *uuid.UUID `json:"tenantId"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+type FleetVehicleInput struct {
+	Plate          string
+	Labels         []string
+	OwnerReference string
+}
+
+type FleetVehicleOutput struct {
+	ID             uuid.UUID `json:"id"`
+	Plate          string    `json:"plate"`
+	Labels         []string  `json:"labels"`
+	OwnerReference string    `json:"ownerReference"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+type RejectedVehicle struct {
+	Line    int    `json:"line,omitempty"`
+	Plate   string `json:"plate"`
+	Message string `json:"message"`
+}
+
+type ImportOutput struct {
+	Saved    int64             `json:"saved"`
+	Rejected []RejectedVehicle `json:"rejected"`
+}
+
+func (fi *FleetInput) Validate() error {
+	if strings.TrimSpace(fi.Name) == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+// CreateFleet creates a fleet owned by the client's tenant.
+func (fs *FleetService) CreateFleet(ctx context.Context, client *domain.APIClient, input FleetInput) (*FleetOutput, error) {
+	result, err := fs.repo.CreateFleet(ctx, domain.Fleet{
+		Name:        strings.TrimSpace(input.Name),
+		Description: input.Description,
+		TenantID:    client.TenantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toFleetOutput(result), nil
+}
+
+// GetFleets lists the fleets of the client's tenant, or every fleet for
+// admins. Fleets of other tenants are not found by the other methods either.
+func (fs *FleetService) GetFleets(ctx context.Context, client *domain.APIClient) ([]FleetOutput, error) {
+	results, err := fs.repo.GetFleets(ctx, domain.FleetScopeFor(client))
+	if err != nil {
+		return nil, err
+	}
+	fleets := make([]FleetOutput, len(results))
+	for i, result := range results {
+		fleets[i] = *toFleetOutput(result)
+	}
+	return fleets, nil
+}
+
+func (fs *FleetService) GetFleet(ctx context.Context, client *domain.APIClient, id uuid.UUID) (*FleetOutput, error) {
+	result, err := fs.repo.GetFleet(ctx, domain.FleetScopeFor(client), id)
+	if err != nil {
+		return nil, err
+	}
+	return toFleetOutput(result), nil
+}
+
+func (fs *FleetService) UpdateFleet(ctx context.Context, client *domain.APIClient, id uuid.UUID, input FleetInput) (*FleetOutput, error) {
+	result, err := fs.repo.UpdateFleet(ctx, domain.FleetScopeFor(client), domain.Fleet{
+		ID:          id,
+		Name:        strings.TrimSpace(input.Name),
+		Description: input.Description,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toFleetOutput(result), nil
+}
+
+func (fs *FleetService) DeleteFleet(ctx context.Context, client *domain.APIClient, id uuid.UUID) error {
+	return fs.repo.DeleteFleet(ctx, domain.FleetScopeFor(client), id)
+}
+
+func (fs *FleetService) GetFleetVehicles(ctx context.Context, client *domain.APIClient, fleetID uuid.UUID) ([]FleetVehicleOutput, error) {
+	scope := domain.FleetScopeFor(client)
+	if _, err := fs.repo.GetFleet(ctx, scope, fleetID); err != nil {
+		return nil, err
+	}
+	results, err := fs.repo.GetFleetVehicles(ctx, scope, fleetID)
+	if err != nil {
+		return nil, err
+	}
+	vehicles := make([]FleetVehicleOutput, len(results))
+	for i, v := range results {
+		vehicles[i] = FleetVehicleOutput{
+			ID:             v.ID,
+			Plate:          v.Plate,
+			Labels:         v.Labels,
+			OwnerReference: v.OwnerReference,
+			CreatedAt:      v.CreatedAt,
+			UpdatedAt:      v.UpdatedAt,
+		}
+	}
+	return vehicles, nil
+}
+
+// AddVehicles validates and stores vehicles in the fleet. Plates already in
+// the fleet have their labels and owner reference replaced.
+func (fs *FleetService) AddVehicles(ctx context.Context, client *domain.APIClient, fleetID uuid.UUID, inputs []FleetVehicleInput) (*ImportOutput, error) {
+	scope := domain.FleetScopeFor(client)
+	if _, err := fs.repo.GetFleet(ctx, scope, fleetID); err != nil {
+		return nil, err
+	}
+
+	output := &ImportOutput{Rejected: make([]RejectedVehicle, 0)}
+	vehicles := make([]domain.FleetVehicle, 0, len(inputs))
+	seen := make(map[string]int, len(inputs))
+
+	for _, input := range inputs {
+		valid, msg := pkg.ValidateGhanaLicensePlate(input.Plate)
+		if !valid {
+			output.Rejected = append(output.Rejected, RejectedVehicle{Plate: input.Plate, Message: msg})
+			continue
+		}
+		vehicle := domain.FleetVehicle{
+			FleetID:        fleetID,
+			Plate:          pkg.CanonicalPlate(input.Plate),
+			Labels:         cleanLabels(input.Labels),
+			OwnerReference: strings.TrimSpace(input.OwnerReference),
+		}
+		// A plate listed twice would make the upsert touch the same row
+		// twice, which Postgres rejects; the last occurrence wins.
+		if i, ok := seen[vehicle.Plate]; ok {
+			vehicles[i] = vehicle
+			continue
+		}
+		seen[vehicle.Plate] = len(vehicles)
+		vehicles = append(vehicles, vehicle)
+	}
+
+	if len(vehicles) == 0 {
+		return output, nil
+	}
+
+	saved, err := fs.repo.UpsertFleetVehicles(ctx, scope, fleetID, vehicles)
+	if err != nil {
+		return nil, err
+	}
+	output.Saved = saved
+	return output, nil
+}
+
+// ImportVehiclesCSV reads a CSV with a header row containing at least a
+// "plate" column, and optionally "labels" (separated by ';' or '|') and
+// "owner_reference".
+func (fs *FleetService) ImportVehiclesCSV(ctx context.Context, client *domain.APIClient, fleetID uuid.UUID, r io.Reader) (*ImportOutput, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(h))
+		name = strings.ReplaceAll(name, " ", "_")
+		if name == "ownerreference" {
+			name = "owner_reference"
+		}
+		columns[name] = i
+	}
+	plateCol, ok := columns["plate"]
+	if !ok {
+		return nil, errors.New("csv must have a plate column")
+	}
+
+	inputs := make([]FleetVehicleInput, 0)
+	lines := make([]int, 0)
+	rejected := make([]RejectedVehicle, 0)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv: %w", err)
+		}
+		plate := field(record, plateCol)
+		if plate == "" {
+			rejected = append(rejected, RejectedVehicle{Line: line, Message: "Empty plate number"})
+			continue
+		}
+		input := FleetVehicleInput{Plate: plate}
+		if col, ok := columns["labels"]; ok {
+			input.Labels = strings.FieldsFunc(field(record, col), func(r rune) bool {
+				return r == ';' || r == '|'
+			})
+		}
+		if col, ok := columns["owner_reference"]; ok {
+			input.OwnerReference = field(record, col)
+		}
+		inputs = append(inputs, input)
+		lines = append(lines, line)
+	}
+
+	output, err := fs.AddVehicles(ctx, client, fleetID, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	// AddVehicles only knows plates; map rejections back to CSV lines.
+	lineByPlate := make(map[string]int, len(inputs))
+	for i, input := range inputs {
+		if _, ok := lineByPlate[input.Plate]; !ok {
+			lineByPlate[input.Plate] = lines[i]
+		}
+	}
+	for i := range output.Rejected {
+		output.Rejected[i].Line = lineByPlate[output.Rejected[i].Plate]
+	}
+	output.Rejected = append(rejected, output.Rejected...)
+	return output, nil
+}
+
+func (fs *FleetService) RemoveVehicle(ctx context.Context, client *domain.APIClient, fleetID uuid.UUID, plate string) error {
+	return fs.repo.DeleteFleetVehicle(ctx, domain.FleetScopeFor(client), fleetID, pkg.CanonicalPlate(plate))
+}
+
+// FleetCars returns the fleet's plates in the comma separated form the
+// vehicle services accept as `cars`.
+func (fs *FleetService) FleetCars(ctx context.Context, client *domain.APIClient, fleetID uuid.UUID) (string, error) {
+	scope := domain.FleetScopeFor(client)
+	vehicles, err := fs.repo.GetFleetVehicles(ctx, scope, fleetID)
+	if err != nil {
+		return "", err
+	}
+	if len(vehicles) == 0 {
+		if _, err := fs.repo.GetFleet(ctx, scope, fleetID); err != nil {
+			return "", err
+		}
+		return "", ErrEmptyFleet
+	}
+	plates := make([]string, len(vehicles))
+	for i, v := range vehicles {
+		plates[i] = v.Plate
+	}
+	return strings.Join(plates, ","), nil
+}
+
+func cleanLabels(labels []string) []string {
+	cleaned := make([]string, 0, len(labels))
+	for _, l := range labels {
+		l = strings.TrimSpace(strings.ReplaceAll(l, ",", " "))
+		if l != "" {
+			cleaned = append(cleaned, l)
+		}
+	}
+	return cleaned
+}
+
+func field(record []string, col int) string {
+	if col >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[col])
+}
+
+func toFleetOutput(f *domain.Fleet) *FleetOutput {
+	return &FleetOutput{
+		ID:          f.ID,
+		Name:        f.Name,
+		Description: f.Description,
+		TenantID:    f.TenantID,
+		CreatedAt:   f.CreatedAt,
+		UpdatedAt:   f.UpdatedAt,
+	}
+}
+
+func NewFleetService(repo FleetPorts) FleetService {
+	return FleetService{repo: repo}
+}
@@ -0,0 +1,31 @@
+package fleet_monitor
+
+import (
+	"context"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+)
+
+type FleetPort interface {
+	GetFleets(ctx context.Context, scope domain.FleetScope) ([]*domain.Fleet, error)
+	GetFleet(ctx context.Context, scope domain.FleetScope, id uuid.UUID) (*domain.Fleet, error)
+	GetFleetVehicles(ctx context.Context, scope domain.FleetScope, fleetID uuid.UUID) ([]*domain.FleetVehicle, error)
+}
+
+type TenantPort interface {
+	GetTenant(ctx context.Context, id uuid.UUID) (*domain.Tenant, error)
+}
+
+type PolicyVerificationPort interface {
+	GetPolicyVerification(ctx context.Context, cars []string) ([]domain.PolicyVerification, error)
+}
+
+type FleetMonitorPorts interface {
+	SaveVehicleChecks(ctx context.Context, fleetID uuid.UUID, checks []domain.VehicleCheck) error
+	GetLatestVehicleChecks(ctx context.Context, fleetID uuid.UUID) ([]*domain.VehicleCheck, error)
+	OpenAlert(ctx context.Context, alert domain.FleetAlert) (bool, error)
+	ResolveAlertsByType(ctx context.Context, fleetID uuid.UUID, plate string, types []domain.AlertType) error
+	ResolveAlert(ctx context.Context, fleetID uuid.UUID, alertID uuid.UUID) error
+	GetOpenAlerts(ctx context.Context, fleetID uuid.UUID) ([]*domain.FleetAlert, error)
+}
@@ -0,0 +1,292 @@
+package fleet_monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultExpiringDays = 30
+
+type FleetMonitorService struct {
+	fleets       FleetPort
+	tenants      TenantPort
+	policies     PolicyVerificationPort
+	repo         FleetMonitorPorts
+	expiringDays int
+}
+
+type CheckOutput struct {
+	FleetID   uuid.UUID `json:"fleetId"`
+	Checked   int       `json:"checked"`
+	Alerts    int       `json:"alerts"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+type VehicleStatusOutput struct {
+	Plate           string                 `json:"plate"`
+	Success         bool                   `json:"statusCode"`
+	ProductName     string                 `json:"productName"`
+	StartDate       *time.Time             `json:"startDate"`
+	EndDate         *time.Time             `json:"endDate"`
+	InsuranceStatus domain.InsuranceStatus `json:"insuranceStatus"`
+	Message         string                 `json:"message"`
+	CheckedAt       time.Time              `json:"checkedAt"`
+}
+
+type AlertOutput struct {
+	ID        uuid.UUID        `json:"id"`
+	Plate     string           `json:"plate"`
+	Type      domain.AlertType `json:"type"`
+	Message   string           `json:"message"`
+	CreatedAt time.Time        `json:"createdAt"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+}
+
+// RunAll checks every registered fleet, of every tenant. A failing fleet is
+// logged and does not stop the others.
+func (fms *FleetMonitorService) RunAll(ctx context.Context) error {
+	fleets, err := fms.fleets.GetFleets(ctx, domain.FleetScope{AllTenants: true})
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, f := range fleets {
+		result, err := fms.check(ctx, f)
+		if err != nil {
+			log.Error().Err(err).Str("fleet", f.ID.String()).Msg("Error checking fleet")
+			errs = append(errs, fmt.Errorf("fleet %s: %w", f.ID, err))
+			continue
+		}
+		log.Info().Str("fleet", f.ID.String()).Int("checked", result.Checked).Int("alerts", result.Alerts).Msg("Fleet checked")
+	}
+	return errors.Join(errs...)
+}
+
+// CheckFleet verifies every vehicle in one of the client's fleets, stores the
+// outcome and opens or resolves alerts by comparing against the previous
+// check.
+func (fms *FleetMonitorService) CheckFleet(ctx context.Context, client *domain.APIClient, fleetID uuid.UUID) (*CheckOutput, error) {
+	f, err := fms.fleets.GetFleet(ctx, domain.FleetScopeFor(client), fleetID)
+	if err != nil {
+		return nil, err
+	}
+	return fms.check(ctx, f)
+}
+
+// check verifies the fleet's vehicles with the NIC account of the tenant that
+// owns the fleet, whoever runs the check.
+func (fms *FleetMonitorService) check(ctx context.Context, f *domain.Fleet) (*CheckOutput, error) {
+	var tenant *domain.Tenant
+	if f.TenantID != nil {
+		var err error
+		if tenant, err = fms.tenants.GetTenant(ctx, *f.TenantID); err != nil {
+			return nil, err
+		}
+	}
+	ctx = domain.WithTenant(ctx, tenant)
+
+	fleetID := f.ID
+	vehicles, err := fms.fleets.GetFleetVehicles(ctx, domain.FleetScope{AllTenants: true}, fleetID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	output := &CheckOutput{FleetID: fleetID, CheckedAt: now}
+	if len(vehicles) == 0 {
+		return output, nil
+	}
+
+	plates := make([]string, len(vehicles))
+	for i, v := range vehicles {
+		plates[i] = v.Plate
+	}
+
+	previous, err := fms.repo.GetLatestVehicleChecks(ctx, fleetID)
+	if err != nil {
+		return nil, err
+	}
+	previousByPlate := make(map[string]*domain.VehicleCheck, len(previous))
+	for _, p := range previous {
+		previousByPlate[p.Plate] = p
+	}
+
+	results, err := fms.policies.GetPolicyVerification(ctx, plates)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := make([]domain.VehicleCheck, 0, len(results))
+	for _, r := range results {
+		status, msg := r.InsuranceStatus(now, fms.expiringDays)
+		check := domain.VehicleCheck{
+			FleetID:         fleetID,
+			Plate:           r.RegistrationNumber,
+			Success:         r.Success,
+			ProductName:     r.ProductName,
+			StartDate:       r.StartDate,
+			EndDate:         r.EndDate,
+			InsuranceStatus: status,
+			Message:         msg,
+			CheckedAt:       now,
+		}
+		checks = append(checks, check)
+
+		opened, err := fms.updateAlerts(ctx, check, previousByPlate[check.Plate])
+		if err != nil {
+			return nil, err
+		}
+		output.Alerts += opened
+	}
+
+	if err := fms.repo.SaveVehicleChecks(ctx, fleetID, checks); err != nil {
+		return nil, err
+	}
+	output.Checked = len(checks)
+	return output, nil
+}
+
+// updateAlerts opens and resolves the plate's alerts after a check. It
+// returns how many alerts it opened; refreshing one already open does not
+// count.
+func (fms *FleetMonitorService) updateAlerts(ctx context.Context, check domain.VehicleCheck, previous *domain.VehicleCheck) (int, error) {
+	opened := 0
+	endDate := check.EndDate.In(pkg.Accra).Format("2006-01-02")
+
+	switch check.InsuranceStatus {
+	case domain.InsuranceStatusExpired:
+		isNew, err := fms.open(ctx, check, domain.AlertTypeExpired, fmt.Sprintf("Policy expired on %s", endDate))
+		if err != nil {
+			return opened, err
+		}
+		if isNew {
+			opened++
+		}
+		if err := fms.repo.ResolveAlertsByType(ctx, check.FleetID, check.Plate, []domain.AlertType{domain.AlertTypeExpiring}); err != nil {
+			return opened, err
+		}
+	case domain.InsuranceStatusExpiring:
+		isNew, err := fms.open(ctx, check, domain.AlertTypeExpiring, fmt.Sprintf("Policy expires on %s", endDate))
+		if err != nil {
+			return opened, err
+		}
+		if isNew {
+			opened++
+		}
+		if err := fms.repo.ResolveAlertsByType(ctx, check.FleetID, check.Plate, []domain.AlertType{domain.AlertTypeExpired}); err != nil {
+			return opened, err
+		}
+	case domain.InsuranceStatusInsured:
+		// A renewed policy clears both expiry alerts.
+		if err := fms.repo.ResolveAlertsByType(ctx, check.FleetID, check.Plate, []domain.AlertType{domain.AlertTypeExpiring, domain.AlertTypeExpired}); err != nil {
+			return opened, err
+		}
+	}
+
+	// A failed lookup says nothing about the product, so only compare two
+	// successful checks.
+	if previous != nil && previous.Success && check.Success &&
+		previous.ProductName != "" && check.ProductName != "" &&
+		!strings.EqualFold(previous.ProductName, check.ProductName) {
+		msg := fmt.Sprintf("Product changed from %s to %s", previous.ProductName, check.ProductName)
+		isNew, err := fms.open(ctx, check, domain.AlertTypeProductChanged, msg)
+		if err != nil {
+			return opened, err
+		}
+		if isNew {
+			opened++
+		}
+	}
+	return opened, nil
+}
+
+// open opens an alert for the checked plate, or refreshes the one of the same
+// type already open, and reports whether it opened a new one.
+func (fms *FleetMonitorService) open(ctx context.Context, check domain.VehicleCheck, alertType domain.AlertType, message string) (bool, error) {
+	return fms.repo.OpenAlert(ctx, domain.FleetAlert{
+		FleetID: check.FleetID,
+		Plate:   check.Plate,
+		Type:    alertType,
+		Message: message,
+	})
+}
+
+func (fms *FleetMonitorService) GetVehicleStatuses(ctx context.Context, client *domain.APIClient, fleetID uuid.UUID) ([]VehicleStatusOutput, error) {
+	if _, err := fms.fleets.GetFleet(ctx, domain.FleetScopeFor(client), fleetID); err != nil {
+		return nil, err
+	}
+	results, err := fms.repo.GetLatestVehicleChecks(ctx, fleetID)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]VehicleStatusOutput, len(results))
+	for i, r := range results {
+		statuses[i] = VehicleStatusOutput{
+			Plate:           r.Plate,
+			Success:         r.Success,
+			ProductName:     r.ProductName,
+			StartDate:       optionalTime(r.StartDate),
+			EndDate:         optionalTime(r.EndDate),
+			InsuranceStatus: r.InsuranceStatus,
+			Message:         r.Message,
+			CheckedAt:       r.CheckedAt,
+		}
+	}
+	return statuses, nil
+}
+
+func (fms *FleetMonitorService) GetOpenAlerts(ctx context.Context, client *domain.APIClient, fleetID uuid.UUID) ([]AlertOutput, error) {
+	if _, err := fms.fleets.GetFleet(ctx, domain.FleetScopeFor(client), fleetID); err != nil {
+		return nil, err
+	}
+	results, err := fms.repo.GetOpenAlerts(ctx, fleetID)
+	if err != nil {
+		return nil, err
+	}
+	alerts := make([]AlertOutput, len(results))
+	for i, r := range results {
+		alerts[i] = AlertOutput{
+			ID:        r.ID,
+			Plate:     r.Plate,
+			Type:      r.Type,
+			Message:   r.Message,
+			CreatedAt: r.CreatedAt,
+			UpdatedAt: r.UpdatedAt,
+		}
+	}
+	return alerts, nil
+}
+
+func (fms *FleetMonitorService) ResolveAlert(ctx context.Context, client *domain.APIClient, fleetID uuid.UUID, alertID uuid.UUID) error {
+	if _, err := fms.fleets.GetFleet(ctx, domain.FleetScopeFor(client), fleetID); err != nil {
+		return err
+	}
+	return fms.repo.ResolveAlert(ctx, fleetID, alertID)
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func NewFleetMonitorService(fleets FleetPort, tenants TenantPort, policies PolicyVerificationPort, repo FleetMonitorPorts, expiringDays int) FleetMonitorService {
+	if expiringDays <= 0 {
+		expiringDays = defaultExpiringDays
+	}
+	return FleetMonitorService{
+		fleets:       fleets,
+		tenants:      tenants,
+		policies:     policies,
+		repo:         repo,
+		expiringDays: expiringDays,
+	}
+}
@@ -0,0 +1,15 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+)
+
+type IdempotencyPort interface {
+	ClaimIdempotencyKey(ctx context.Context, key domain.IdempotencyKey, requestHash string, expiresBefore, staleBefore time.Time) (bool, error)
+	GetIdempotencyKey(ctx context.Context, key domain.IdempotencyKey) (*domain.IdempotencyRecord, error)
+	CompleteIdempotencyKey(ctx context.Context, key domain.IdempotencyKey, statusCode int, response []byte) error
+	DeleteIdempotencyKey(ctx context.Context, key domain.IdempotencyKey) error
+}
@@ -0,0 +1,166 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// KeyTTL is how long a completed request is replayed for its key.
+	KeyTTL = 24 * time.Hour
+	// pendingTimeout is how long a request may hold its key before it is
+	// presumed dead and the key can be claimed again. It is well beyond the
+	// time the largest batch takes against NIC.
+	pendingTimeout = 15 * time.Minute
+	maxKeyLength   = 255
+	// completeAttempts is how often Complete tries to store a response,
+	// waiting completeRetryDelay longer before each new attempt.
+	completeAttempts   = 3
+	completeRetryDelay = 200 * time.Millisecond
+)
+
+var (
+	ErrKeyReused     = errors.New("idempotency key was already used with a different request")
+	ErrKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+type IdempotencyService struct {
+	repo IdempotencyPort
+}
+
+// ValidateKey checks an Idempotency-Key header value: 1 to 255 printable
+// ASCII characters.
+func ValidateKey(key string) error {
+	if key == "" || len(key) > maxKeyLength {
+		return fmt.Errorf("Idempotency-Key must be 1 to %d characters", maxKeyLength)
+	}
+	for _, c := range key {
+		if c < 0x21 || c > 0x7e {
+			return errors.New("Idempotency-Key must be printable ASCII without spaces")
+		}
+	}
+	return nil
+}
+
+// Begin claims key for request. It returns nil when the caller should go on
+// and handle the request, then Complete or Release the key. When the key's
+// first request has completed with the same payload its record is returned
+// for replay. ErrKeyReused means the payload differs and ErrKeyInProgress
+// that the first request is still running.
+func (is *IdempotencyService) Begin(ctx context.Context, key domain.IdempotencyKey, request any) (*domain.IdempotencyRecord, error) {
+	hash, err := requestHash(key.Endpoint, request)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	claimed, err := is.repo.ClaimIdempotencyKey(ctx, key, hash, now.Add(-KeyTTL), now.Add(-pendingTimeout))
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		return nil, nil
+	}
+	record, err := is.repo.GetIdempotencyKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			// The first request released the key between our claim and
+			// this read; the client can simply retry.
+			return nil, ErrKeyInProgress
+		}
+		return nil, err
+	}
+	if record.RequestHash != hash {
+		return nil, ErrKeyReused
+	}
+	if !record.Completed() {
+		return nil, ErrKeyInProgress
+	}
+	return record, nil
+}
+
+// Check answers as Begin would for key and request without claiming the
+// key: nil when the request would run, the record when it would be
+// replayed, and ErrKeyReused or ErrKeyInProgress when it would be refused.
+func (is *IdempotencyService) Check(ctx context.Context, key domain.IdempotencyKey, request any) (*domain.IdempotencyRecord, error) {
+	hash, err := requestHash(key.Endpoint, request)
+	if err != nil {
+		return nil, err
+	}
+	record, err := is.repo.GetIdempotencyKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if record.CreatedAt.Before(now.Add(-KeyTTL)) || (!record.Completed() && record.CreatedAt.Before(now.Add(-pendingTimeout))) {
+		// Begin would take the key over.
+		return nil, nil
+	}
+	if record.RequestHash != hash {
+		return nil, ErrKeyReused
+	}
+	if !record.Completed() {
+		return nil, ErrKeyInProgress
+	}
+	return record, nil
+}
+
+// Complete stores the response to a claimed key's request so retries get it
+// back. NIC has done the work by then, so the key is never freed here: a
+// write that still fails after completeAttempts is logged and the key left
+// pending, and retries are refused with ErrKeyInProgress until pendingTimeout
+// instead of running the batch again.
+func (is *IdempotencyService) Complete(ctx context.Context, key domain.IdempotencyKey, statusCode int, response any) {
+	ctx = context.WithoutCancel(ctx)
+	body, err := json.Marshal(response)
+	if err != nil {
+		log.Error().Err(err).Str("client", key.ClientID.String()).Str("endpoint", key.Endpoint).Msg("Error encoding idempotent response")
+		return
+	}
+	for attempt := 1; ; attempt++ {
+		if err = is.repo.CompleteIdempotencyKey(ctx, key, statusCode, body); err == nil {
+			return
+		}
+		if attempt == completeAttempts {
+			break
+		}
+		time.Sleep(time.Duration(attempt) * completeRetryDelay)
+	}
+	log.Error().Err(err).Str("client", key.ClientID.String()).Str("endpoint", key.Endpoint).Int("attempts", completeAttempts).Msg("Error storing idempotent response; key left pending")
+}
+
+// Release frees a claimed key whose request did not complete, so it can be
+// retried with the same key. It must only be called before any NIC work.
+func (is *IdempotencyService) Release(ctx context.Context, key domain.IdempotencyKey) {
+	if err := is.repo.DeleteIdempotencyKey(context.WithoutCancel(ctx), key); err != nil {
+		log.Error().Err(err).Str("client", key.ClientID.String()).Str("endpoint", key.Endpoint).Msg("Error releasing idempotency key")
+	}
+}
+
+// requestHash fingerprints a request by its endpoint and decoded payload, so
+// retries that only differ in whitespace or field order still match.
+func requestHash(endpoint string, request any) (string, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write([]byte(endpoint))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func NewIdempotencyService(repo IdempotencyPort) IdempotencyService {
+	return IdempotencyService{repo: repo}
+}
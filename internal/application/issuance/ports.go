@@ -0,0 +1,35 @@
+package issuance
+
+import (
+	"context"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+)
+
+type IssuancePort interface {
+	CreateIssuanceRequest(ctx context.Context, request domain.IssuanceRequest) (*domain.IssuanceRequest, error)
+	GetIssuanceRequest(ctx context.Context, id uuid.UUID) (*domain.IssuanceRequest, error)
+	GetIssuanceRequests(ctx context.Context, filter domain.IssuanceFilter) ([]*domain.IssuanceRequest, error)
+	DecideIssuanceRequest(ctx context.Context, id uuid.UUID, status domain.IssuanceStatus, approverID uuid.UUID, note string) (*domain.IssuanceRequest, error)
+	StartIssuanceRequest(ctx context.Context, id uuid.UUID, clientID uuid.UUID) (*domain.IssuanceRequest, error)
+	CompleteIssuanceRequest(ctx context.Context, id uuid.UUID, status domain.IssuanceStatus, issued int, results []byte, message string, clientID uuid.UUID) (*domain.IssuanceRequest, error)
+	FailInterruptedIssuanceRequests(ctx context.Context, startedBefore time.Time, message string) ([]uuid.UUID, error)
+}
+
+type APIClientPort interface {
+	GetAPIClient(ctx context.Context, id uuid.UUID) (*domain.APIClient, error)
+}
+
+type TenantPort interface {
+	GetTenant(ctx context.Context, id uuid.UUID) (*domain.Tenant, error)
+}
+
+type RiskTypePort interface {
+	GetRiskType(ctx context.Context, ref domain.CatalogRef) (*domain.RiskType, error)
+}
+
+type PricePort interface {
+	GetServicePrices(ctx context.Context) ([]*domain.ServicePrice, error)
+}
@@ -0,0 +1,355 @@
+package issuance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultRequestLimit = 50
+	maxRequestLimit     = 500
+	maxNoteLength       = 1000
+
+	// DefaultApprovalThreshold applies when BULK_APPROVAL_THRESHOLD is unset.
+	DefaultApprovalThreshold = 50
+
+	// interruptedAfter is how long a request may be executing before it is
+	// taken to have been interrupted, far longer than the largest batch takes.
+	interruptedAfter = time.Hour
+	interruptedError = "execution was interrupted; some plates may have been issued, see the audit log before resubmitting"
+)
+
+var (
+	ErrSelfApproval   = errors.New("an issuance request cannot be decided by the client that submitted it")
+	ErrNotPending     = errors.New("issuance request has already been decided")
+	ErrNotApproved    = errors.New("issuance request is not approved or is already being executed")
+	ErrReasonRequired = errors.New("a note giving the reason is required to reject a request")
+	// ErrOtherTenant refuses decisions across tenants, admins included, since
+	// an approved batch is executed with the approver's NIC account.
+	ErrOtherTenant = errors.New("an issuance request can only be decided by a client of its tenant")
+)
+
+// InvalidPlatesError lists the submitted plates that are not Ghanaian
+// license plates. A batch is only accepted when every plate is valid.
+type InvalidPlatesError struct {
+	Plates []string
+}
+
+func (e *InvalidPlatesError) Error() string {
+	return fmt.Sprintf("%d plates are not valid license plates: %s", len(e.Plates), strings.Join(e.Plates, ", "))
+}
+
+// IssuanceService runs the maker-checker review of bulk issuance. A maker
+// submits a batch, a different client with the approver role from the same
+// tenant approves or rejects it, and only approved batches are executed.
+type IssuanceService struct {
+	repo      IssuancePort
+	riskTypes RiskTypePort
+	prices    PricePort
+	clients   APIClientPort
+	tenants   TenantPort
+	threshold int
+}
+
+// Execution is an approved request and the approver it is executed as, with
+// the approver's tenant loaded since the batch uses its NIC account.
+type Execution struct {
+	Request  *domain.IssuanceRequest
+	Approver *domain.APIClient
+}
+
+type SubmitInput struct {
+	Kind string
+	Cars string
+	// RiskTypeID is passed to sticker generation; brown cards take none.
+	RiskTypeID int
+	Note       string
+	// Branch is recorded with the stickers the request generates.
+	Branch string
+}
+
+type DecisionInput struct {
+	Note string
+}
+
+// ListInput filters issuance requests by status and kind.
+type ListInput struct {
+	Status string
+	Kind   string
+	Limit  int
+}
+
+func (si *SubmitInput) Validate() error {
+	if !slices.Contains(domain.IssuanceKinds, domain.IssuanceKind(si.Kind)) {
+		return fmt.Errorf("kind must be one of %s", joinKinds())
+	}
+	if strings.TrimSpace(si.Cars) == "" {
+		return errors.New("cars is required")
+	}
+	if si.RiskTypeID < 0 {
+		return errors.New("riskTypeId must be a NIC risk type id")
+	}
+	if si.RiskTypeID > 0 && si.Kind != string(domain.IssuanceSticker) {
+		return errors.New("riskTypeId only applies to stickers")
+	}
+	if len(si.Note) > maxNoteLength {
+		return fmt.Errorf("note must be at most %d characters", maxNoteLength)
+	}
+	if len(si.Branch) > domain.MaxBranchLength {
+		return fmt.Errorf("branch must be at most %d characters", domain.MaxBranchLength)
+	}
+	return nil
+}
+
+func (di *DecisionInput) Validate() error {
+	if len(di.Note) > maxNoteLength {
+		return fmt.Errorf("note must be at most %d characters", maxNoteLength)
+	}
+	return nil
+}
+
+func (li *ListInput) Validate() error {
+	if li.Status != "" && !slices.Contains(domain.IssuanceStatuses, domain.IssuanceStatus(li.Status)) {
+		return errors.New("status must be one of pending, rejected, approved, executing, executed, failed")
+	}
+	if li.Kind != "" && !slices.Contains(domain.IssuanceKinds, domain.IssuanceKind(li.Kind)) {
+		return fmt.Errorf("kind must be one of %s", joinKinds())
+	}
+	if li.Limit > maxRequestLimit {
+		return fmt.Errorf("limit must be at most %d", maxRequestLimit)
+	}
+	return nil
+}
+
+// RequiresApproval reports whether a batch of this many plates is too large
+// to be issued directly and has to go through an issuance request.
+func (is *IssuanceService) RequiresApproval(plates int) bool {
+	return plates > is.threshold
+}
+
+// Threshold is both the largest batch and the most plates per day a client
+// may issue without approval.
+func (is *IssuanceService) Threshold() int {
+	return is.threshold
+}
+
+// Submit validates, deduplicates and prices a batch and stores it for
+// review. Nothing is sent to NIC.
+func (is *IssuanceService) Submit(ctx context.Context, maker *domain.APIClient, input SubmitInput) (*domain.IssuanceRequest, error) {
+	valid := make([]string, 0)
+	invalid := make([]string, 0)
+	for _, car := range pkg.SplitCars(input.Cars) {
+		if ok, _ := pkg.ValidateGhanaLicensePlate(car); !ok {
+			invalid = append(invalid, car)
+			continue
+		}
+		valid = append(valid, car)
+	}
+	if len(invalid) > 0 {
+		return nil, &InvalidPlatesError{Plates: invalid}
+	}
+	plates, _ := pkg.DedupePlates(valid)
+	if len(plates) == 0 {
+		return nil, errors.New("cars is required")
+	}
+
+	request := domain.IssuanceRequest{
+		Kind:     domain.IssuanceKind(input.Kind),
+		MakerID:  maker.ID,
+		TenantID: maker.TenantID,
+		Plates:   plates,
+		Note:     input.Note,
+		Branch:   input.Branch,
+	}
+	if input.RiskTypeID > 0 {
+		if _, err := is.riskTypes.GetRiskType(ctx, domain.CatalogRef{ExternalID: input.RiskTypeID}); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, domain.ErrUnknownRiskType
+			}
+			return nil, err
+		}
+		request.RiskTypeID = &input.RiskTypeID
+	}
+
+	prices, err := is.prices.GetServicePrices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, price := range prices {
+		if price.Service == domain.MeteredService(request.Kind) {
+			// Each plate is one NIC call and, at best, one issued document.
+			request.EstimatedAmount = price.Estimate(len(plates), len(plates), len(plates))
+		}
+	}
+	return is.repo.CreateIssuanceRequest(ctx, request)
+}
+
+// Get returns a request with its history. Requests of other tenants are not
+// found, except by admins.
+func (is *IssuanceService) Get(ctx context.Context, client *domain.APIClient, id uuid.UUID) (*domain.IssuanceRequest, error) {
+	request, err := is.repo.GetIssuanceRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !visible(client, request) {
+		return nil, domain.ErrNotFound
+	}
+	return request, nil
+}
+
+// List returns the requests of the client's tenant, newest first, or those
+// of every tenant for admins.
+func (is *IssuanceService) List(ctx context.Context, client *domain.APIClient, input ListInput) ([]*domain.IssuanceRequest, error) {
+	filter := domain.IssuanceFilter{
+		AllTenants: client.Can(domain.RoleAdmin),
+		TenantID:   client.TenantID,
+		Status:     domain.IssuanceStatus(input.Status),
+		Kind:       domain.IssuanceKind(input.Kind),
+		Limit:      input.Limit,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultRequestLimit
+	}
+	return is.repo.GetIssuanceRequests(ctx, filter)
+}
+
+// Approve marks a pending request approved. The caller then claims it with
+// Start, executes it and reports the outcome with Complete.
+func (is *IssuanceService) Approve(ctx context.Context, approver *domain.APIClient, id uuid.UUID, input DecisionInput) (*domain.IssuanceRequest, error) {
+	return is.decide(ctx, approver, id, domain.IssuanceApproved, input.Note)
+}
+
+// Reject closes a pending request without executing it.
+func (is *IssuanceService) Reject(ctx context.Context, approver *domain.APIClient, id uuid.UUID, input DecisionInput) (*domain.IssuanceRequest, error) {
+	if strings.TrimSpace(input.Note) == "" {
+		return nil, ErrReasonRequired
+	}
+	return is.decide(ctx, approver, id, domain.IssuanceRejected, input.Note)
+}
+
+// Start claims an approved request for execution. ErrNotApproved means it
+// must not be executed, as another worker has already claimed it.
+func (is *IssuanceService) Start(ctx context.Context, approverID, id uuid.UUID) (*domain.IssuanceRequest, error) {
+	request, err := is.repo.StartIssuanceRequest(ctx, id, approverID)
+	if errors.Is(err, domain.ErrConflict) {
+		return nil, ErrNotApproved
+	}
+	return request, err
+}
+
+// Complete stores the outcome of executing a claimed request: the per-plate
+// results and number issued, or the error that stopped the batch.
+func (is *IssuanceService) Complete(ctx context.Context, approverID, id uuid.UUID, results any, issued int, runErr error) (*domain.IssuanceRequest, error) {
+	status, message := domain.IssuanceExecuted, ""
+	if runErr != nil {
+		status, message = domain.IssuanceFailed, runErr.Error()
+	}
+	body, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+	request, err := is.repo.CompleteIssuanceRequest(context.WithoutCancel(ctx), id, status, issued, body, message, approverID)
+	if errors.Is(err, domain.ErrConflict) {
+		return nil, ErrNotApproved
+	}
+	return request, err
+}
+
+// Recover fails the requests that have been executing for too long to still
+// be running, since NIC may have issued part of them, and returns the
+// approved requests that were never started, oldest first, to be executed.
+func (is *IssuanceService) Recover(ctx context.Context) ([]Execution, error) {
+	failed, err := is.repo.FailInterruptedIssuanceRequests(ctx, time.Now().Add(-interruptedAfter), interruptedError)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range failed {
+		log.Warn().Str("request", id.String()).Msg("Issuance request was interrupted while executing")
+	}
+	approved, err := is.repo.GetIssuanceRequests(ctx, domain.IssuanceFilter{
+		AllTenants: true,
+		Status:     domain.IssuanceApproved,
+		Limit:      maxRequestLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	executions := make([]Execution, 0, len(approved))
+	for i := len(approved) - 1; i >= 0; i-- {
+		request := approved[i]
+		if request.ApproverID == nil {
+			continue
+		}
+		approver, err := is.clients.GetAPIClient(ctx, *request.ApproverID)
+		if err != nil {
+			return nil, err
+		}
+		if approver.TenantID != nil {
+			if approver.Tenant, err = is.tenants.GetTenant(ctx, *approver.TenantID); err != nil {
+				return nil, err
+			}
+		}
+		executions = append(executions, Execution{Request: request, Approver: approver})
+	}
+	return executions, nil
+}
+
+func (is *IssuanceService) decide(ctx context.Context, approver *domain.APIClient, id uuid.UUID, status domain.IssuanceStatus, note string) (*domain.IssuanceRequest, error) {
+	request, err := is.Get(ctx, approver, id)
+	if err != nil {
+		return nil, err
+	}
+	if request.MakerID == approver.ID {
+		return nil, ErrSelfApproval
+	}
+	if !sameTenant(approver.TenantID, request.TenantID) {
+		return nil, ErrOtherTenant
+	}
+	if request.Status != domain.IssuancePending {
+		return nil, ErrNotPending
+	}
+	decided, err := is.repo.DecideIssuanceRequest(ctx, id, status, approver.ID, note)
+	if errors.Is(err, domain.ErrConflict) {
+		// Another approver decided the request since we read it.
+		return nil, ErrNotPending
+	}
+	return decided, err
+}
+
+func visible(client *domain.APIClient, request *domain.IssuanceRequest) bool {
+	return client.Can(domain.RoleAdmin) || sameTenant(client.TenantID, request.TenantID)
+}
+
+func sameTenant(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func joinKinds() string {
+	kinds := make([]string, len(domain.IssuanceKinds))
+	for i, kind := range domain.IssuanceKinds {
+		kinds[i] = string(kind)
+	}
+	return strings.Join(kinds, ", ")
+}
+
+// NewIssuanceService builds the service. Approval cannot be turned off: a
+// threshold that is not positive is replaced by DefaultApprovalThreshold.
+func NewIssuanceService(repo IssuancePort, riskTypes RiskTypePort, prices PricePort, clients APIClientPort, tenants TenantPort, threshold int) IssuanceService {
+	if threshold <= 0 {
+		threshold = DefaultApprovalThreshold
+	}
+	return IssuanceService{repo: repo, riskTypes: riskTypes, prices: prices, clients: clients, tenants: tenants, threshold: threshold}
+}
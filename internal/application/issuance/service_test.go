@@ -0,0 +1,27 @@
+package issuance
+
+import "testing"
+
+func TestRequiresApproval(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		plates    int
+		want      bool
+	}{
+		{name: "below the threshold", threshold: 20, plates: 19, want: false},
+		{name: "at the threshold", threshold: 20, plates: 20, want: false},
+		{name: "above the threshold", threshold: 20, plates: 21, want: true},
+		{name: "default at its threshold", threshold: 0, plates: DefaultApprovalThreshold, want: false},
+		{name: "default above its threshold", threshold: 0, plates: DefaultApprovalThreshold + 1, want: true},
+		{name: "negative threshold falls back to the default", threshold: -5, plates: DefaultApprovalThreshold, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewIssuanceService(nil, nil, nil, nil, nil, tt.threshold)
+			if got := service.RequiresApproval(tt.plates); got != tt.want {
+				t.Errorf("RequiresApproval(%d) with threshold %d = %t, want %t", tt.plates, service.Threshold(), got, tt.want)
+			}
+		})
+	}
+}
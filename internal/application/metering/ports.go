@@ -0,0 +1,16 @@
+package metering
+
+import (
+	"context"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+)
+
+type MeteringPort interface {
+	RecordUsage(ctx context.Context, record domain.UsageRecord) error
+	GetStatements(ctx context.Context, start, end time.Time, clientID uuid.UUID) ([]*domain.Statement, error)
+	GetServicePrices(ctx context.Context) ([]*domain.ServicePrice, error)
+	SetServicePrice(ctx context.Context, price domain.ServicePrice) (*domain.ServicePrice, error)
+}
@@ -0,0 +1,184 @@
+package metering
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const monthLayout = "2006-01"
+
+var ErrUnknownService = errors.New("unknown metered service")
+
+type MeteringService struct {
+	repo MeteringPort
+}
+
+// StatementInput selects the statements for a month, written YYYY-MM and
+// defaulting to the current month, optionally for one client.
+type StatementInput struct {
+	Month    string
+	ClientID string
+}
+
+// ServicePriceInput sets the unit prices of a service.
+type ServicePriceInput struct {
+	PlatePrice   domain.Money
+	NICCallPrice domain.Money
+	IssuedPrice  domain.Money
+}
+
+func (si *StatementInput) Validate() error {
+	if si.Month != "" {
+		if _, err := time.Parse(monthLayout, si.Month); err != nil {
+			return errors.New("month must be written YYYY-MM")
+		}
+	}
+	if si.ClientID != "" {
+		if _, err := uuid.Parse(si.ClientID); err != nil {
+			return errors.New("clientId must be an api client id")
+		}
+	}
+	return nil
+}
+
+func (pi *ServicePriceInput) Validate() error {
+	if pi.PlatePrice < 0 || pi.NICCallPrice < 0 || pi.IssuedPrice < 0 {
+		return errors.New("prices must not be negative")
+	}
+	return nil
+}
+
+// RecordUsage stores a metered request. Metering must never fail the request
+// it describes, so errors are logged rather than returned.
+func (ms *MeteringService) RecordUsage(ctx context.Context, record domain.UsageRecord) {
+	if err := ms.repo.RecordUsage(context.WithoutCancel(ctx), record); err != nil {
+		log.Error().Err(err).
+			Str("client", record.ClientID.String()).
+			Str("service", string(record.Service)).
+			Int("plates", record.Plates).
+			Int("nicCalls", record.NICCalls).
+			Int("issued", record.Issued).
+			Msg("Error recording usage")
+	}
+}
+
+// GetStatements returns the statements for a month, one per client with
+// usage in it.
+func (ms *MeteringService) GetStatements(ctx context.Context, input StatementInput) ([]*domain.Statement, error) {
+	start, err := monthStart(input.Month)
+	if err != nil {
+		return nil, err
+	}
+	var clientID uuid.UUID
+	if input.ClientID != "" {
+		if clientID, err = uuid.Parse(input.ClientID); err != nil {
+			return nil, err
+		}
+	}
+	statements, err := ms.repo.GetStatements(ctx, start, start.AddDate(0, 1, 0), clientID)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range statements {
+		s.Month = start.Format(monthLayout)
+	}
+	return statements, nil
+}
+
+// GetClientStatement returns one client's statement for a month. A month
+// without usage gives an empty statement.
+func (ms *MeteringService) GetClientStatement(ctx context.Context, client *domain.APIClient, input StatementInput) (*domain.Statement, error) {
+	input.ClientID = client.ID.String()
+	statements, err := ms.GetStatements(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if len(statements) > 0 {
+		return statements[0], nil
+	}
+	start, err := monthStart(input.Month)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Statement{
+		ClientID:   client.ID,
+		ClientName: client.Name,
+		Month:      start.Format(monthLayout),
+		Lines:      make([]domain.StatementLine, 0),
+	}, nil
+}
+
+// ExportStatements writes a month's statements as CSV, one row per client
+// and service, with amounts in cedis.
+func (ms *MeteringService) ExportStatements(ctx context.Context, input StatementInput) ([]byte, error) {
+	statements, err := ms.GetStatements(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"month", "client_id", "client_name", "service", "requests", "plates", "nic_calls", "issued", "amount"})
+	for _, s := range statements {
+		for _, l := range s.Lines {
+			w.Write([]string{
+				s.Month,
+				s.ClientID.String(),
+				s.ClientName,
+				string(l.Service),
+				strconv.Itoa(l.Requests),
+				strconv.Itoa(l.Plates),
+				strconv.Itoa(l.NICCalls),
+				strconv.Itoa(l.Issued),
+				l.Amount.String(),
+			})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (ms *MeteringService) GetServicePrices(ctx context.Context) ([]*domain.ServicePrice, error) {
+	return ms.repo.GetServicePrices(ctx)
+}
+
+// SetServicePrice changes what a service costs from now on. Usage already
+// recorded keeps the prices it was recorded at.
+func (ms *MeteringService) SetServicePrice(ctx context.Context, service string, input ServicePriceInput) (*domain.ServicePrice, error) {
+	if !slices.Contains(domain.MeteredServices, domain.MeteredService(service)) {
+		return nil, fmt.Errorf("%w %q; services are %v", ErrUnknownService, service, domain.MeteredServices)
+	}
+	return ms.repo.SetServicePrice(ctx, domain.ServicePrice{
+		Service:      domain.MeteredService(service),
+		PlatePrice:   input.PlatePrice,
+		NICCallPrice: input.NICCallPrice,
+		IssuedPrice:  input.IssuedPrice,
+	})
+}
+
+// monthStart returns midnight in Accra on the first of the month, or of the
+// current month when month is empty.
+func monthStart(month string) (time.Time, error) {
+	if month == "" {
+		now := time.Now().In(pkg.Accra)
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, pkg.Accra), nil
+	}
+	return time.ParseInLocation(monthLayout, month, pkg.Accra)
+}
+
+func NewMeteringService(repo MeteringPort) MeteringService {
+	return MeteringService{repo: repo}
+}
@@ -0,0 +1,25 @@
+package plate_rule
+
+import (
+	"context"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+)
+
+type PlateRiskRulePorts interface {
+	CreatePlateRiskRule(ctx context.Context, rule domain.PlateRiskRule) (*domain.PlateRiskRule, error)
+	GetPlateRiskRules(ctx context.Context) (domain.PlateRiskRules, error)
+	DeletePlateRiskRule(ctx context.Context, id uuid.UUID) error
+	GetActiveRiskTypesInCategories(ctx context.Context, categoryIDs []uuid.UUID) ([]*domain.RiskType, error)
+}
+
+type RiskTypePort interface {
+	GetRiskType(ctx context.Context, ref domain.CatalogRef) (*domain.RiskType, error)
+	GetRiskCategories(ctx context.Context) ([]*domain.RiskCategory, error)
+}
+
+type ProductPort interface {
+	GetProduct(ctx context.Context, ref domain.CatalogRef) (*domain.Product, error)
+	GetProductRiskTypes(ctx context.Context, productID int) ([]*domain.ProductRiskType, error)
+}
@@ -0,0 +1,249 @@
+package plate_rule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+)
+
+var plateKinds = []pkg.PlateKind{
+	pkg.PlateKindOldFormat,
+	pkg.PlateKindNewFormat,
+	pkg.PlateKindDVTrade,
+	pkg.PlateKindMotorcycle,
+	pkg.PlateKindSpecial,
+	pkg.PlateKindPersonalised,
+}
+
+var ErrDefaultOutsideCategory = errors.New("default risk type is not in the rule's risk category")
+
+type PlateRuleService struct {
+	repo      PlateRiskRulePorts
+	riskTypes RiskTypePort
+	products  ProductPort
+}
+
+type PlateRiskRuleInput struct {
+	PlateKind         string
+	Code              string
+	RiskCategoryID    string
+	DefaultRiskTypeID int
+}
+
+type PlateRiskRuleOutput struct {
+	ID                uuid.UUID `json:"id"`
+	PlateKind         string    `json:"plateKind"`
+	Code              string    `json:"code"`
+	RiskCategoryID    uuid.UUID `json:"riskCategoryId"`
+	RiskCategory      string    `json:"riskCategory"`
+	DefaultRiskTypeId *int      `json:"defaultRiskTypeId"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+type PlateRiskTypesInput struct {
+	Plate     string
+	ProductID int
+}
+
+type SuggestedRiskType struct {
+	RiskTypeId   int    `json:"riskTypeId"`
+	Name         string `json:"name"`
+	RiskCategory string `json:"riskCategory"`
+	RiskTypeCode string `json:"riskTypeCode"`
+	Default      bool   `json:"default"`
+}
+
+// PlateRiskTypesOutput describes what a plate may be insured as. Restricted
+// is false when no rule covers the plate, in which case any risk type is
+// accepted and only a product's own mappings are listed.
+type PlateRiskTypesOutput struct {
+	Plate             string              `json:"plate"`
+	Kind              pkg.PlateKind       `json:"kind"`
+	Code              string              `json:"code"`
+	Restricted        bool                `json:"restricted"`
+	AllowedCategories []string            `json:"allowedCategories"`
+	DefaultRiskTypeId *int                `json:"defaultRiskTypeId"`
+	RiskTypes         []SuggestedRiskType `json:"riskTypes"`
+}
+
+func (pi *PlateRiskRuleInput) Validate() error {
+	if !slices.Contains(plateKinds, pkg.PlateKind(strings.ToLower(strings.TrimSpace(pi.PlateKind)))) {
+		return fmt.Errorf("plateKind must be one of %v", plateKinds)
+	}
+	if _, err := uuid.Parse(pi.RiskCategoryID); err != nil {
+		return errors.New("riskCategoryId must be a risk category id")
+	}
+	if pi.DefaultRiskTypeID < 0 {
+		return errors.New("defaultRiskTypeId must be a NIC risk type id")
+	}
+	return nil
+}
+
+func (pi *PlateRiskTypesInput) Validate() error {
+	if valid, msg := pkg.ValidateGhanaLicensePlate(pi.Plate); !valid {
+		return errors.New(msg)
+	}
+	if pi.ProductID < 0 {
+		return errors.New("productId must be a NIC product id")
+	}
+	return nil
+}
+
+// CreatePlateRiskRule allows plates of a kind, optionally only those with a
+// given region or special code, under a risk category. The default risk type,
+// if given, must belong to that category.
+func (ps *PlateRuleService) CreatePlateRiskRule(ctx context.Context, input PlateRiskRuleInput) (*PlateRiskRuleOutput, error) {
+	categoryID, err := uuid.Parse(input.RiskCategoryID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := ps.riskTypes.GetRiskCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(categories, func(c *domain.RiskCategory) bool { return c.ID == categoryID })
+	if i < 0 {
+		return nil, domain.ErrUnknownRiskCategory
+	}
+
+	rule := domain.PlateRiskRule{
+		PlateKind:      strings.ToLower(strings.TrimSpace(input.PlateKind)),
+		Code:           strings.ToUpper(strings.TrimSpace(input.Code)),
+		RiskCategoryID: categoryID,
+	}
+	if input.DefaultRiskTypeID > 0 {
+		riskType, err := ps.riskTypes.GetRiskType(ctx, domain.CatalogRef{ExternalID: input.DefaultRiskTypeID})
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, domain.ErrUnknownRiskType
+			}
+			return nil, err
+		}
+		if !strings.EqualFold(strings.TrimSpace(riskType.RiskCategory), categories[i].Name) {
+			return nil, ErrDefaultOutsideCategory
+		}
+		rule.DefaultRiskTypeId = &input.DefaultRiskTypeID
+	}
+
+	result, err := ps.repo.CreatePlateRiskRule(ctx, rule)
+	if err != nil {
+		return nil, err
+	}
+	return toPlateRiskRuleOutput(result), nil
+}
+
+func (ps *PlateRuleService) GetPlateRiskRules(ctx context.Context) ([]PlateRiskRuleOutput, error) {
+	results, err := ps.repo.GetPlateRiskRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]PlateRiskRuleOutput, len(results))
+	for i, result := range results {
+		rules[i] = *toPlateRiskRuleOutput(result)
+	}
+	return rules, nil
+}
+
+func (ps *PlateRuleService) DeletePlateRiskRule(ctx context.Context, id uuid.UUID) error {
+	return ps.repo.DeletePlateRiskRule(ctx, id)
+}
+
+// SuggestRiskTypes lists the active risk types a plate may be insured as,
+// default first. With a product that has risk type mappings, the list is
+// narrowed to risk types the product allows.
+func (ps *PlateRuleService) SuggestRiskTypes(ctx context.Context, input PlateRiskTypesInput) (*PlateRiskTypesOutput, error) {
+	info, _, _ := pkg.ParseGhanaLicensePlate(input.Plate)
+	all, err := ps.repo.GetPlateRiskRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules := all.For(string(info.Kind), info.Code)
+
+	output := &PlateRiskTypesOutput{
+		Plate:             pkg.CanonicalPlate(input.Plate),
+		Kind:              info.Kind,
+		Code:              info.Code,
+		Restricted:        len(rules) > 0,
+		AllowedCategories: rules.Categories(),
+		DefaultRiskTypeId: rules.DefaultRiskTypeId(),
+		RiskTypes:         make([]SuggestedRiskType, 0),
+	}
+
+	var candidates []*domain.RiskType
+	if output.Restricted {
+		if candidates, err = ps.repo.GetActiveRiskTypesInCategories(ctx, rules.CategoryIDs()); err != nil {
+			return nil, err
+		}
+	}
+	if input.ProductID > 0 {
+		if candidates, err = ps.narrowToProduct(ctx, input.ProductID, candidates, output.Restricted); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, r := range candidates {
+		suggestion := SuggestedRiskType{
+			RiskTypeId:   r.RiskTypeId,
+			Name:         r.Name,
+			RiskCategory: r.RiskCategory,
+			RiskTypeCode: r.RiskTypeCode,
+			Default:      output.DefaultRiskTypeId != nil && *output.DefaultRiskTypeId == r.RiskTypeId,
+		}
+		if suggestion.Default {
+			output.RiskTypes = slices.Insert(output.RiskTypes, 0, suggestion)
+		} else {
+			output.RiskTypes = append(output.RiskTypes, suggestion)
+		}
+	}
+	return output, nil
+}
+
+// narrowToProduct keeps the candidates the product allows. An unrestricted
+// plate takes the product's mappings as they are; a product without mappings
+// allows everything.
+func (ps *PlateRuleService) narrowToProduct(ctx context.Context, productID int, candidates []*domain.RiskType, restricted bool) ([]*domain.RiskType, error) {
+	if _, err := ps.products.GetProduct(ctx, domain.CatalogRef{ExternalID: productID}); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrUnknownProduct
+		}
+		return nil, err
+	}
+	mappings, err := ps.products.GetProductRiskTypes(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(mappings) == 0 {
+		return candidates, nil
+	}
+	allowed := make([]*domain.RiskType, 0, len(mappings))
+	for _, m := range mappings {
+		riskType := m.RiskType
+		if !restricted || slices.ContainsFunc(candidates, func(c *domain.RiskType) bool { return c.RiskTypeId == riskType.RiskTypeId }) {
+			allowed = append(allowed, &riskType)
+		}
+	}
+	return allowed, nil
+}
+
+func toPlateRiskRuleOutput(r *domain.PlateRiskRule) *PlateRiskRuleOutput {
+	return &PlateRiskRuleOutput{
+		ID:                r.ID,
+		PlateKind:         r.PlateKind,
+		Code:              r.Code,
+		RiskCategoryID:    r.RiskCategoryID,
+		RiskCategory:      r.RiskCategory,
+		DefaultRiskTypeId: r.DefaultRiskTypeId,
+		CreatedAt:         r.CreatedAt,
+	}
+}
+
+func NewPlateRuleService(repo PlateRiskRulePorts, riskTypes RiskTypePort, products ProductPort) PlateRuleService {
+	return PlateRuleService{repo: repo, riskTypes: riskTypes, products: products}
+}
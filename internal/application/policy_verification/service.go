@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/godsent-code/midtools/pkg"
 )
@@ -13,33 +14,55 @@ type PolicyVerificationService struct {
 }
 type PolicyVerificationInput struct {
 	Cars string
+	AsOf string
 }
 
 type PolicyVerificationOutput struct {
-	Status      bool   `json:"statusCode"`
-	ProductName string `json:"ProductName"`
-	StartDate   string `json:"startDate"`
-	EndDate     string `json:"endDate"`
-	Message     string `json:"message"`
-	CarNumber   string `json:"carNumber"`
+	Status      bool       `json:"statusCode"`
+	ProductName string     `json:"ProductName"`
+	StartDate   *time.Time `json:"startDate"`
+	EndDate     *time.Time `json:"endDate"`
+	AsOf        *time.Time `json:"asOf,omitempty"`
+	Covered     *bool      `json:"covered,omitempty"`
+	Message     string     `json:"message"`
+	CarNumber   string     `json:"carNumber"`
 }
 
 func (bci *PolicyVerificationInput) Validate() error {
 	if strings.TrimSpace(bci.Cars) == "" {
 		return errors.New("cars is required")
 	}
+	if _, err := bci.asOf(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// asOf returns the moment coverage should be checked at, or nil when the
+// caller did not ask for it. A bare date means the start of that day in Accra.
+func (bci *PolicyVerificationInput) asOf() (*time.Time, error) {
+	if strings.TrimSpace(bci.AsOf) == "" {
+		return nil, nil
+	}
+	t, _, err := pkg.ParseDate(bci.AsOf)
+	if err != nil {
+		return nil, errors.New("asOf must be an ISO-8601 date or date-time")
+	}
+	return &t, nil
+}
+
 func (pvs *PolicyVerificationService) GetPolicyVerifications(ctx context.Context, input PolicyVerificationInput) ([]PolicyVerificationOutput, error) {
-	parts := strings.FieldsFunc(input.Cars, func(r rune) bool {
-		return r == ',' || r == '\n' || r == '\r' || r == '\t'
-	})
+	parts := pkg.SplitCars(input.Cars)
 
 	if len(parts) == 0 {
 		return nil, errors.New("cars is required")
 	}
 
+	asOf, err := input.asOf()
+	if err != nil {
+		return nil, err
+	}
+
 	policies := make([]PolicyVerificationOutput, 0)
 
 	correctCars := make([]string, 0)
@@ -49,9 +72,7 @@ func (pvs *PolicyVerificationService) GetPolicyVerifications(ctx context.Context
 		if !exists {
 			policies = append(policies, PolicyVerificationOutput{
 				Status:    false,
-				StartDate: num,
 				CarNumber: parts[i],
-				EndDate:   num,
 				Message:   num,
 			})
 		} else {
@@ -65,19 +86,32 @@ func (pvs *PolicyVerificationService) GetPolicyVerifications(ctx context.Context
 	}
 
 	for i, _ := range results {
-		policies = append(policies, PolicyVerificationOutput{
+		output := PolicyVerificationOutput{
 			Status:      results[i].Success,
-			StartDate:   results[i].StartDate,
+			StartDate:   optionalTime(results[i].StartDate),
 			ProductName: results[i].ProductName,
 			CarNumber:   results[i].RegistrationNumber,
-			EndDate:     results[i].EndDate,
+			EndDate:     optionalTime(results[i].EndDate),
 			Message:     results[i].Message,
-		})
+		}
+		if asOf != nil {
+			covered := results[i].CoveredAt(*asOf)
+			output.AsOf = asOf
+			output.Covered = &covered
+		}
+		policies = append(policies, output)
 	}
 
 	return policies, nil
 }
 
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
 func NewPolicyVerificationService(r PolicyVerificationPort) PolicyVerificationService {
 	return PolicyVerificationService{repo: r}
 }
@@ -2,11 +2,27 @@ package product
 
 import (
 	"context"
+	"time"
 
 	"github.com/godsent-code/midtools/internal/domain"
 )
 
 type ProductPorts interface {
-	CreateProduct(ctx context.Context) error
-	GetProducts(ctx context.Context) ([]*domain.Product, error)
+	CreateProduct(ctx context.Context, opts domain.SyncOptions) (*domain.SyncResult, error)
+	ExportProducts(ctx context.Context, format domain.CatalogFileFormat) ([]byte, error)
+	SearchProducts(ctx context.Context, query domain.CatalogQuery) (*domain.CatalogPage[domain.Product], error)
+	GetProductsAsOf(ctx context.Context, asOf time.Time) ([]*domain.Product, error)
+	GetProduct(ctx context.Context, ref domain.CatalogRef) (*domain.Product, error)
+	GetProductByCode(ctx context.Context, code string) (*domain.Product, error)
+	GetProductRiskTypes(ctx context.Context, productID int) ([]*domain.ProductRiskType, error)
+	AddProductRiskType(ctx context.Context, productID, riskTypeID int) error
+	RemoveProductRiskType(ctx context.Context, productID, riskTypeID int) error
+	GetProductHistory(ctx context.Context, productID int) ([]*domain.ProductVersion, error)
+}
+
+type SyncRunPort interface {
+	StartSyncRun(ctx context.Context, run domain.SyncRun) (*domain.SyncRun, error)
+	FinishSyncRun(ctx context.Context, run domain.SyncRun) error
+	GetSyncRuns(ctx context.Context, catalog domain.Catalog, limit int) ([]*domain.SyncRun, error)
+	GetLastSuccessfulSyncRun(ctx context.Context, catalog domain.Catalog) (*domain.SyncRun, error)
 }
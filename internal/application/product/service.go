@@ -1,14 +1,28 @@
 package product
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultSyncRunLimit = 20
+	maxPageSize         = 500
 )
 
 type ProductService struct {
 	repo ProductPorts
+	runs SyncRunPort
 }
 
 type ProductOutput struct {
@@ -17,36 +31,478 @@ type ProductOutput struct {
 	ProductCode string
 	ProductId   int
 	Description string
+	Active      bool
 	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
-func (ps *ProductService) CreateProducts(ctx context.Context) error {
-	err := ps.repo.CreateProduct(ctx)
-	if err != nil {
+type ProductVersionOutput struct {
+	ProductId   int        `json:"productId"`
+	Name        string     `json:"name"`
+	ProductCode string     `json:"productCode"`
+	Description string     `json:"description"`
+	Active      bool       `json:"active"`
+	SyncRunID   *uuid.UUID `json:"syncRunId"`
+	ValidFrom   time.Time  `json:"validFrom"`
+	ValidTo     *time.Time `json:"validTo"`
+}
+
+type ProductRiskTypeInput struct {
+	RiskTypeID int
+}
+
+type ProductRiskTypeOutput struct {
+	ID           uuid.UUID            `json:"id"`
+	RiskTypeId   int                  `json:"riskTypeId"`
+	Name         string               `json:"name"`
+	RiskCategory string               `json:"riskCategory"`
+	RiskTypeCode string               `json:"riskTypeCode"`
+	Description  string               `json:"description"`
+	Source       domain.MappingSource `json:"source"`
+}
+
+// SyncInput controls a sync. Force applies a catalog without usable records,
+// deactivating every entry; without it such a sync is refused.
+type SyncInput struct {
+	TriggerSource   string
+	DryRun          bool
+	Force           bool
+	SkipIfUnchanged bool
+}
+
+// ImportInput loads the catalog from a fixture file in the NIC shape instead
+// of downloading it from NIC.
+type ImportInput struct {
+	TriggerSource string
+	DryRun        bool
+	Force         bool
+	Format        string
+	Data          []byte
+}
+
+type SyncOutput struct {
+	RunID         uuid.UUID              `json:"runId"`
+	DryRun        bool                   `json:"dryRun"`
+	Skipped       bool                   `json:"skipped"`
+	UpstreamCount int                    `json:"upstreamCount"`
+	Added         int                    `json:"added"`
+	Updated       int                    `json:"updated"`
+	Deactivated   int                    `json:"deactivated"`
+	Unchanged     int                    `json:"unchanged"`
+	Changes       []domain.SyncChange    `json:"changes"`
+	Rejected      []domain.SyncRejection `json:"rejected"`
+}
+
+type SyncRunOutput struct {
+	ID            uuid.UUID              `json:"id"`
+	TriggerSource string                 `json:"triggerSource"`
+	DryRun        bool                   `json:"dryRun"`
+	StartedAt     time.Time              `json:"startedAt"`
+	FinishedAt    *time.Time             `json:"finishedAt"`
+	Outcome       domain.SyncOutcome     `json:"outcome"`
+	Error         string                 `json:"error,omitempty"`
+	UpstreamCount int                    `json:"upstreamCount"`
+	Added         int                    `json:"added"`
+	Updated       int                    `json:"updated"`
+	Deactivated   int                    `json:"deactivated"`
+	Unchanged     int                    `json:"unchanged"`
+	Changes       []domain.SyncChange    `json:"changes"`
+	Rejected      []domain.SyncRejection `json:"rejected"`
+	Checksum      string                 `json:"checksum,omitempty"`
+}
+
+type ProductsInput struct {
+	AsOf   string
+	Search string
+	Code   string
+	Active string
+	Sort   string
+	Limit  int
+	Cursor string
+}
+
+type ProductsOutput struct {
+	Products   []ProductOutput
+	Total      int
+	NextCursor string
+}
+
+func (pi *ProductRiskTypeInput) Validate() error {
+	if pi.RiskTypeID <= 0 {
+		return errors.New("riskTypeId is required")
+	}
+	return nil
+}
+
+func (si *SyncInput) Validate() error {
+	if strings.TrimSpace(si.TriggerSource) == "" {
+		return errors.New("trigger source is required")
+	}
+	return nil
+}
+
+func (ii *ImportInput) Validate() error {
+	if strings.TrimSpace(ii.TriggerSource) == "" {
+		return errors.New("trigger source is required")
+	}
+	if _, err := domain.ParseCatalogFileFormat(ii.Format); err != nil {
 		return err
 	}
+	if len(bytes.TrimSpace(ii.Data)) == 0 {
+		return errors.New("file is empty")
+	}
 	return nil
 }
 
-func (ps *ProductService) GetProducts(ctx context.Context) ([]ProductOutput, error) {
-	results, err := ps.repo.GetProducts(ctx)
+// CreateProducts syncs the catalog from NIC and records the run, whether it
+// succeeds or fails. With SkipIfUnchanged the catalog is only reconciled when
+// the NIC payload differs from the one last applied.
+func (ps *ProductService) CreateProducts(ctx context.Context, input SyncInput) (*SyncOutput, error) {
+	return ps.sync(ctx, input, nil)
+}
+
+// ImportProducts reconciles the catalog with a fixture file exactly as a NIC
+// sync would, recording the run and catalog versions. The run's trigger
+// source is prefixed with "import:" so imports stand out in the sync history.
+func (ps *ProductService) ImportProducts(ctx context.Context, input ImportInput) (*SyncOutput, error) {
+	format, err := domain.ParseCatalogFileFormat(input.Format)
 	if err != nil {
 		return nil, err
 	}
-	products := make([]ProductOutput, len(results))
-	for i, result := range results {
-		products[i] = ProductOutput{
-			ID:          result.ID,
-			Name:        result.Name,
-			ProductCode: result.ProductCode,
-			ProductId:   result.ProductId,
-			Description: result.Description,
-			CreatedAt:   result.CreatedAt,
+	return ps.sync(ctx, SyncInput{
+		TriggerSource: "import:" + input.TriggerSource,
+		DryRun:        input.DryRun,
+		Force:         input.Force,
+	}, &domain.CatalogFile{Format: format, Data: input.Data})
+}
+
+// ExportProducts renders the active catalog as a fixture file that
+// ImportProducts accepts.
+func (ps *ProductService) ExportProducts(ctx context.Context, format domain.CatalogFileFormat) ([]byte, error) {
+	return ps.repo.ExportProducts(ctx, format)
+}
+
+func (ps *ProductService) sync(ctx context.Context, input SyncInput, source *domain.CatalogFile) (*SyncOutput, error) {
+	opts := domain.SyncOptions{DryRun: input.DryRun, Force: input.Force, Source: source}
+	if input.SkipIfUnchanged && !input.DryRun {
+		last, err := ps.runs.GetLastSuccessfulSyncRun(ctx, domain.CatalogProducts)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		if last != nil {
+			opts.SkipIfChecksum = last.Checksum
 		}
 	}
-	return products, nil
+
+	run, err := ps.runs.StartSyncRun(ctx, domain.SyncRun{
+		Catalog:       domain.CatalogProducts,
+		TriggerSource: input.TriggerSource,
+		DryRun:        input.DryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	opts.RunID = run.ID
+	result, syncErr := ps.repo.CreateProduct(ctx, opts)
+	run.Finish(result, syncErr)
+	// Record the outcome even if the caller has gone away.
+	if err := ps.runs.FinishSyncRun(context.WithoutCancel(ctx), *run); err != nil {
+		log.Error().Err(err).Str("run", run.ID.String()).Msg("Error recording sync run")
+	}
+	if syncErr != nil {
+		return nil, syncErr
+	}
+
+	return &SyncOutput{
+		RunID:         run.ID,
+		DryRun:        result.DryRun,
+		Skipped:       result.Skipped,
+		UpstreamCount: result.UpstreamCount,
+		Added:         result.Summary.Added,
+		Updated:       result.Summary.Updated,
+		Deactivated:   result.Summary.Deactivated,
+		Unchanged:     result.Summary.Unchanged,
+		Changes:       result.Changes,
+		Rejected:      result.Rejected,
+	}, nil
+}
+
+func (ps *ProductService) GetSyncRuns(ctx context.Context, limit int) ([]SyncRunOutput, error) {
+	if limit <= 0 {
+		limit = defaultSyncRunLimit
+	}
+	results, err := ps.runs.GetSyncRuns(ctx, domain.CatalogProducts, limit)
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]SyncRunOutput, len(results))
+	for i, r := range results {
+		runs[i] = SyncRunOutput{
+			ID:            r.ID,
+			TriggerSource: r.TriggerSource,
+			DryRun:        r.DryRun,
+			StartedAt:     r.StartedAt,
+			FinishedAt:    r.FinishedAt,
+			Outcome:       r.Outcome,
+			Error:         r.Error,
+			UpstreamCount: r.UpstreamCount,
+			Added:         r.Summary.Added,
+			Updated:       r.Summary.Updated,
+			Deactivated:   r.Summary.Deactivated,
+			Unchanged:     r.Summary.Unchanged,
+			Changes:       r.Changes,
+			Rejected:      r.Rejected,
+			Checksum:      r.Checksum,
+		}
+	}
+	return runs, nil
+}
+
+// LastSyncedAt returns when the catalog was last confirmed to match NIC, or
+// nil if it never has been.
+func (ps *ProductService) LastSyncedAt(ctx context.Context) (*time.Time, error) {
+	last, err := ps.runs.GetLastSuccessfulSyncRun(ctx, domain.CatalogProducts)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return last.FinishedAt, nil
+}
+
+func (ci *ProductsInput) Validate() error {
+	asOf, err := ci.asOf()
+	if err != nil {
+		return err
+	}
+	if asOf != nil && (ci.Search != "" || ci.Code != "" || ci.Active != "" || ci.Sort != "" || ci.Limit > 0 || ci.Cursor != "") {
+		return errors.New("asOf cannot be combined with search, filters, sorting or pagination")
+	}
+	if ci.Limit > maxPageSize {
+		return fmt.Errorf("limit must be at most %d", maxPageSize)
+	}
+	_, err = ci.query()
+	return err
+}
+
+// asOf returns the moment the catalog should be read at, or nil for the
+// current catalog. A bare date means the end of that day in Accra, so changes
+// made during the day are included.
+func (ci *ProductsInput) asOf() (*time.Time, error) {
+	if strings.TrimSpace(ci.AsOf) == "" {
+		return nil, nil
+	}
+	t, dateOnly, err := pkg.ParseDate(ci.AsOf)
+	if err != nil {
+		return nil, errors.New("asOf must be an ISO-8601 date or date-time")
+	}
+	if dateOnly {
+		t = pkg.EndOfDay(t)
+	}
+	return &t, nil
+}
+
+// query turns the listing parameters into a catalog query. Without an
+// explicit sort, searches are ordered by relevance and everything else by
+// name.
+func (ci *ProductsInput) query() (domain.CatalogQuery, error) {
+	query := domain.CatalogQuery{
+		Search: strings.TrimSpace(ci.Search),
+		Code:   strings.TrimSpace(ci.Code),
+		Limit:  ci.Limit,
+	}
+
+	if active := strings.TrimSpace(ci.Active); active != "" {
+		b, err := strconv.ParseBool(active)
+		if err != nil {
+			return query, errors.New("active must be true or false")
+		}
+		query.Active = &b
+	}
+
+	switch sort := strings.TrimSpace(ci.Sort); {
+	case sort != "":
+		by, descending, err := domain.ParseCatalogSort(sort)
+		if err != nil {
+			return query, err
+		}
+		query.Sort, query.Descending = by, descending
+	case query.Search != "":
+		query.Sort, query.Descending = domain.CatalogSortRelevance, true
+	default:
+		query.Sort = domain.CatalogSortName
+	}
+
+	if cursor := strings.TrimSpace(ci.Cursor); cursor != "" {
+		after, err := domain.ParseCatalogCursor(cursor)
+		if err != nil {
+			return query, err
+		}
+		query.After = after
+	}
+	return query, nil
+}
+
+// GetProducts lists the catalog, either as it stands now with the requested
+// search, filters and paging, or as it stood at input.AsOf.
+func (ps *ProductService) GetProducts(ctx context.Context, input ProductsInput) (*ProductsOutput, error) {
+	asOf, err := input.asOf()
+	if err != nil {
+		return nil, err
+	}
+	var page *domain.CatalogPage[domain.Product]
+	if asOf != nil {
+		results, err := ps.repo.GetProductsAsOf(ctx, *asOf)
+		if err != nil {
+			return nil, err
+		}
+		page = &domain.CatalogPage[domain.Product]{Items: results, Total: len(results)}
+	} else {
+		query, err := input.query()
+		if err != nil {
+			return nil, err
+		}
+		page, err = ps.repo.SearchProducts(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	products := make([]ProductOutput, len(page.Items))
+	for i, result := range page.Items {
+		products[i] = newProductOutput(result)
+	}
+
+	output := &ProductsOutput{Products: products, Total: page.Total}
+	if page.Next != nil {
+		output.NextCursor = page.Next.Encode()
+	}
+	return output, nil
+}
+
+// GetProductHistory returns every version of a product, oldest first. ref is
+// our UUID or the NIC numeric ID.
+func (ps *ProductService) GetProductHistory(ctx context.Context, ref string) ([]ProductVersionOutput, error) {
+	parsed, err := domain.ParseCatalogRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.ID != uuid.Nil {
+		current, err := ps.repo.GetProduct(ctx, parsed)
+		if err != nil {
+			return nil, err
+		}
+		parsed.ExternalID = current.ProductId
+	}
+	results, err := ps.repo.GetProductHistory(ctx, parsed.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]ProductVersionOutput, len(results))
+	for i, v := range results {
+		versions[i] = ProductVersionOutput{
+			ProductId:   v.ProductId,
+			Name:        v.Name,
+			ProductCode: v.ProductCode,
+			Description: v.Description,
+			Active:      v.Active,
+			SyncRunID:   v.SyncRunID,
+			ValidFrom:   v.ValidFrom,
+			ValidTo:     v.ValidTo,
+		}
+	}
+	return versions, nil
+}
+
+// GetProduct looks up a product by our UUID or its NIC numeric ID.
+func (ps *ProductService) GetProduct(ctx context.Context, ref string) (*ProductOutput, error) {
+	result, err := ps.resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	output := newProductOutput(result)
+	return &output, nil
+}
+
+func (ps *ProductService) GetProductByCode(ctx context.Context, code string) (*ProductOutput, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, domain.ErrNotFound
+	}
+	result, err := ps.repo.GetProductByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	output := newProductOutput(result)
+	return &output, nil
+}
+
+// GetProductRiskTypes lists the active risk types allowed for a product.
+func (ps *ProductService) GetProductRiskTypes(ctx context.Context, ref string) ([]ProductRiskTypeOutput, error) {
+	product, err := ps.resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	results, err := ps.repo.GetProductRiskTypes(ctx, product.ProductId)
+	if err != nil {
+		return nil, err
+	}
+	riskTypes := make([]ProductRiskTypeOutput, len(results))
+	for i, r := range results {
+		riskTypes[i] = ProductRiskTypeOutput{
+			ID:           r.RiskType.ID,
+			RiskTypeId:   r.RiskType.RiskTypeId,
+			Name:         r.RiskType.Name,
+			RiskCategory: r.RiskType.RiskCategory,
+			RiskTypeCode: r.RiskType.RiskTypeCode,
+			Description:  r.RiskType.Description,
+			Source:       r.Source,
+		}
+	}
+	return riskTypes, nil
+}
+
+func (ps *ProductService) AddProductRiskType(ctx context.Context, ref string, input ProductRiskTypeInput) error {
+	product, err := ps.resolve(ctx, ref)
+	if err != nil {
+		return err
+	}
+	return ps.repo.AddProductRiskType(ctx, product.ProductId, input.RiskTypeID)
+}
+
+// RemoveProductRiskType drops a mapping whatever its source. A mapping that
+// came from NIC is restored by the next product sync if NIC still lists it.
+func (ps *ProductService) RemoveProductRiskType(ctx context.Context, ref string, riskTypeID int) error {
+	product, err := ps.resolve(ctx, ref)
+	if err != nil {
+		return err
+	}
+	return ps.repo.RemoveProductRiskType(ctx, product.ProductId, riskTypeID)
+}
+
+func (ps *ProductService) resolve(ctx context.Context, ref string) (*domain.Product, error) {
+	parsed, err := domain.ParseCatalogRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	return ps.repo.GetProduct(ctx, parsed)
+}
+
+func newProductOutput(p *domain.Product) ProductOutput {
+	return ProductOutput{
+		ID:          p.ID,
+		Name:        p.Name,
+		ProductCode: p.ProductCode,
+		ProductId:   p.ProductId,
+		Description: p.Description,
+		Active:      p.Active,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
 }
 
-func NewProductService(repo ProductPorts) ProductService {
-	return ProductService{repo: repo}
+func NewProductService(repo ProductPorts, runs SyncRunPort) ProductService {
+	return ProductService{repo: repo, runs: runs}
 }
@@ -0,0 +1,15 @@
+package quota
+
+import (
+	"context"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+)
+
+type QuotaPort interface {
+	ConsumeQuota(ctx context.Context, clientID uuid.UUID, quota domain.Quota, windowStart time.Time, amount, limit int) (int, bool, error)
+	GetQuotaUsage(ctx context.Context, clientID uuid.UUID, quota domain.Quota, windowStart time.Time) (int, error)
+	RefundQuota(ctx context.Context, clientID uuid.UUID, quota domain.Quota, windowStart time.Time, amount int) error
+}
@@ -0,0 +1,172 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRateLimited        = errors.New("request rate limit exceeded")
+	ErrPlateQuotaExceeded = errors.New("daily plate quota exceeded")
+	ErrBatchTooLarge      = errors.New("batch has more plates than the client may submit at once")
+	ErrApprovalRequired   = errors.New("daily allowance of plates issued without approval used up")
+)
+
+// QuotaService enforces per-client limits. Request rates are counted in
+// fixed one minute windows and plates in calendar days in Accra.
+type QuotaService struct {
+	repo QuotaPort
+}
+
+// QuotaOutput is a client's standing against each of its limits. Unlimited
+// quotas are null.
+type QuotaOutput struct {
+	ClientID          uuid.UUID           `json:"clientId"`
+	RequestsPerMinute *domain.QuotaStatus `json:"requestsPerMinute"`
+	PlatesPerDay      *domain.QuotaStatus `json:"platesPerDay"`
+	MaxBatchSize      int                 `json:"maxBatchSize"`
+}
+
+// AllowRequest counts a request against the client's per-minute limit. The
+// status is returned whenever the client has a limit, also alongside
+// ErrRateLimited.
+func (qs *QuotaService) AllowRequest(ctx context.Context, client *domain.APIClient) (*domain.QuotaStatus, error) {
+	return qs.consume(ctx, client.ID, domain.QuotaRequestsPerMinute, client.Limits.RequestsPerMinute, 1, ErrRateLimited)
+}
+
+// AllowPlates checks a batch against the client's maximum batch size and
+// counts it against its daily plate quota. It is called before any NIC work
+// starts, so a refused batch costs nothing.
+func (qs *QuotaService) AllowPlates(ctx context.Context, client *domain.APIClient, plates int) (*domain.QuotaStatus, error) {
+	if plates == 0 {
+		return nil, nil
+	}
+	if limit := client.Limits.BatchLimit(); limit > 0 && plates > limit {
+		return nil, ErrBatchTooLarge
+	}
+	return qs.consume(ctx, client.ID, domain.QuotaPlatesPerDay, client.Limits.PlatesPerDay, plates, ErrPlateQuotaExceeded)
+}
+
+// RefundPlates gives back plates counted by AllowPlates for a batch that was
+// then refused before any NIC work started.
+func (qs *QuotaService) RefundPlates(ctx context.Context, client *domain.APIClient, plates int) error {
+	return qs.refund(ctx, client.ID, domain.QuotaPlatesPerDay, client.Limits.PlatesPerDay, plates)
+}
+
+// CheckPlates answers as AllowPlates would for a batch without counting it,
+// so the client's quota is left as it was.
+func (qs *QuotaService) CheckPlates(ctx context.Context, client *domain.APIClient, plates int) (*domain.QuotaStatus, error) {
+	if plates == 0 {
+		return nil, nil
+	}
+	if limit := client.Limits.BatchLimit(); limit > 0 && plates > limit {
+		return nil, ErrBatchTooLarge
+	}
+	status, err := qs.status(ctx, client.ID, domain.QuotaPlatesPerDay, client.Limits.PlatesPerDay)
+	if err != nil {
+		return nil, err
+	}
+	if status != nil && plates > status.Remaining {
+		return status, ErrPlateQuotaExceeded
+	}
+	return status, nil
+}
+
+// AllowDirectIssuance counts a sticker or brown card batch issued without
+// an issuance request against limit, the plates a client may issue that way
+// per day, so a large batch cannot be split into smaller direct ones.
+func (qs *QuotaService) AllowDirectIssuance(ctx context.Context, client *domain.APIClient, plates, limit int) (*domain.QuotaStatus, error) {
+	return qs.consume(ctx, client.ID, domain.QuotaDirectIssuancePerDay, limit, plates, ErrApprovalRequired)
+}
+
+// CheckDirectIssuance answers as AllowDirectIssuance would without counting
+// the batch.
+func (qs *QuotaService) CheckDirectIssuance(ctx context.Context, client *domain.APIClient, plates, limit int) (*domain.QuotaStatus, error) {
+	status, err := qs.status(ctx, client.ID, domain.QuotaDirectIssuancePerDay, limit)
+	if err != nil {
+		return nil, err
+	}
+	if status != nil && plates > status.Remaining {
+		return status, ErrApprovalRequired
+	}
+	return status, nil
+}
+
+// GetQuota reports what the client has left without using any of it.
+func (qs *QuotaService) GetQuota(ctx context.Context, client *domain.APIClient) (*QuotaOutput, error) {
+	requests, err := qs.status(ctx, client.ID, domain.QuotaRequestsPerMinute, client.Limits.RequestsPerMinute)
+	if err != nil {
+		return nil, err
+	}
+	plates, err := qs.status(ctx, client.ID, domain.QuotaPlatesPerDay, client.Limits.PlatesPerDay)
+	if err != nil {
+		return nil, err
+	}
+	return &QuotaOutput{
+		ClientID:          client.ID,
+		RequestsPerMinute: requests,
+		PlatesPerDay:      plates,
+		MaxBatchSize:      client.Limits.MaxBatchSize,
+	}, nil
+}
+
+func (qs *QuotaService) consume(ctx context.Context, clientID uuid.UUID, quota domain.Quota, limit, amount int, exceeded error) (*domain.QuotaStatus, error) {
+	if limit == 0 {
+		return nil, nil
+	}
+	start, resetsAt := quotaWindow(quota, time.Now())
+	used, ok, err := qs.repo.ConsumeQuota(ctx, clientID, quota, start, amount, limit)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if used, err = qs.repo.GetQuotaUsage(ctx, clientID, quota, start); err != nil {
+			return nil, err
+		}
+	}
+	status := &domain.QuotaStatus{Limit: limit, Used: used, Remaining: max(limit-used, 0), ResetsAt: resetsAt}
+	if !ok {
+		return status, exceeded
+	}
+	return status, nil
+}
+
+func (qs *QuotaService) refund(ctx context.Context, clientID uuid.UUID, quota domain.Quota, limit, amount int) error {
+	if limit == 0 || amount == 0 {
+		return nil
+	}
+	start, _ := quotaWindow(quota, time.Now())
+	return qs.repo.RefundQuota(ctx, clientID, quota, start, amount)
+}
+
+func (qs *QuotaService) status(ctx context.Context, clientID uuid.UUID, quota domain.Quota, limit int) (*domain.QuotaStatus, error) {
+	if limit == 0 {
+		return nil, nil
+	}
+	start, resetsAt := quotaWindow(quota, time.Now())
+	used, err := qs.repo.GetQuotaUsage(ctx, clientID, quota, start)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.QuotaStatus{Limit: limit, Used: used, Remaining: max(limit-used, 0), ResetsAt: resetsAt}, nil
+}
+
+// quotaWindow returns the start and end of the window now falls in.
+func quotaWindow(quota domain.Quota, now time.Time) (time.Time, time.Time) {
+	if quota != domain.QuotaRequestsPerMinute {
+		local := now.In(pkg.Accra)
+		start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, pkg.Accra)
+		return start, start.AddDate(0, 0, 1)
+	}
+	start := now.Truncate(time.Minute)
+	return start, start.Add(time.Minute)
+}
+
+func NewQuotaService(repo QuotaPort) QuotaService {
+	return QuotaService{repo: repo}
+}
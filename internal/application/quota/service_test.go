@@ -0,0 +1,177 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+)
+
+func TestQuotaWindow(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, pkg.Accra)
+	}
+	tests := []struct {
+		name      string
+		quota     domain.Quota
+		now       time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "plates at midnight in Accra",
+			quota:     domain.QuotaPlatesPerDay,
+			now:       time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+			wantStart: day(2026, 3, 2),
+			wantEnd:   day(2026, 3, 3),
+		},
+		{
+			name:      "plates in the last second of the day",
+			quota:     domain.QuotaPlatesPerDay,
+			now:       time.Date(2026, 3, 1, 23, 59, 59, 999999999, time.UTC),
+			wantStart: day(2026, 3, 1),
+			wantEnd:   day(2026, 3, 2),
+		},
+		{
+			name:      "plates west of Accra already past midnight there",
+			quota:     domain.QuotaPlatesPerDay,
+			now:       time.Date(2026, 3, 1, 23, 30, 0, 0, time.FixedZone("west", -3600)),
+			wantStart: day(2026, 3, 2),
+			wantEnd:   day(2026, 3, 3),
+		},
+		{
+			name:      "plates east of Accra not yet past midnight there",
+			quota:     domain.QuotaPlatesPerDay,
+			now:       time.Date(2026, 3, 2, 0, 30, 0, 0, time.FixedZone("east", 3600)),
+			wantStart: day(2026, 3, 1),
+			wantEnd:   day(2026, 3, 2),
+		},
+		{
+			name:      "direct issuance across the end of the year",
+			quota:     domain.QuotaDirectIssuancePerDay,
+			now:       time.Date(2026, 12, 31, 12, 0, 0, 0, time.UTC),
+			wantStart: day(2026, 12, 31),
+			wantEnd:   day(2027, 1, 1),
+		},
+		{
+			name:      "requests at the start of a minute",
+			quota:     domain.QuotaRequestsPerMinute,
+			now:       time.Date(2026, 3, 1, 10, 15, 0, 0, time.UTC),
+			wantStart: time.Date(2026, 3, 1, 10, 15, 0, 0, time.UTC),
+			wantEnd:   time.Date(2026, 3, 1, 10, 16, 0, 0, time.UTC),
+		},
+		{
+			name:      "requests at the end of a minute",
+			quota:     domain.QuotaRequestsPerMinute,
+			now:       time.Date(2026, 3, 1, 10, 15, 59, 999999999, time.UTC),
+			wantStart: time.Date(2026, 3, 1, 10, 15, 0, 0, time.UTC),
+			wantEnd:   time.Date(2026, 3, 1, 10, 16, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := quotaWindow(tt.quota, tt.now)
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("quotaWindow(%s, %s) = %s, %s; want %s, %s", tt.quota, tt.now, start, end, tt.wantStart, tt.wantEnd)
+			}
+			if tt.now.Before(start) || !tt.now.Before(end) {
+				t.Errorf("quotaWindow(%s, %s) = %s, %s; does not contain now", tt.quota, tt.now, start, end)
+			}
+		})
+	}
+}
+
+// quotaRepo keeps usage in memory the way QuotaRepository keeps it in
+// Postgres: a batch is only counted when it fits in what is left.
+type quotaRepo struct {
+	used map[domain.Quota]int
+}
+
+func (qr *quotaRepo) ConsumeQuota(_ context.Context, _ uuid.UUID, quota domain.Quota, _ time.Time, amount, limit int) (int, bool, error) {
+	if qr.used[quota]+amount > limit {
+		return 0, false, nil
+	}
+	qr.used[quota] += amount
+	return qr.used[quota], true, nil
+}
+
+func (qr *quotaRepo) GetQuotaUsage(_ context.Context, _ uuid.UUID, quota domain.Quota, _ time.Time) (int, error) {
+	return qr.used[quota], nil
+}
+
+func (qr *quotaRepo) RefundQuota(_ context.Context, _ uuid.UUID, quota domain.Quota, _ time.Time, amount int) error {
+	qr.used[quota] = max(qr.used[quota]-amount, 0)
+	return nil
+}
+
+func TestAllowPlates(t *testing.T) {
+	limits := domain.ClientLimits{PlatesPerDay: 10, MaxBatchSize: 6}
+	tests := []struct {
+		name          string
+		limits        domain.ClientLimits
+		used          int
+		plates        int
+		wantErr       error
+		wantUsed      int
+		wantRemaining int
+	}{
+		{name: "fits", limits: limits, plates: 4, wantUsed: 4, wantRemaining: 6},
+		{name: "uses up the day exactly", limits: limits, used: 4, plates: 6, wantUsed: 10, wantRemaining: 0},
+		{name: "one plate over the day", limits: limits, used: 5, plates: 6, wantErr: ErrPlateQuotaExceeded, wantUsed: 5, wantRemaining: 5},
+		{name: "larger than a batch", limits: limits, plates: 7, wantErr: ErrBatchTooLarge},
+		{name: "larger than the day when the day is the smaller limit", limits: domain.ClientLimits{PlatesPerDay: 3, MaxBatchSize: 6}, plates: 4, wantErr: ErrBatchTooLarge},
+		{name: "unlimited", limits: domain.ClientLimits{}, plates: 1000},
+		{name: "empty batch", limits: limits, used: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &quotaRepo{used: map[domain.Quota]int{domain.QuotaPlatesPerDay: tt.used}}
+			service := NewQuotaService(repo)
+			client := &domain.APIClient{ID: uuid.New(), Limits: tt.limits}
+			status, err := service.AllowPlates(context.Background(), client, tt.plates)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AllowPlates() error = %v, want %v", err, tt.wantErr)
+			}
+			if status == nil {
+				if tt.wantUsed != 0 || tt.wantRemaining != 0 {
+					t.Fatalf("AllowPlates() status = nil, want used %d, remaining %d", tt.wantUsed, tt.wantRemaining)
+				}
+				return
+			}
+			if status.Used != tt.wantUsed || status.Remaining != tt.wantRemaining {
+				t.Errorf("AllowPlates() used %d, remaining %d; want used %d, remaining %d", status.Used, status.Remaining, tt.wantUsed, tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestRefundPlates(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		used     int
+		refund   int
+		wantUsed int
+	}{
+		{name: "gives back a refused batch", limit: 10, used: 7, refund: 3, wantUsed: 4},
+		{name: "never goes below zero", limit: 10, used: 2, refund: 3, wantUsed: 0},
+		{name: "unlimited clients have nothing to refund", limit: 0, used: 7, refund: 3, wantUsed: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &quotaRepo{used: map[domain.Quota]int{domain.QuotaPlatesPerDay: tt.used}}
+			service := NewQuotaService(repo)
+			client := &domain.APIClient{ID: uuid.New(), Limits: domain.ClientLimits{PlatesPerDay: tt.limit}}
+			if err := service.RefundPlates(context.Background(), client, tt.refund); err != nil {
+				t.Fatalf("RefundPlates() error = %v", err)
+			}
+			if got := repo.used[domain.QuotaPlatesPerDay]; got != tt.wantUsed {
+				t.Errorf("used = %d, want %d", got, tt.wantUsed)
+			}
+		})
+	}
+}
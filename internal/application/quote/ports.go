@@ -0,0 +1,32 @@
+package quote
+
+import (
+	"context"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+)
+
+type ProductPort interface {
+	GetProduct(ctx context.Context, ref domain.CatalogRef) (*domain.Product, error)
+	GetProductRiskTypes(ctx context.Context, productID int) ([]*domain.ProductRiskType, error)
+}
+
+type RiskTypePort interface {
+	GetRiskType(ctx context.Context, ref domain.CatalogRef) (*domain.RiskType, error)
+}
+
+type PlateRiskRulePort interface {
+	GetPlateRiskRules(ctx context.Context) (domain.PlateRiskRules, error)
+}
+
+type QuotePorts interface {
+	CreateRateTable(ctx context.Context, table domain.RateTable) (*domain.RateTable, error)
+	GetRateTables(ctx context.Context) ([]*domain.RateTable, error)
+	GetRateTable(ctx context.Context, id uuid.UUID) (*domain.RateTable, error)
+	GetRateTableFor(ctx context.Context, productID, riskTypeID int) (*domain.RateTable, error)
+	UpdateRateTable(ctx context.Context, table domain.RateTable) (*domain.RateTable, error)
+	DeleteRateTable(ctx context.Context, id uuid.UUID) error
+	CreateQuote(ctx context.Context, quote domain.Quote) (*domain.Quote, error)
+	GetQuote(ctx context.Context, id uuid.UUID) (*domain.Quote, error)
+}
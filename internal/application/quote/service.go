@@ -0,0 +1,436 @@
+package quote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultCurrency = "GHS"
+	// maxCoverDays allows a full year of cover starting on any day, leap
+	// years included.
+	maxCoverDays = 366
+)
+
+var (
+	ErrInactiveProduct    = errors.New("product is not active")
+	ErrInactiveRiskType   = errors.New("risk type is not active")
+	ErrRiskTypeNotAllowed = errors.New("risk type is not allowed for the product")
+	ErrNoRateTable        = errors.New("no rate table for the product and risk type")
+	ErrNoDefaultRiskType  = errors.New("riskTypeId is required; there is no default risk type for the plate")
+)
+
+type QuoteService struct {
+	repo      QuotePorts
+	products  ProductPort
+	riskTypes RiskTypePort
+	rules     PlateRiskRulePort
+}
+
+// RatesInput is the part of a rate table an admin may change. Rates and
+// loadings are percentages.
+type RatesInput struct {
+	Currency             string
+	BaseRate             float64
+	SumInsuredBands      []domain.SumInsuredBand
+	VehicleClassLoadings []domain.VehicleClassLoading
+	MinimumPremium       domain.Money
+	Levies               []domain.Levy
+}
+
+type RateTableInput struct {
+	ProductID  int
+	RiskTypeID int
+	RatesInput
+}
+
+type RateTableOutput struct {
+	ID                   uuid.UUID                    `json:"id"`
+	ProductId            int                          `json:"productId"`
+	RiskTypeId           int                          `json:"riskTypeId"`
+	Currency             string                       `json:"currency"`
+	BaseRate             float64                      `json:"baseRate"`
+	SumInsuredBands      []domain.SumInsuredBand      `json:"sumInsuredBands"`
+	VehicleClassLoadings []domain.VehicleClassLoading `json:"vehicleClassLoadings"`
+	MinimumPremium       domain.Money                 `json:"minimumPremium"`
+	Levies               []domain.Levy                `json:"levies"`
+	CreatedAt            time.Time                    `json:"createdAt"`
+	UpdatedAt            time.Time                    `json:"updatedAt"`
+}
+
+type QuoteInput struct {
+	Plate        string
+	ProductID    int
+	RiskTypeID   int
+	VehicleClass string
+	SumInsured   domain.Money
+	StartDate    string
+	EndDate      string
+}
+
+type QuoteOutput struct {
+	ID           uuid.UUID          `json:"id"`
+	Plate        string             `json:"plate"`
+	ProductId    int                `json:"productId"`
+	RiskTypeId   int                `json:"riskTypeId"`
+	RateTableID  *uuid.UUID         `json:"rateTableId"`
+	VehicleClass string             `json:"vehicleClass"`
+	SumInsured   domain.Money       `json:"sumInsured"`
+	CoverStart   time.Time          `json:"coverStart"`
+	CoverEnd     time.Time          `json:"coverEnd"`
+	CoverDays    int                `json:"coverDays"`
+	Currency     string             `json:"currency"`
+	Rate         float64            `json:"rate"`
+	Lines        []domain.QuoteLine `json:"lines"`
+	Premium      domain.Money       `json:"premium"`
+	Levies       domain.Money       `json:"levies"`
+	Total        domain.Money       `json:"total"`
+	Warnings     []string           `json:"warnings"`
+	CreatedAt    time.Time          `json:"createdAt"`
+}
+
+func (ri *RatesInput) Validate() error {
+	if currency := strings.TrimSpace(ri.Currency); currency != "" && len(currency) != 3 {
+		return errors.New("currency must be an ISO 4217 code")
+	}
+	if err := validRate("baseRate", ri.BaseRate); err != nil {
+		return err
+	}
+	if ri.MinimumPremium < 0 {
+		return errors.New("minimumPremium cannot be negative")
+	}
+	for i, band := range ri.SumInsuredBands {
+		if band.From < 0 || band.To < 0 {
+			return fmt.Errorf("sumInsuredBands[%d] cannot have negative bounds", i)
+		}
+		if band.To != 0 && band.To < band.From {
+			return fmt.Errorf("sumInsuredBands[%d] ends before it starts", i)
+		}
+		if err := validRate(fmt.Sprintf("sumInsuredBands[%d].rate", i), band.Rate); err != nil {
+			return err
+		}
+	}
+	classes := make([]string, 0, len(ri.VehicleClassLoadings))
+	for i, loading := range ri.VehicleClassLoadings {
+		class := strings.ToLower(strings.TrimSpace(loading.VehicleClass))
+		if class == "" {
+			return fmt.Errorf("vehicleClassLoadings[%d].vehicleClass is required", i)
+		}
+		if slices.Contains(classes, class) {
+			return fmt.Errorf("vehicle class %q is loaded more than once", loading.VehicleClass)
+		}
+		classes = append(classes, class)
+		if loading.Loading < -100 {
+			return fmt.Errorf("vehicleClassLoadings[%d].loading cannot discount more than 100%%", i)
+		}
+	}
+	for i, levy := range ri.Levies {
+		if strings.TrimSpace(levy.Code) == "" {
+			return fmt.Errorf("levies[%d].code is required", i)
+		}
+		if err := validRate(fmt.Sprintf("levies[%d].rate", i), levy.Rate); err != nil {
+			return err
+		}
+		if levy.Fixed < 0 {
+			return fmt.Errorf("levies[%d].fixed cannot be negative", i)
+		}
+	}
+	return nil
+}
+
+func (ri *RateTableInput) Validate() error {
+	if ri.ProductID <= 0 {
+		return errors.New("productId is required")
+	}
+	if ri.RiskTypeID <= 0 {
+		return errors.New("riskTypeId is required")
+	}
+	return ri.RatesInput.Validate()
+}
+
+func (qi *QuoteInput) Validate() error {
+	if valid, msg := pkg.ValidateGhanaLicensePlate(qi.Plate); !valid {
+		return errors.New(msg)
+	}
+	if qi.ProductID <= 0 {
+		return errors.New("productId is required")
+	}
+	if qi.RiskTypeID < 0 {
+		return errors.New("riskTypeId must be a NIC risk type id")
+	}
+	if qi.SumInsured <= 0 {
+		return errors.New("sumInsured must be greater than zero")
+	}
+	_, _, _, err := qi.coverPeriod()
+	return err
+}
+
+// coverPeriod returns the start and end of cover and the number of days it
+// spans. A bare end date covers the whole of that day, so 1 January to 31
+// December is 365 days.
+func (qi *QuoteInput) coverPeriod() (start, end time.Time, days int, err error) {
+	start, _, err = pkg.ParseDate(qi.StartDate)
+	if err != nil {
+		return start, end, 0, errors.New("startDate must be an ISO-8601 date or date-time")
+	}
+	end, dateOnly, err := pkg.ParseDate(qi.EndDate)
+	if err != nil {
+		return start, end, 0, errors.New("endDate must be an ISO-8601 date or date-time")
+	}
+	if dateOnly {
+		end = pkg.EndOfDay(end)
+	}
+	if !end.After(start) {
+		return start, end, 0, errors.New("endDate must be after startDate")
+	}
+	days = int(math.Ceil(end.Sub(start).Hours() / 24))
+	if days > maxCoverDays {
+		return start, end, 0, fmt.Errorf("cover period cannot be longer than %d days", maxCoverDays)
+	}
+	return start, end, days, nil
+}
+
+func validRate(name string, rate float64) error {
+	if rate < 0 || rate > 100 {
+		return fmt.Errorf("%s must be a percentage between 0 and 100", name)
+	}
+	return nil
+}
+
+func (ri *RatesInput) apply(table *domain.RateTable) {
+	table.Currency = strings.ToUpper(strings.TrimSpace(ri.Currency))
+	if table.Currency == "" {
+		table.Currency = defaultCurrency
+	}
+	table.BaseRate = ri.BaseRate
+	table.SumInsuredBands = ri.SumInsuredBands
+	table.VehicleClassLoadings = make([]domain.VehicleClassLoading, len(ri.VehicleClassLoadings))
+	for i, l := range ri.VehicleClassLoadings {
+		table.VehicleClassLoadings[i] = domain.VehicleClassLoading{
+			VehicleClass: strings.TrimSpace(l.VehicleClass),
+			Loading:      l.Loading,
+		}
+	}
+	table.MinimumPremium = ri.MinimumPremium
+	table.Levies = make([]domain.Levy, len(ri.Levies))
+	for i, l := range ri.Levies {
+		table.Levies[i] = domain.Levy{
+			Code:  strings.ToUpper(strings.TrimSpace(l.Code)),
+			Name:  strings.TrimSpace(l.Name),
+			Rate:  l.Rate,
+			Fixed: l.Fixed,
+		}
+	}
+}
+
+// CreateRateTable adds the rate table for a product and risk type. There can
+// only be one per pair; a second one fails with domain.ErrConflict.
+func (qs *QuoteService) CreateRateTable(ctx context.Context, input RateTableInput) (*RateTableOutput, error) {
+	if _, err := qs.checkCover(ctx, input.ProductID, input.RiskTypeID); err != nil {
+		return nil, err
+	}
+	table := domain.RateTable{ProductId: input.ProductID, RiskTypeId: input.RiskTypeID}
+	input.apply(&table)
+	result, err := qs.repo.CreateRateTable(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	return toRateTableOutput(result), nil
+}
+
+func (qs *QuoteService) GetRateTables(ctx context.Context) ([]RateTableOutput, error) {
+	results, err := qs.repo.GetRateTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]RateTableOutput, len(results))
+	for i, result := range results {
+		tables[i] = *toRateTableOutput(result)
+	}
+	return tables, nil
+}
+
+func (qs *QuoteService) GetRateTable(ctx context.Context, id uuid.UUID) (*RateTableOutput, error) {
+	result, err := qs.repo.GetRateTable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toRateTableOutput(result), nil
+}
+
+// UpdateRateTable replaces the rates of a table. Its product and risk type
+// stay as they are; price another pair by creating a new table.
+func (qs *QuoteService) UpdateRateTable(ctx context.Context, id uuid.UUID, input RatesInput) (*RateTableOutput, error) {
+	table := domain.RateTable{ID: id}
+	input.apply(&table)
+	result, err := qs.repo.UpdateRateTable(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	return toRateTableOutput(result), nil
+}
+
+func (qs *QuoteService) DeleteRateTable(ctx context.Context, id uuid.UUID) error {
+	return qs.repo.DeleteRateTable(ctx, id)
+}
+
+// CreateQuote prices cover for a vehicle from the rate table of the chosen
+// product and risk type, and keeps the quote so it can be retrieved later.
+// Without a risk type, the plate rules' default for the plate is used; a
+// risk type the rules do not allow for the plate is quoted with a warning.
+func (qs *QuoteService) CreateQuote(ctx context.Context, input QuoteInput) (*QuoteOutput, error) {
+	start, end, days, err := input.coverPeriod()
+	if err != nil {
+		return nil, err
+	}
+
+	plate, _, _ := pkg.ParseGhanaLicensePlate(input.Plate)
+	all, err := qs.rules.GetPlateRiskRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules := all.For(string(plate.Kind), plate.Code)
+	riskTypeID := input.RiskTypeID
+	if riskTypeID == 0 {
+		def := rules.DefaultRiskTypeId()
+		if def == nil {
+			return nil, ErrNoDefaultRiskType
+		}
+		riskTypeID = *def
+	}
+
+	riskType, err := qs.checkCover(ctx, input.ProductID, riskTypeID)
+	if err != nil {
+		return nil, err
+	}
+	table, err := qs.repo.GetRateTableFor(ctx, input.ProductID, riskTypeID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, ErrNoRateTable
+		}
+		return nil, err
+	}
+
+	quote := domain.Quote{
+		Plate:        pkg.CanonicalPlate(input.Plate),
+		ProductId:    input.ProductID,
+		RiskTypeId:   riskTypeID,
+		VehicleClass: strings.TrimSpace(input.VehicleClass),
+		SumInsured:   input.SumInsured,
+		CoverStart:   start,
+		CoverEnd:     end,
+		CoverDays:    days,
+		Warnings:     make([]string, 0),
+	}
+	if warning := rules.Warning(string(plate.Kind), riskType); warning != "" {
+		quote.Warnings = append(quote.Warnings, warning)
+	}
+	table.Price(&quote)
+
+	result, err := qs.repo.CreateQuote(ctx, quote)
+	if err != nil {
+		return nil, err
+	}
+	return toQuoteOutput(result), nil
+}
+
+func (qs *QuoteService) GetQuote(ctx context.Context, id uuid.UUID) (*QuoteOutput, error) {
+	result, err := qs.repo.GetQuote(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toQuoteOutput(result), nil
+}
+
+// checkCover makes sure the product and risk type exist, are active and may
+// be sold together, and returns the risk type. Products without any risk type
+// mappings accept every risk type.
+func (qs *QuoteService) checkCover(ctx context.Context, productID, riskTypeID int) (*domain.RiskType, error) {
+	product, err := qs.products.GetProduct(ctx, domain.CatalogRef{ExternalID: productID})
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrUnknownProduct
+		}
+		return nil, err
+	}
+	if !product.Active {
+		return nil, ErrInactiveProduct
+	}
+
+	riskType, err := qs.riskTypes.GetRiskType(ctx, domain.CatalogRef{ExternalID: riskTypeID})
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrUnknownRiskType
+		}
+		return nil, err
+	}
+	if !riskType.Active {
+		return nil, ErrInactiveRiskType
+	}
+
+	allowed, err := qs.products.GetProductRiskTypes(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(allowed) == 0 {
+		return riskType, nil
+	}
+	for _, a := range allowed {
+		if a.RiskType.RiskTypeId == riskTypeID {
+			return riskType, nil
+		}
+	}
+	return nil, ErrRiskTypeNotAllowed
+}
+
+func toRateTableOutput(t *domain.RateTable) *RateTableOutput {
+	return &RateTableOutput{
+		ID:                   t.ID,
+		ProductId:            t.ProductId,
+		RiskTypeId:           t.RiskTypeId,
+		Currency:             t.Currency,
+		BaseRate:             t.BaseRate,
+		SumInsuredBands:      t.SumInsuredBands,
+		VehicleClassLoadings: t.VehicleClassLoadings,
+		MinimumPremium:       t.MinimumPremium,
+		Levies:               t.Levies,
+		CreatedAt:            t.CreatedAt,
+		UpdatedAt:            t.UpdatedAt,
+	}
+}
+
+func toQuoteOutput(q *domain.Quote) *QuoteOutput {
+	return &QuoteOutput{
+		ID:           q.ID,
+		Plate:        q.Plate,
+		ProductId:    q.ProductId,
+		RiskTypeId:   q.RiskTypeId,
+		RateTableID:  q.RateTableID,
+		VehicleClass: q.VehicleClass,
+		SumInsured:   q.SumInsured,
+		CoverStart:   q.CoverStart,
+		CoverEnd:     q.CoverEnd,
+		CoverDays:    q.CoverDays,
+		Currency:     q.Currency,
+		Rate:         q.Rate,
+		Lines:        q.Lines,
+		Premium:      q.Premium,
+		Levies:       q.Levies,
+		Total:        q.Total,
+		Warnings:     q.Warnings,
+		CreatedAt:    q.CreatedAt,
+	}
+}
+
+func NewQuoteService(repo QuotePorts, products ProductPort, riskTypes RiskTypePort, rules PlateRiskRulePort) QuoteService {
+	return QuoteService{repo: repo, products: products, riskTypes: riskTypes, rules: rules}
+}
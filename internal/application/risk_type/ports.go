@@ -2,11 +2,25 @@ package risk_type
 
 import (
 	"context"
+	"time"
 
 	"github.com/godsent-code/midtools/internal/domain"
 )
 
 type RiskTypePort interface {
-	GetRiskTypes(ctx context.Context) ([]*domain.RiskType, error)
-	CreateRiskType(ctx context.Context) error
+	SearchRiskTypes(ctx context.Context, query domain.CatalogQuery) (*domain.CatalogPage[domain.RiskType], error)
+	GetRiskTypesAsOf(ctx context.Context, asOf time.Time) ([]*domain.RiskType, error)
+	GetRiskType(ctx context.Context, ref domain.CatalogRef) (*domain.RiskType, error)
+	GetRiskTypeByCode(ctx context.Context, code string) (*domain.RiskType, error)
+	GetRiskCategories(ctx context.Context) ([]*domain.RiskCategory, error)
+	GetRiskTypeHistory(ctx context.Context, riskTypeID int) ([]*domain.RiskTypeVersion, error)
+	CreateRiskType(ctx context.Context, opts domain.SyncOptions) (*domain.SyncResult, error)
+	ExportRiskTypes(ctx context.Context, format domain.CatalogFileFormat) ([]byte, error)
+}
+
+type SyncRunPort interface {
+	StartSyncRun(ctx context.Context, run domain.SyncRun) (*domain.SyncRun, error)
+	FinishSyncRun(ctx context.Context, run domain.SyncRun) error
+	GetSyncRuns(ctx context.Context, catalog domain.Catalog, limit int) ([]*domain.SyncRun, error)
+	GetLastSuccessfulSyncRun(ctx context.Context, catalog domain.Catalog) (*domain.SyncRun, error)
 }
@@ -1,14 +1,28 @@
 package risk_type
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultSyncRunLimit = 20
+	maxPageSize         = 500
 )
 
 type RiskTypeService struct {
 	repo RiskTypePort
+	runs SyncRunPort
 }
 
 type RiskTypeOutput struct {
@@ -18,37 +32,442 @@ type RiskTypeOutput struct {
 	Description  string    `json:"description"`
 	RiskCategory string    `json:"riskCategory"`
 	RiskTypeCode string    `json:"riskTypeCode"`
+	Active       bool      `json:"active"`
 	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+type RiskTypeVersionOutput struct {
+	RiskTypeId   int        `json:"risk_type_id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	RiskCategory string     `json:"riskCategory"`
+	RiskTypeCode string     `json:"riskTypeCode"`
+	Active       bool       `json:"active"`
+	SyncRunID    *uuid.UUID `json:"syncRunId"`
+	ValidFrom    time.Time  `json:"validFrom"`
+	ValidTo      *time.Time `json:"validTo"`
+}
+
+type RiskCategoryOutput struct {
+	ID              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	ActiveRiskTypes int       `json:"activeRiskTypes"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// SyncInput controls a sync. Force applies a catalog without usable records,
+// deactivating every entry; without it such a sync is refused.
+type SyncInput struct {
+	TriggerSource   string
+	DryRun          bool
+	Force           bool
+	SkipIfUnchanged bool
+}
+
+// ImportInput loads the catalog from a fixture file in the NIC shape instead
+// of downloading it from NIC.
+type ImportInput struct {
+	TriggerSource string
+	DryRun        bool
+	Force         bool
+	Format        string
+	Data          []byte
+}
+
+type SyncOutput struct {
+	RunID         uuid.UUID              `json:"runId"`
+	DryRun        bool                   `json:"dryRun"`
+	Skipped       bool                   `json:"skipped"`
+	UpstreamCount int                    `json:"upstreamCount"`
+	Added         int                    `json:"added"`
+	Updated       int                    `json:"updated"`
+	Deactivated   int                    `json:"deactivated"`
+	Unchanged     int                    `json:"unchanged"`
+	Changes       []domain.SyncChange    `json:"changes"`
+	Rejected      []domain.SyncRejection `json:"rejected"`
+}
+
+type SyncRunOutput struct {
+	ID            uuid.UUID              `json:"id"`
+	TriggerSource string                 `json:"triggerSource"`
+	DryRun        bool                   `json:"dryRun"`
+	StartedAt     time.Time              `json:"startedAt"`
+	FinishedAt    *time.Time             `json:"finishedAt"`
+	Outcome       domain.SyncOutcome     `json:"outcome"`
+	Error         string                 `json:"error,omitempty"`
+	UpstreamCount int                    `json:"upstreamCount"`
+	Added         int                    `json:"added"`
+	Updated       int                    `json:"updated"`
+	Deactivated   int                    `json:"deactivated"`
+	Unchanged     int                    `json:"unchanged"`
+	Changes       []domain.SyncChange    `json:"changes"`
+	Rejected      []domain.SyncRejection `json:"rejected"`
+	Checksum      string                 `json:"checksum,omitempty"`
+}
+
+type RiskTypesInput struct {
+	AsOf     string
+	Search   string
+	Code     string
+	Category string
+	Active   string
+	Sort     string
+	Limit    int
+	Cursor   string
+}
+
+type RiskTypesOutput struct {
+	RiskTypes  []RiskTypeOutput
+	Total      int
+	NextCursor string
+}
+
+func (si *SyncInput) Validate() error {
+	if strings.TrimSpace(si.TriggerSource) == "" {
+		return errors.New("trigger source is required")
+	}
+	return nil
+}
+
+func (ii *ImportInput) Validate() error {
+	if strings.TrimSpace(ii.TriggerSource) == "" {
+		return errors.New("trigger source is required")
+	}
+	if _, err := domain.ParseCatalogFileFormat(ii.Format); err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(ii.Data)) == 0 {
+		return errors.New("file is empty")
+	}
+	return nil
+}
+
+func (ci *RiskTypesInput) Validate() error {
+	asOf, err := ci.asOf()
+	if err != nil {
+		return err
+	}
+	if asOf != nil && (ci.Search != "" || ci.Code != "" || ci.Category != "" || ci.Active != "" || ci.Sort != "" || ci.Limit > 0 || ci.Cursor != "") {
+		return errors.New("asOf cannot be combined with search, filters, sorting or pagination")
+	}
+	if ci.Limit > maxPageSize {
+		return fmt.Errorf("limit must be at most %d", maxPageSize)
+	}
+	_, err = ci.query()
+	return err
 }
 
-func (rrt *RiskTypeService) GetRiskTypes(ctx context.Context) ([]RiskTypeOutput, error) {
-	results, err := rrt.repo.GetRiskTypes(ctx)
+// asOf returns the moment the catalog should be read at, or nil for the
+// current catalog. A bare date means the end of that day in Accra, so changes
+// made during the day are included.
+func (ci *RiskTypesInput) asOf() (*time.Time, error) {
+	if strings.TrimSpace(ci.AsOf) == "" {
+		return nil, nil
+	}
+	t, dateOnly, err := pkg.ParseDate(ci.AsOf)
+	if err != nil {
+		return nil, errors.New("asOf must be an ISO-8601 date or date-time")
+	}
+	if dateOnly {
+		t = pkg.EndOfDay(t)
+	}
+	return &t, nil
+}
+
+// query turns the listing parameters into a catalog query. Without an
+// explicit sort, searches are ordered by relevance and everything else by
+// name.
+func (ci *RiskTypesInput) query() (domain.CatalogQuery, error) {
+	query := domain.CatalogQuery{
+		Search:   strings.TrimSpace(ci.Search),
+		Code:     strings.TrimSpace(ci.Code),
+		Category: strings.TrimSpace(ci.Category),
+		Limit:    ci.Limit,
+	}
+
+	if active := strings.TrimSpace(ci.Active); active != "" {
+		b, err := strconv.ParseBool(active)
+		if err != nil {
+			return query, errors.New("active must be true or false")
+		}
+		query.Active = &b
+	}
+
+	switch sort := strings.TrimSpace(ci.Sort); {
+	case sort != "":
+		by, descending, err := domain.ParseCatalogSort(sort)
+		if err != nil {
+			return query, err
+		}
+		query.Sort, query.Descending = by, descending
+	case query.Search != "":
+		query.Sort, query.Descending = domain.CatalogSortRelevance, true
+	default:
+		query.Sort = domain.CatalogSortName
+	}
+
+	if cursor := strings.TrimSpace(ci.Cursor); cursor != "" {
+		after, err := domain.ParseCatalogCursor(cursor)
+		if err != nil {
+			return query, err
+		}
+		query.After = after
+	}
+	return query, nil
+}
+
+// GetRiskTypes lists the catalog, either as it stands now with the requested
+// search, filters and paging, or as it stood at input.AsOf.
+func (rrt *RiskTypeService) GetRiskTypes(ctx context.Context, input RiskTypesInput) (*RiskTypesOutput, error) {
+	asOf, err := input.asOf()
 	if err != nil {
 		return nil, err
 	}
-	riskTypes := make([]RiskTypeOutput, len(results))
+	var page *domain.CatalogPage[domain.RiskType]
+	if asOf != nil {
+		results, err := rrt.repo.GetRiskTypesAsOf(ctx, *asOf)
+		if err != nil {
+			return nil, err
+		}
+		page = &domain.CatalogPage[domain.RiskType]{Items: results, Total: len(results)}
+	} else {
+		query, err := input.query()
+		if err != nil {
+			return nil, err
+		}
+		page, err = rrt.repo.SearchRiskTypes(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	riskTypes := make([]RiskTypeOutput, len(page.Items))
+
+	for i, r := range page.Items {
+		riskTypes[i] = newRiskTypeOutput(r)
+	}
+
+	output := &RiskTypesOutput{RiskTypes: riskTypes, Total: page.Total}
+	if page.Next != nil {
+		output.NextCursor = page.Next.Encode()
+	}
+	return output, nil
+}
+
+// CreateRiskType syncs the catalog from NIC and records the run, whether it
+// succeeds or fails. With SkipIfUnchanged the catalog is only reconciled when
+// the NIC payload differs from the one last applied.
+func (rrt *RiskTypeService) CreateRiskType(ctx context.Context, input SyncInput) (*SyncOutput, error) {
+	return rrt.sync(ctx, input, nil)
+}
 
+// ImportRiskTypes reconciles the catalog with a fixture file exactly as a NIC
+// sync would, recording the run and catalog versions. The run's trigger
+// source is prefixed with "import:" so imports stand out in the sync history.
+func (rrt *RiskTypeService) ImportRiskTypes(ctx context.Context, input ImportInput) (*SyncOutput, error) {
+	format, err := domain.ParseCatalogFileFormat(input.Format)
+	if err != nil {
+		return nil, err
+	}
+	return rrt.sync(ctx, SyncInput{
+		TriggerSource: "import:" + input.TriggerSource,
+		DryRun:        input.DryRun,
+		Force:         input.Force,
+	}, &domain.CatalogFile{Format: format, Data: input.Data})
+}
+
+// ExportRiskTypes renders the active catalog as a fixture file that
+// ImportRiskTypes accepts.
+func (rrt *RiskTypeService) ExportRiskTypes(ctx context.Context, format domain.CatalogFileFormat) ([]byte, error) {
+	return rrt.repo.ExportRiskTypes(ctx, format)
+}
+
+func (rrt *RiskTypeService) sync(ctx context.Context, input SyncInput, source *domain.CatalogFile) (*SyncOutput, error) {
+	opts := domain.SyncOptions{DryRun: input.DryRun, Force: input.Force, Source: source}
+	if input.SkipIfUnchanged && !input.DryRun {
+		last, err := rrt.runs.GetLastSuccessfulSyncRun(ctx, domain.CatalogRiskTypes)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		if last != nil {
+			opts.SkipIfChecksum = last.Checksum
+		}
+	}
+
+	run, err := rrt.runs.StartSyncRun(ctx, domain.SyncRun{
+		Catalog:       domain.CatalogRiskTypes,
+		TriggerSource: input.TriggerSource,
+		DryRun:        input.DryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	opts.RunID = run.ID
+	result, syncErr := rrt.repo.CreateRiskType(ctx, opts)
+	run.Finish(result, syncErr)
+	// Record the outcome even if the caller has gone away.
+	if err := rrt.runs.FinishSyncRun(context.WithoutCancel(ctx), *run); err != nil {
+		log.Error().Err(err).Str("run", run.ID.String()).Msg("Error recording sync run")
+	}
+	if syncErr != nil {
+		return nil, syncErr
+	}
+
+	return &SyncOutput{
+		RunID:         run.ID,
+		DryRun:        result.DryRun,
+		Skipped:       result.Skipped,
+		UpstreamCount: result.UpstreamCount,
+		Added:         result.Summary.Added,
+		Updated:       result.Summary.Updated,
+		Deactivated:   result.Summary.Deactivated,
+		Unchanged:     result.Summary.Unchanged,
+		Changes:       result.Changes,
+		Rejected:      result.Rejected,
+	}, nil
+}
+
+func (rrt *RiskTypeService) GetSyncRuns(ctx context.Context, limit int) ([]SyncRunOutput, error) {
+	if limit <= 0 {
+		limit = defaultSyncRunLimit
+	}
+	results, err := rrt.runs.GetSyncRuns(ctx, domain.CatalogRiskTypes, limit)
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]SyncRunOutput, len(results))
 	for i, r := range results {
-		riskTypes[i] = RiskTypeOutput{
-			ID:           r.ID,
-			Name:         r.Name,
-			RiskTypeCode: r.RiskTypeCode,
-			Description:  r.Description,
-			RiskCategory: r.RiskCategory,
-			RiskTypeId:   r.RiskTypeId,
-			CreatedAt:    r.CreatedAt,
+		runs[i] = SyncRunOutput{
+			ID:            r.ID,
+			TriggerSource: r.TriggerSource,
+			DryRun:        r.DryRun,
+			StartedAt:     r.StartedAt,
+			FinishedAt:    r.FinishedAt,
+			Outcome:       r.Outcome,
+			Error:         r.Error,
+			UpstreamCount: r.UpstreamCount,
+			Added:         r.Summary.Added,
+			Updated:       r.Summary.Updated,
+			Deactivated:   r.Summary.Deactivated,
+			Unchanged:     r.Summary.Unchanged,
+			Changes:       r.Changes,
+			Rejected:      r.Rejected,
+			Checksum:      r.Checksum,
 		}
 	}
-	return riskTypes, nil
+	return runs, nil
 }
-func (rrt *RiskTypeService) CreateRiskType(ctx context.Context) error {
-	err := rrt.repo.CreateRiskType(ctx)
+
+// LastSyncedAt returns when the catalog was last confirmed to match NIC, or
+// nil if it never has been.
+func (rrt *RiskTypeService) LastSyncedAt(ctx context.Context) (*time.Time, error) {
+	last, err := rrt.runs.GetLastSuccessfulSyncRun(ctx, domain.CatalogRiskTypes)
 	if err != nil {
-		return err
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return last.FinishedAt, nil
+}
+
+// GetRiskTypeHistory returns every version of a risk type, oldest first. ref is
+// our UUID or the NIC numeric ID.
+func (rrt *RiskTypeService) GetRiskTypeHistory(ctx context.Context, ref string) ([]RiskTypeVersionOutput, error) {
+	parsed, err := domain.ParseCatalogRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.ID != uuid.Nil {
+		current, err := rrt.repo.GetRiskType(ctx, parsed)
+		if err != nil {
+			return nil, err
+		}
+		parsed.ExternalID = current.RiskTypeId
+	}
+	results, err := rrt.repo.GetRiskTypeHistory(ctx, parsed.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]RiskTypeVersionOutput, len(results))
+	for i, v := range results {
+		versions[i] = RiskTypeVersionOutput{
+			RiskTypeId:   v.RiskTypeId,
+			Name:         v.Name,
+			Description:  v.Description,
+			RiskCategory: v.RiskCategory,
+			RiskTypeCode: v.RiskTypeCode,
+			Active:       v.Active,
+			SyncRunID:    v.SyncRunID,
+			ValidFrom:    v.ValidFrom,
+			ValidTo:      v.ValidTo,
+		}
+	}
+	return versions, nil
+}
+
+// GetRiskType looks up a risk type by our UUID or its NIC numeric ID.
+func (rrt *RiskTypeService) GetRiskType(ctx context.Context, ref string) (*RiskTypeOutput, error) {
+	parsed, err := domain.ParseCatalogRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	result, err := rrt.repo.GetRiskType(ctx, parsed)
+	if err != nil {
+		return nil, err
+	}
+	output := newRiskTypeOutput(result)
+	return &output, nil
+}
+
+func (rrt *RiskTypeService) GetRiskTypeByCode(ctx context.Context, code string) (*RiskTypeOutput, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, domain.ErrNotFound
+	}
+	result, err := rrt.repo.GetRiskTypeByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	output := newRiskTypeOutput(result)
+	return &output, nil
+}
+
+// GetRiskCategories lists the categories seen in synced risk types with the
+// number of active risk types in each.
+func (rrt *RiskTypeService) GetRiskCategories(ctx context.Context) ([]RiskCategoryOutput, error) {
+	results, err := rrt.repo.GetRiskCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	categories := make([]RiskCategoryOutput, len(results))
+	for i, c := range results {
+		categories[i] = RiskCategoryOutput{
+			ID:              c.ID,
+			Name:            c.Name,
+			ActiveRiskTypes: c.ActiveRiskTypes,
+			CreatedAt:       c.CreatedAt,
+		}
+	}
+	return categories, nil
+}
+
+func newRiskTypeOutput(r *domain.RiskType) RiskTypeOutput {
+	return RiskTypeOutput{
+		ID:           r.ID,
+		Name:         r.Name,
+		RiskTypeCode: r.RiskTypeCode,
+		Description:  r.Description,
+		RiskCategory: r.RiskCategory,
+		RiskTypeId:   r.RiskTypeId,
+		Active:       r.Active,
+		CreatedAt:    r.CreatedAt,
+		UpdatedAt:    r.UpdatedAt,
 	}
-	return nil
 }
 
-func NewRiskTypeService(repo RiskTypePort) RiskTypeService {
-	return RiskTypeService{repo: repo}
+func NewRiskTypeService(repo RiskTypePort, runs SyncRunPort) RiskTypeService {
+	return RiskTypeService{repo: repo, runs: runs}
 }
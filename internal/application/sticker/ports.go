@@ -8,4 +8,13 @@ import (
 
 type StickerPort interface {
 	GetStickers(ctx context.Context, cars []string) ([]domain.Sticker, error)
+	PlanStickers(ctx context.Context, cars []string) (*domain.NICPlan, error)
+}
+
+type PlateRiskRulePort interface {
+	GetPlateRiskRules(ctx context.Context) (domain.PlateRiskRules, error)
+}
+
+type RiskTypePort interface {
+	GetRiskType(ctx context.Context, ref domain.CatalogRef) (*domain.RiskType, error)
 }
@@ -5,14 +5,22 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/godsent-code/midtools/internal/domain"
 	"github.com/godsent-code/midtools/pkg"
 )
 
 type StickerService struct {
-	repo StickerPort
+	repo      StickerPort
+	rules     PlateRiskRulePort
+	riskTypes RiskTypePort
 }
 type StickerInput struct {
 	Cars string
+	// RiskTypeID, if set, is checked against the plate rules of every car
+	// before NIC is called. Cars the rules do not allow it for are refused
+	// unless OverridePlateRules is set.
+	RiskTypeID         int
+	OverridePlateRules bool
 }
 
 type StickerOutput struct {
@@ -21,19 +29,23 @@ type StickerOutput struct {
 	StickerNumber string `json:"stickerNumber"`
 	Message       string `json:"message"`
 	CarNumber     string `json:"carNumber"`
+	// SuggestedRiskTypeId is the plate rules' default risk type for the car.
+	SuggestedRiskTypeId *int     `json:"suggestedRiskTypeId,omitempty"`
+	Warnings            []string `json:"warnings,omitempty"`
 }
 
 func (bci *StickerInput) Validate() error {
 	if strings.TrimSpace(bci.Cars) == "" {
 		return errors.New("cars is required")
 	}
+	if bci.RiskTypeID < 0 {
+		return errors.New("riskTypeId must be a NIC risk type id")
+	}
 	return nil
 }
 
 func (ss *StickerService) GetSticker(ctx context.Context, input StickerInput) ([]StickerOutput, error) {
-	parts := strings.FieldsFunc(input.Cars, func(r rune) bool {
-		return r == ',' || r == '\n' || r == '\r' || r == '\t'
-	})
+	parts := pkg.SplitCars(input.Cars)
 
 	if len(parts) == 0 {
 		return nil, errors.New("cars is required")
@@ -57,6 +69,31 @@ func (ss *StickerService) GetSticker(ctx context.Context, input StickerInput) ([
 			correctCars = append(correctCars, parts[i])
 		}
 	}
+	// A plate repeated in any spelling is only generated once.
+	correctCars, _ = pkg.DedupePlates(correctCars)
+
+	rules, err := ss.rules.GetPlateRiskRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	riskType, err := ss.riskType(ctx, input.RiskTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, mismatches := checkPlateRules(correctCars, rules, riskType)
+	if !input.OverridePlateRules {
+		for _, mismatch := range mismatches {
+			sticker := StickerOutput{
+				Status:    false,
+				CarNumber: mismatch.CarNumber,
+				Message:   mismatch.Message,
+			}
+			advise(&sticker, rules, riskType)
+			stickers = append(stickers, sticker)
+		}
+		correctCars = allowed
+	}
 
 	results, err := ss.repo.GetStickers(ctx, correctCars)
 	if err != nil {
@@ -64,18 +101,120 @@ func (ss *StickerService) GetSticker(ctx context.Context, input StickerInput) ([
 	}
 
 	for i, _ := range results {
-		stickers = append(stickers, StickerOutput{
+		sticker := StickerOutput{
 			Status:        results[i].Success,
 			StickerLink:   results[i].StickerLink,
 			CarNumber:     results[i].RegistrationNumber,
 			StickerNumber: results[i].StickerNumber,
 			Message:       results[i].Message,
-		})
+		}
+		advise(&sticker, rules, riskType)
+		stickers = append(stickers, sticker)
 	}
 
 	return stickers, nil
 }
 
-func NewStickerService(r StickerPort) StickerService {
-	return StickerService{repo: r}
+// PlanSticker works out what GetSticker would do with input, down to the
+// NIC calls it would make, without calling NIC.
+func (ss *StickerService) PlanSticker(ctx context.Context, input StickerInput) (*domain.DryRun, error) {
+	parts := pkg.SplitCars(input.Cars)
+	if len(parts) == 0 {
+		return nil, errors.New("cars is required")
+	}
+	invalid := make([]domain.InvalidPlate, 0)
+	correctCars := make([]string, 0)
+	for _, part := range parts {
+		if exists, num := pkg.ValidateGhanaLicensePlate(part); !exists {
+			invalid = append(invalid, domain.InvalidPlate{CarNumber: part, Message: num})
+		} else {
+			correctCars = append(correctCars, part)
+		}
+	}
+	correctCars, duplicates := pkg.DedupePlates(correctCars)
+	rules, err := ss.rules.GetPlateRiskRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	riskType, err := ss.riskType(ctx, input.RiskTypeID)
+	if err != nil {
+		return nil, err
+	}
+	allowed, mismatches := checkPlateRules(correctCars, rules, riskType)
+	if !input.OverridePlateRules {
+		correctCars = allowed
+	}
+	plan, err := ss.repo.PlanStickers(ctx, correctCars)
+	if err != nil {
+		return nil, err
+	}
+	dryRun := domain.NewDryRun(len(parts), invalid, duplicates, plan)
+	dryRun.PlateRuleWarnings = mismatches
+	return dryRun, nil
+}
+
+// CheckRiskType refuses a risk type id NIC's catalog does not know with
+// domain.ErrUnknownRiskType, so a request naming one can be turned away before
+// any quota is counted. Zero names no risk type and is accepted.
+func (ss *StickerService) CheckRiskType(ctx context.Context, id int) error {
+	_, err := ss.riskType(ctx, id)
+	return err
+}
+
+// riskType looks up the risk type a request names, if it names one.
+func (ss *StickerService) riskType(ctx context.Context, id int) (*domain.RiskType, error) {
+	if id <= 0 {
+		return nil, nil
+	}
+	riskType, err := ss.riskTypes.GetRiskType(ctx, domain.CatalogRef{ExternalID: id})
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrUnknownRiskType
+		}
+		return nil, err
+	}
+	return riskType, nil
+}
+
+// checkPlateRules splits cars into those the plate rules allow riskType for
+// and those they do not, with the rules' warning. Without a risk type every
+// car is allowed.
+func checkPlateRules(cars []string, rules domain.PlateRiskRules, riskType *domain.RiskType) ([]string, []domain.InvalidPlate) {
+	mismatches := make([]domain.InvalidPlate, 0)
+	if riskType == nil {
+		return cars, mismatches
+	}
+	allowed := make([]string, 0, len(cars))
+	for _, car := range cars {
+		plate, valid, _ := pkg.ParseGhanaLicensePlate(car)
+		if valid {
+			if warning := rules.For(string(plate.Kind), plate.Code).Warning(string(plate.Kind), riskType); warning != "" {
+				mismatches = append(mismatches, domain.InvalidPlate{CarNumber: car, Message: warning})
+				continue
+			}
+		}
+		allowed = append(allowed, car)
+	}
+	return allowed, mismatches
+}
+
+// advise adds the risk type suggested for the car's plate and, when the
+// caller named a risk type the plate rules do not allow, a warning.
+func advise(sticker *StickerOutput, rules domain.PlateRiskRules, riskType *domain.RiskType) {
+	plate, valid, _ := pkg.ParseGhanaLicensePlate(sticker.CarNumber)
+	if !valid {
+		return
+	}
+	applicable := rules.For(string(plate.Kind), plate.Code)
+	sticker.SuggestedRiskTypeId = applicable.DefaultRiskTypeId()
+	if riskType == nil {
+		return
+	}
+	if warning := applicable.Warning(string(plate.Kind), riskType); warning != "" {
+		sticker.Warnings = append(sticker.Warnings, warning)
+	}
+}
+
+func NewStickerService(r StickerPort, rules PlateRiskRulePort, riskTypes RiskTypePort) StickerService {
+	return StickerService{repo: r, rules: rules, riskTypes: riskTypes}
 }
@@ -0,0 +1,16 @@
+package sticker_registry
+
+import (
+	"context"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+)
+
+type StickerRegistryPort interface {
+	RecordStickers(ctx context.Context, clientID, tenantID *uuid.UUID, branch string, stickers []domain.IssuedSticker) error
+	GetSticker(ctx context.Context, id uuid.UUID) (*domain.StickerRecord, error)
+	GetStickers(ctx context.Context, filter domain.StickerFilter) ([]*domain.StickerRecord, error)
+	SetStickerStatus(ctx context.Context, id uuid.UUID, status domain.StickerStatus, note string) (*domain.StickerRecord, error)
+	GetStickerReconciliation(ctx context.Context, allTenants bool, tenantID *uuid.UUID) (*domain.StickerReconciliation, error)
+}
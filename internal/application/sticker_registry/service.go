@@ -0,0 +1,162 @@
+package sticker_registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultStickerLimit = 50
+	maxStickerLimit     = 500
+	maxNoteLength       = 1000
+)
+
+var ErrNoteRequired = errors.New("a note is required to mark a sticker voided or lost")
+
+// StickerRegistryService keeps the registry of stickers generated through
+// the API. Clients see the stickers of their own tenant; admins see all.
+type StickerRegistryService struct {
+	repo StickerRegistryPort
+}
+
+// RegistryRecord is the outcome of a sticker generation to be registered.
+// ClientID and TenantID are those of the client the stickers were issued
+// for, and are nil for requests made without a client or tenant.
+type RegistryRecord struct {
+	ClientID *uuid.UUID
+	TenantID *uuid.UUID
+	Branch   string
+	Stickers []domain.IssuedSticker
+}
+
+// SearchInput filters the registry. Plate matches in any spelling.
+type SearchInput struct {
+	Plate         string
+	StickerNumber string
+	Status        string
+	Branch        string
+	Limit         int
+}
+
+type StatusInput struct {
+	Status string
+	Note   string
+}
+
+// ValidateBranch checks the branch a request names for its stickers.
+func ValidateBranch(branch string) error {
+	if len(branch) > domain.MaxBranchLength {
+		return fmt.Errorf("branch must be at most %d characters", domain.MaxBranchLength)
+	}
+	return nil
+}
+
+func (si *SearchInput) Validate() error {
+	if si.Status != "" && !slices.Contains(domain.StickerStatuses, domain.StickerStatus(si.Status)) {
+		return errors.New("status must be one of active, voided, lost")
+	}
+	if si.Limit > maxStickerLimit {
+		return fmt.Errorf("limit must be at most %d", maxStickerLimit)
+	}
+	return nil
+}
+
+func (si *StatusInput) Validate() error {
+	if !slices.Contains(domain.StickerStatuses, domain.StickerStatus(si.Status)) {
+		return errors.New("status must be one of active, voided, lost")
+	}
+	if len(si.Note) > maxNoteLength {
+		return fmt.Errorf("note must be at most %d characters", maxNoteLength)
+	}
+	return nil
+}
+
+// Record registers the stickers NIC generated. Stickers without a number
+// are skipped. The stickers have already been issued by the time they are
+// recorded, so a failure to record them is logged rather than returned.
+func (ss *StickerRegistryService) Record(ctx context.Context, record RegistryRecord) {
+	stickers := make([]domain.IssuedSticker, 0, len(record.Stickers))
+	for _, sticker := range record.Stickers {
+		if strings.TrimSpace(sticker.StickerNumber) != "" {
+			stickers = append(stickers, sticker)
+		}
+	}
+	if len(stickers) == 0 {
+		return
+	}
+	if err := ss.repo.RecordStickers(context.WithoutCancel(ctx), record.ClientID, record.TenantID, record.Branch, stickers); err != nil {
+		numbers := make([]string, len(stickers))
+		for i, sticker := range stickers {
+			numbers[i] = sticker.Plate + "=" + sticker.StickerNumber
+		}
+		log.Error().Err(err).Str("branch", record.Branch).Strs("stickers", numbers).Msg("Error recording stickers")
+	}
+}
+
+func (ss *StickerRegistryService) Search(ctx context.Context, client *domain.APIClient, input SearchInput) ([]*domain.StickerRecord, error) {
+	filter := domain.StickerFilter{
+		AllTenants:    client.Can(domain.RoleAdmin),
+		TenantID:      client.TenantID,
+		StickerNumber: strings.TrimSpace(input.StickerNumber),
+		Status:        domain.StickerStatus(input.Status),
+		Branch:        input.Branch,
+		Limit:         input.Limit,
+	}
+	if input.Plate != "" {
+		filter.Plate = pkg.CanonicalPlate(input.Plate)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultStickerLimit
+	}
+	return ss.repo.GetStickers(ctx, filter)
+}
+
+// Get returns a registry entry. Entries of other tenants are not found,
+// except by admins.
+func (ss *StickerRegistryService) Get(ctx context.Context, client *domain.APIClient, id uuid.UUID) (*domain.StickerRecord, error) {
+	record, err := ss.repo.GetSticker(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !client.Can(domain.RoleAdmin) && !sameTenant(client.TenantID, record.TenantID) {
+		return nil, domain.ErrNotFound
+	}
+	return record, nil
+}
+
+// SetStatus marks a sticker active, voided or lost. Voiding or losing one
+// needs a note saying why.
+func (ss *StickerRegistryService) SetStatus(ctx context.Context, client *domain.APIClient, id uuid.UUID, input StatusInput) (*domain.StickerRecord, error) {
+	if domain.StickerStatus(input.Status) != domain.StickerActive && strings.TrimSpace(input.Note) == "" {
+		return nil, ErrNoteRequired
+	}
+	if _, err := ss.Get(ctx, client, id); err != nil {
+		return nil, err
+	}
+	return ss.repo.SetStickerStatus(ctx, id, domain.StickerStatus(input.Status), input.Note)
+}
+
+// Reconcile reports sticker numbers registered against more than one plate
+// and plates holding more than one active sticker.
+func (ss *StickerRegistryService) Reconcile(ctx context.Context, client *domain.APIClient) (*domain.StickerReconciliation, error) {
+	return ss.repo.GetStickerReconciliation(ctx, client.Can(domain.RoleAdmin), client.TenantID)
+}
+
+func sameTenant(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func NewStickerRegistryService(repo StickerRegistryPort) StickerRegistryService {
+	return StickerRegistryService{repo: repo}
+}
@@ -0,0 +1,15 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+)
+
+type TenantPort interface {
+	CreateTenant(ctx context.Context, tenant domain.Tenant) (*domain.Tenant, error)
+	GetTenants(ctx context.Context) ([]*domain.Tenant, error)
+	GetTenant(ctx context.Context, id uuid.UUID) (*domain.Tenant, error)
+	UpdateTenant(ctx context.Context, tenant domain.Tenant) (*domain.Tenant, error)
+}
@@ -0,0 +1,141 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/google/uuid"
+)
+
+var ErrNICAPIKeyRequired = errors.New("nicApiKey is required")
+
+type TenantService struct {
+	repo TenantPort
+}
+
+// TenantInput creates or updates a tenant. Zero rate limit fields take the
+// defaults on creation and keep the current values on update, as does an
+// empty NICAPIKey or NICEnvironment.
+type TenantInput struct {
+	Name              string
+	NICEnvironment    string
+	NICAPIKey         string
+	RequestIntervalMs int
+	Burst             int
+	Workers           int
+}
+
+type TenantOutput struct {
+	ID             uuid.UUID             `json:"id"`
+	Name           string                `json:"name"`
+	NICEnvironment domain.NICEnvironment `json:"nicEnvironment"`
+	NICRateLimit   domain.NICRateLimit   `json:"nicRateLimit"`
+	CreatedAt      time.Time             `json:"createdAt"`
+	UpdatedAt      time.Time             `json:"updatedAt"`
+}
+
+func (ti *TenantInput) Validate() error {
+	if strings.TrimSpace(ti.Name) == "" {
+		return errors.New("name is required")
+	}
+	if ti.NICEnvironment != "" && !slices.Contains(domain.NICEnvironments, domain.NICEnvironment(ti.NICEnvironment)) {
+		return fmt.Errorf("nicEnvironment must be one of %v", domain.NICEnvironments)
+	}
+	if ti.RequestIntervalMs < 0 || ti.Burst < 0 || ti.Workers < 0 {
+		return errors.New("requestIntervalMs, burst and workers must not be negative")
+	}
+	return nil
+}
+
+func (ts *TenantService) CreateTenant(ctx context.Context, input TenantInput) (*TenantOutput, error) {
+	if strings.TrimSpace(input.NICAPIKey) == "" {
+		return nil, ErrNICAPIKeyRequired
+	}
+	tenant := domain.Tenant{
+		NICEnvironment: domain.NICProduction,
+		NICRateLimit:   domain.DefaultNICRateLimit,
+	}
+	applyTenantInput(&tenant, input)
+	result, err := ts.repo.CreateTenant(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	output := toTenantOutput(result)
+	return &output, nil
+}
+
+func (ts *TenantService) GetTenants(ctx context.Context) ([]TenantOutput, error) {
+	results, err := ts.repo.GetTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenants := make([]TenantOutput, len(results))
+	for i, result := range results {
+		tenants[i] = toTenantOutput(result)
+	}
+	return tenants, nil
+}
+
+func (ts *TenantService) GetTenant(ctx context.Context, id uuid.UUID) (*TenantOutput, error) {
+	result, err := ts.repo.GetTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	output := toTenantOutput(result)
+	return &output, nil
+}
+
+// UpdateTenant changes a tenant's name, NIC account or limits. Clients of the
+// tenant pick up the change on their next request.
+func (ts *TenantService) UpdateTenant(ctx context.Context, id uuid.UUID, input TenantInput) (*TenantOutput, error) {
+	current, err := ts.repo.GetTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	applyTenantInput(current, input)
+	result, err := ts.repo.UpdateTenant(ctx, *current)
+	if err != nil {
+		return nil, err
+	}
+	output := toTenantOutput(result)
+	return &output, nil
+}
+
+func applyTenantInput(tenant *domain.Tenant, input TenantInput) {
+	tenant.Name = strings.TrimSpace(input.Name)
+	if input.NICEnvironment != "" {
+		tenant.NICEnvironment = domain.NICEnvironment(input.NICEnvironment)
+	}
+	if key := strings.TrimSpace(input.NICAPIKey); key != "" {
+		tenant.NICAPIKey = key
+	}
+	if input.RequestIntervalMs > 0 {
+		tenant.NICRateLimit.RequestIntervalMs = input.RequestIntervalMs
+	}
+	if input.Burst > 0 {
+		tenant.NICRateLimit.Burst = input.Burst
+	}
+	if input.Workers > 0 {
+		tenant.NICRateLimit.Workers = input.Workers
+	}
+}
+
+func toTenantOutput(t *domain.Tenant) TenantOutput {
+	return TenantOutput{
+		ID:             t.ID,
+		Name:           t.Name,
+		NICEnvironment: t.NICEnvironment,
+		NICRateLimit:   t.NICRateLimit,
+		CreatedAt:      t.CreatedAt,
+		UpdatedAt:      t.UpdatedAt,
+	}
+}
+
+func NewTenantService(repo TenantPort) TenantService {
+	return TenantService{repo: repo}
+}
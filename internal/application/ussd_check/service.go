@@ -29,9 +29,7 @@ func (bci *USSDCheckInput) Validate() error {
 }
 
 func (ss *USSDCheckService) GetUSSDCheck(ctx context.Context, input USSDCheckInput) ([]USSDCheckOutput, error) {
-	parts := strings.FieldsFunc(input.Cars, func(r rune) bool {
-		return r == ',' || r == '\n' || r == '\r' || r == '\t'
-	})
+	parts := pkg.SplitCars(input.Cars)
 
 	if len(parts) == 0 {
 		return nil, errors.New("cars is required")
@@ -0,0 +1,19 @@
+package vehicle_profile
+
+import (
+	"context"
+
+	"github.com/godsent-code/midtools/internal/domain"
+)
+
+type PolicyVerificationPort interface {
+	GetPolicyVerification(ctx context.Context, cars []string) ([]domain.PolicyVerification, error)
+}
+
+type USSDCheckPort interface {
+	GetUSSDCheck(ctx context.Context, cars []string) ([]domain.USSDChecker, error)
+}
+
+type StickerRegistryPort interface {
+	GetStickers(ctx context.Context, filter domain.StickerFilter) ([]*domain.StickerRecord, error)
+}
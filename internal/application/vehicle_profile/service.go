@@ -0,0 +1,223 @@
+package vehicle_profile
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godsent-code/midtools/internal/domain"
+	"github.com/godsent-code/midtools/pkg"
+)
+
+const defaultExpiringDays = 30
+
+type VehicleProfileService struct {
+	policyRepo   PolicyVerificationPort
+	ussdRepo     USSDCheckPort
+	registryRepo StickerRegistryPort
+}
+
+type VehicleProfileInput struct {
+	Cars         string
+	ExpiringDays int
+}
+
+type PolicyProfile struct {
+	Status      bool       `json:"statusCode"`
+	ProductName string     `json:"productName"`
+	StartDate   *time.Time `json:"startDate"`
+	EndDate     *time.Time `json:"endDate"`
+	Message     string     `json:"message"`
+}
+
+type USSDProfile struct {
+	Status  bool   `json:"statusCode"`
+	Message string `json:"message"`
+}
+
+// StickerProfile is the newest sticker the registry holds for the plate.
+type StickerProfile struct {
+	StickerNumber string               `json:"stickerNumber"`
+	StickerLink   string               `json:"stickerLink"`
+	Status        domain.StickerStatus `json:"status"`
+	Branch        string               `json:"branch"`
+	IssuedAt      time.Time            `json:"issuedAt"`
+}
+
+type VehicleProfileOutput struct {
+	CarNumber       string                 `json:"carNumber"`
+	InsuranceStatus domain.InsuranceStatus `json:"insuranceStatus"`
+	Message         string                 `json:"message"`
+	Policy          *PolicyProfile         `json:"policy"`
+	USSD            *USSDProfile           `json:"ussd"`
+	Sticker         *StickerProfile        `json:"sticker"`
+}
+
+func (vpi *VehicleProfileInput) Validate() error {
+	if strings.TrimSpace(vpi.Cars) == "" {
+		return errors.New("cars is required")
+	}
+	if vpi.ExpiringDays < 0 {
+		return errors.New("expiringDays must not be negative")
+	}
+	return nil
+}
+
+// GetVehicleProfiles builds a profile per car. Sticker information comes
+// from the registry, limited to the stickers client may see there.
+func (vps *VehicleProfileService) GetVehicleProfiles(ctx context.Context, client *domain.APIClient, input VehicleProfileInput) ([]VehicleProfileOutput, error) {
+	parts := pkg.SplitCars(input.Cars)
+
+	if len(parts) == 0 {
+		return nil, errors.New("cars is required")
+	}
+
+	expiringDays := input.ExpiringDays
+	if expiringDays == 0 {
+		expiringDays = defaultExpiringDays
+	}
+
+	profiles := make([]VehicleProfileOutput, 0, len(parts))
+	correctCars := make([]string, 0)
+
+	for i := range parts {
+		car := strings.TrimSpace(parts[i])
+		exists, num := pkg.ValidateGhanaLicensePlate(car)
+		if !exists {
+			profiles = append(profiles, VehicleProfileOutput{
+				CarNumber:       car,
+				InsuranceStatus: domain.InsuranceStatusUnknown,
+				Message:         num,
+			})
+		} else {
+			correctCars = append(correctCars, car)
+		}
+	}
+
+	if len(correctCars) == 0 {
+		return profiles, nil
+	}
+
+	// Only read-only lookups belong here: the sticker and brown card
+	// endpoints issue documents, so they are never called for a profile.
+	// Stickers are read from the registry instead; brown cards have none.
+	var (
+		wg       sync.WaitGroup
+		policies []domain.PolicyVerification
+		ussds    []domain.USSDChecker
+		stickers map[string]*domain.StickerRecord
+		errs     [3]error
+	)
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		policies, errs[0] = vps.policyRepo.GetPolicyVerification(ctx, correctCars)
+	}()
+	go func() {
+		defer wg.Done()
+		ussds, errs[1] = vps.ussdRepo.GetUSSDCheck(ctx, correctCars)
+	}()
+	go func() {
+		defer wg.Done()
+		stickers, errs[2] = vps.latestStickers(ctx, client, correctCars)
+	}()
+	wg.Wait()
+
+	if err := errors.Join(errs[:]...); err != nil {
+		return nil, err
+	}
+
+	byCar := make(map[string]*VehicleProfileOutput, len(correctCars))
+	ordered := make([]*VehicleProfileOutput, 0, len(correctCars))
+	for _, car := range correctCars {
+		if _, ok := byCar[car]; ok {
+			continue
+		}
+		p := &VehicleProfileOutput{CarNumber: car}
+		byCar[car] = p
+		ordered = append(ordered, p)
+	}
+
+	now := time.Now()
+	for _, r := range policies {
+		if p, ok := byCar[r.RegistrationNumber]; ok {
+			p.InsuranceStatus, p.Message = r.InsuranceStatus(now, expiringDays)
+			p.Policy = &PolicyProfile{
+				Status:      r.Success,
+				ProductName: r.ProductName,
+				StartDate:   optionalTime(r.StartDate),
+				EndDate:     optionalTime(r.EndDate),
+				Message:     r.Message,
+			}
+		}
+	}
+	for _, r := range ussds {
+		if p, ok := byCar[r.RegistrationNumber]; ok {
+			p.USSD = &USSDProfile{Status: r.Success, Message: r.Message}
+		}
+	}
+	for _, p := range ordered {
+		if r, ok := stickers[pkg.CanonicalPlate(p.CarNumber)]; ok {
+			p.Sticker = &StickerProfile{
+				StickerNumber: r.StickerNumber,
+				StickerLink:   r.StickerLink,
+				Status:        r.Status,
+				Branch:        r.Branch,
+				IssuedAt:      r.IssuedAt,
+			}
+		}
+		if p.Policy == nil {
+			p.InsuranceStatus, p.Message = domain.InsuranceStatusUnknown, "No policy found for vehicle"
+		}
+		profiles = append(profiles, *p)
+	}
+
+	return profiles, nil
+}
+
+// latestStickers returns the newest registry entry of each car that has one,
+// by canonical plate. Only entries of the client's tenant are read, or of
+// every tenant for admins.
+func (vps *VehicleProfileService) latestStickers(ctx context.Context, client *domain.APIClient, cars []string) (map[string]*domain.StickerRecord, error) {
+	filter := domain.StickerFilter{Limit: 1}
+	if client != nil {
+		filter.AllTenants, filter.TenantID = client.Can(domain.RoleAdmin), client.TenantID
+	}
+	latest := make(map[string]*domain.StickerRecord, len(cars))
+	for _, car := range cars {
+		filter.Plate = pkg.CanonicalPlate(car)
+		if _, ok := latest[filter.Plate]; ok {
+			continue
+		}
+		records, err := vps.registryRepo.GetStickers(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) > 0 {
+			latest[filter.Plate] = records[0]
+		}
+	}
+	return latest, nil
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func NewVehicleProfileService(
+	policyRepo PolicyVerificationPort,
+	ussdRepo USSDCheckPort,
+	registryRepo StickerRegistryPort,
+) VehicleProfileService {
+	return VehicleProfileService{
+		policyRepo:   policyRepo,
+		ussdRepo:     ussdRepo,
+		registryRepo: registryRepo,
+	}
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role is what an API client is allowed to do. Routes name the role they
+// need; see Role.Grants for how roles include one another.
+type Role string
+
+const (
+	// RoleViewer covers lookups and checks that neither issue documents nor
+	// change shared data.
+	RoleViewer Role = "viewer"
+	// RoleIssuer may generate stickers and brown cards, quote, and manage
+	// fleets.
+	RoleIssuer Role = "issuer"
+	// RoleCatalogAdmin may sync, import and edit the product and risk type
+	// catalogs, rate tables and plate rules.
+	RoleCatalogAdmin Role = "catalog-admin"
+	// RoleApprover may approve or reject bulk issuance requests made by
+	// other clients of its tenant.
+	RoleApprover Role = "approver"
+	// RoleAdmin may do everything, including managing API clients.
+	RoleAdmin Role = "admin"
+)
+
+var Roles = []Role{RoleViewer, RoleIssuer, RoleCatalogAdmin, RoleApprover, RoleAdmin}
+
+// Grants reports whether holding r is enough for a route that needs
+// required. Admin grants every role, and every role grants viewer.
+func (r Role) Grants(required Role) bool {
+	return r == required || r == RoleAdmin || (required == RoleViewer && slices.Contains(Roles, r))
+}
+
+// AccessDenial records a request refused because the client lacked the
+// role the route needs.
+type AccessDenial struct {
+	ID           uuid.UUID `json:"id"`
+	ClientID     uuid.UUID `json:"clientId"`
+	ClientName   string    `json:"clientName"`
+	Method       string    `json:"method"`
+	Path         string    `json:"path"`
+	RequiredRole Role      `json:"requiredRole"`
+	Roles        []Role    `json:"roles"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// apiKeyPrefix marks our keys so they are recognisable in config files and
+// secret scanners.
+const apiKeyPrefix = "mt"
+
+// APIClient is an application allowed to call the API. Its key is never
+// stored, only KeyHash; KeyPrefix is the non-secret part of the key that
+// identifies the client. Clients without a TenantID call NIC with the
+// configured account; Tenant is only loaded when the client authenticates.
+type APIClient struct {
+	ID         uuid.UUID    `json:"id"`
+	Name       string       `json:"name"`
+	KeyPrefix  string       `json:"keyPrefix"`
+	KeyHash    string       `json:"-"`
+	Roles      []Role       `json:"roles"`
+	TenantID   *uuid.UUID   `json:"tenantId"`
+	Tenant     *Tenant      `json:"-"`
+	Limits     ClientLimits `json:"limits"`
+	CreatedAt  time.Time    `json:"createdAt"`
+	LastUsedAt *time.Time   `json:"lastUsedAt"`
+	RotatedAt  *time.Time   `json:"rotatedAt"`
+	RevokedAt  *time.Time   `json:"revokedAt"`
+}
+
+func (c *APIClient) Revoked() bool {
+	return c.RevokedAt != nil
+}
+
+// Can reports whether any of the client's roles grants required.
+func (c *APIClient) Can(required Role) bool {
+	return slices.ContainsFunc(c.Roles, func(r Role) bool { return r.Grants(required) })
+}
+
+// NewAPIKey generates a key of the form mt_<prefix>_<secret>. The caller
+// shows the key once and keeps only its prefix and hash.
+func NewAPIKey() (key, prefix string, err error) {
+	id := make([]byte, 6)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	prefix = hex.EncodeToString(id)
+	return apiKeyPrefix + "_" + prefix + "_" + base64.RawURLEncoding.EncodeToString(secret), prefix, nil
+}
+
+// APIKeyPrefix extracts the prefix of a key made by NewAPIKey.
+func APIKeyPrefix(key string) (string, bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyPrefix || parts[1] == "" || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// HashAPIKey returns the hash stored for a key. Keys carry 256 random bits,
+// so a fast hash is enough; there is nothing to brute force.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction names what an audit entry records.
+type AuditAction string
+
+const (
+	AuditStickerIssue       AuditAction = "sticker.issue"
+	AuditBrownCardIssue     AuditAction = "brown_card.issue"
+	AuditAPIClientCreate    AuditAction = "api_client.create"
+	AuditAPIClientRotate    AuditAction = "api_client.rotate"
+	AuditAPIClientRevoke    AuditAction = "api_client.revoke"
+	AuditAPIClientSetTenant AuditAction = "api_client.set_tenant"
+	AuditAPIClientSetLimits AuditAction = "api_client.set_limits"
+	AuditAPIClientSetRoles  AuditAction = "api_client.set_roles"
+	AuditTenantCreate       AuditAction = "tenant.create"
+	AuditTenantUpdate       AuditAction = "tenant.update"
+	AuditServicePriceSet    AuditAction = "service_price.set"
+	AuditIssuanceSubmit     AuditAction = "issuance_request.submit"
+	AuditIssuanceApprove    AuditAction = "issuance_request.approve"
+	AuditIssuanceReject     AuditAction = "issuance_request.reject"
+	AuditStickerSetStatus   AuditAction = "sticker.set_status"
+	AuditProductSync        AuditAction = "product.sync"
+	AuditProductImport      AuditAction = "product.import"
+	AuditProductMapRisk     AuditAction = "product.add_risk_type"
+	AuditProductUnmapRisk   AuditAction = "product.remove_risk_type"
+	AuditRiskTypeSync       AuditAction = "risk_type.sync"
+	AuditRiskTypeImport     AuditAction = "risk_type.import"
+	AuditRateTableCreate    AuditAction = "rate_table.create"
+	AuditRateTableUpdate    AuditAction = "rate_table.update"
+	AuditRateTableDelete    AuditAction = "rate_table.delete"
+	AuditPlateRuleCreate    AuditAction = "plate_rule.create"
+	AuditPlateRuleDelete    AuditAction = "plate_rule.delete"
+)
+
+// AuditOutcome is how an audited action ended. Issuances where NIC refused
+// some plates but not all are partial.
+type AuditOutcome string
+
+const (
+	AuditSucceeded AuditOutcome = "succeeded"
+	AuditPartial   AuditOutcome = "partial"
+	AuditFailed    AuditOutcome = "failed"
+)
+
+// AuditGenesisHash is the previous hash of the first entry in the log.
+var AuditGenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// AuditEntry is one entry in the append-only audit log. Actor is the name of
+// the API client, or "cli" for the command line tools. Detail holds the NIC
+// response for issuances and the resulting record for admin actions.
+type AuditEntry struct {
+	Seq        int64           `json:"seq"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	ClientID   *uuid.UUID      `json:"clientId"`
+	Actor      string          `json:"actor"`
+	Action     AuditAction     `json:"action"`
+	Target     string          `json:"target,omitempty"`
+	Plates     []string        `json:"plates"`
+	Outcome    AuditOutcome    `json:"outcome"`
+	Detail     json.RawMessage `json:"detail"`
+	PrevHash   string          `json:"prevHash"`
+	Hash       string          `json:"hash"`
+}
+
+// ComputeHash returns the SHA-256 of the entry's contents and PrevHash, hex
+// encoded. Every field is written with its length first, so no two entries
+// hash the same input. OccurredAt is hashed at microsecond precision, which
+// is what Postgres keeps.
+func (e AuditEntry) ComputeHash() string {
+	h := sha256.New()
+	writeField(h, strconv.FormatInt(e.Seq, 10))
+	writeField(h, e.OccurredAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano))
+	clientID := ""
+	if e.ClientID != nil {
+		clientID = e.ClientID.String()
+	}
+	writeField(h, clientID)
+	writeField(h, e.Actor)
+	writeField(h, string(e.Action))
+	writeField(h, e.Target)
+	writeField(h, strconv.Itoa(len(e.Plates)))
+	for _, plate := range e.Plates {
+		writeField(h, plate)
+	}
+	writeField(h, string(e.Outcome))
+	writeField(h, string(e.Detail))
+	writeField(h, e.PrevHash)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeField(h hash.Hash, value string) {
+	fmt.Fprintf(h, "%d:%s;", len(value), value)
+}
+
+// AuditOutcomeOf classifies an issuance by how many of its plates were
+// issued.
+func AuditOutcomeOf(issued, plates int) AuditOutcome {
+	switch {
+	case plates > 0 && issued == plates:
+		return AuditSucceeded
+	case issued > 0:
+		return AuditPartial
+	default:
+		return AuditFailed
+	}
+}
+
+// AuditVerification is the result of checking the audit log's hash chain.
+// When the chain is broken, BrokenAt is the first entry that does not match
+// and Problem says how. LastSeq and LastHash are those of the last entry that
+// verified.
+type AuditVerification struct {
+	Entries  int64  `json:"entries"`
+	Valid    bool   `json:"valid"`
+	BrokenAt int64  `json:"brokenAt,omitempty"`
+	Problem  string `json:"problem,omitempty"`
+	LastSeq  int64  `json:"lastSeq"`
+	LastHash string `json:"lastHash"`
+}
+
+// AuditCheckpoint is the last entry of the audit log as of an earlier
+// verification, kept outside the database. The chain cannot show entries cut
+// off its end; a log that no longer reaches the checkpoint, or holds another
+// entry at its seq, can.
+type AuditCheckpoint struct {
+	Seq        int64     `json:"seq"`
+	Hash       string    `json:"hash"`
+	VerifiedAt time.Time `json:"verifiedAt"`
+}
+
+// AuditFilter selects audit entries, newest first. Zero fields match every
+// entry; BeforeSeq pages back from an earlier page's last entry.
+type AuditFilter struct {
+	ClientID  uuid.UUID
+	Action    AuditAction
+	Plate     string
+	From      time.Time
+	To        time.Time
+	BeforeSeq int64
+	Limit     int
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestAuditEntryComputeHash(t *testing.T) {
+	clientID := uuid.MustParse("6f1c2b4e-8d3a-4b7e-9c1f-2a5d7e9b0c13")
+	otherID := uuid.MustParse("0b9e7d5a-2f1c-4e9c-b7a4-3d8e1b6c2f70")
+	base := AuditEntry{
+		Seq:        7,
+		OccurredAt: time.Date(2026, 3, 1, 9, 30, 0, 123456000, time.UTC),
+		ClientID:   &clientID,
+		Actor:      "fleet-app",
+		Action:     AuditStickerIssue,
+		Target:     "",
+		Plates:     []string{"GR123421", "AS45620"},
+		Outcome:    AuditPartial,
+		Detail:     json.RawMessage(`{"issued":1}`),
+		PrevHash:   AuditGenesisHash,
+	}
+	want := base.ComputeHash()
+
+	tests := []struct {
+		name   string
+		change func(e *AuditEntry)
+		same   bool
+	}{
+		{name: "unchanged", change: func(*AuditEntry) {}, same: true},
+		{name: "stored hash is not hashed", change: func(e *AuditEntry) { e.Hash = "anything" }, same: true},
+		{name: "same instant in another zone", change: func(e *AuditEntry) { e.OccurredAt = e.OccurredAt.In(time.FixedZone("east", 3600)) }, same: true},
+		{name: "nanoseconds Postgres drops", change: func(e *AuditEntry) { e.OccurredAt = e.OccurredAt.Add(999 * time.Nanosecond) }, same: true},
+		{name: "seq", change: func(e *AuditEntry) { e.Seq = 8 }},
+		{name: "occurred at", change: func(e *AuditEntry) { e.OccurredAt = e.OccurredAt.Add(time.Microsecond) }},
+		{name: "client", change: func(e *AuditEntry) { e.ClientID = &otherID }},
+		{name: "no client", change: func(e *AuditEntry) { e.ClientID = nil }},
+		{name: "actor", change: func(e *AuditEntry) { e.Actor = "cli" }},
+		{name: "action", change: func(e *AuditEntry) { e.Action = AuditProductSync }},
+		{name: "target", change: func(e *AuditEntry) { e.Target = "GR123421" }},
+		{name: "plate removed", change: func(e *AuditEntry) { e.Plates = e.Plates[:1] }},
+		{name: "plates reordered", change: func(e *AuditEntry) { e.Plates = []string{"AS45620", "GR123421"} }},
+		{name: "plates joined", change: func(e *AuditEntry) { e.Plates = []string{"GR123421AS45620"} }},
+		{name: "outcome", change: func(e *AuditEntry) { e.Outcome = AuditSucceeded }},
+		{name: "detail", change: func(e *AuditEntry) { e.Detail = json.RawMessage(`{"issued":2}`) }},
+		{name: "previous hash", change: func(e *AuditEntry) { e.PrevHash = want }},
+		{name: "text moved between actor and action", change: func(e *AuditEntry) {
+			e.Actor, e.Action = "fleet-appsticker", ".issue"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := base
+			entry.Plates = append([]string(nil), base.Plates...)
+			tt.change(&entry)
+			got := entry.ComputeHash()
+			if len(got) != len(AuditGenesisHash) {
+				t.Fatalf("ComputeHash() = %q, want %d hex characters", got, len(AuditGenesisHash))
+			}
+			if (got == want) != tt.same {
+				t.Errorf("ComputeHash() = %s, base %s; want same %t", got, want, tt.same)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CatalogFileFormat is the encoding of a catalog fixture file.
+type CatalogFileFormat string
+
+const (
+	CatalogFileJSON CatalogFileFormat = "json"
+	CatalogFileCSV  CatalogFileFormat = "csv"
+)
+
+var ErrInvalidCatalogFile = errors.New("invalid catalog file")
+
+// CatalogFile holds a product or risk type catalog in the shape NIC returns
+// it: either the NIC JSON response itself, or CSV with one column per NIC
+// field. Environments without NIC access seed their catalogs from these.
+type CatalogFile struct {
+	Format CatalogFileFormat
+	Data   []byte
+}
+
+// ParseCatalogFileFormat reads a format name, defaulting to JSON.
+func ParseCatalogFileFormat(value string) (CatalogFileFormat, error) {
+	switch format := CatalogFileFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return CatalogFileJSON, nil
+	case CatalogFileJSON, CatalogFileCSV:
+		return format, nil
+	default:
+		return "", fmt.Errorf("format must be %s or %s", CatalogFileJSON, CatalogFileCSV)
+	}
+}
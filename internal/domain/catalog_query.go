@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type CatalogSort string
+
+const (
+	CatalogSortExternalID CatalogSort = "externalId"
+	CatalogSortName       CatalogSort = "name"
+	CatalogSortCode       CatalogSort = "code"
+	CatalogSortUpdatedAt  CatalogSort = "updatedAt"
+	CatalogSortRelevance  CatalogSort = "relevance"
+)
+
+func (cs CatalogSort) Valid() bool {
+	switch cs {
+	case CatalogSortExternalID, CatalogSortName, CatalogSortCode, CatalogSortUpdatedAt, CatalogSortRelevance:
+		return true
+	}
+	return false
+}
+
+// CatalogQuery filters, sorts and pages a product or risk type listing.
+// Empty filters match everything and a zero Limit returns every match.
+type CatalogQuery struct {
+	Search     string
+	Code       string
+	Category   string
+	Active     *bool
+	Sort       CatalogSort
+	Descending bool
+	Limit      int
+	After      *CatalogCursor
+}
+
+// CatalogCursor marks the last row of a page by its sort key and ID, so the
+// next page starts strictly after it whatever was inserted in between.
+type CatalogCursor struct {
+	Key string    `json:"k"`
+	ID  uuid.UUID `json:"id"`
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+func (cc CatalogCursor) Encode() string {
+	payload, _ := json.Marshal(cc)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func ParseCatalogCursor(value string) (*CatalogCursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor CatalogCursor
+	if err := json.Unmarshal(payload, &cursor); err != nil || cursor.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// CatalogPage is one page of a catalog listing. Total counts every match, not
+// just the rows on this page; Next is nil on the last page.
+type CatalogPage[T any] struct {
+	Items []*T
+	Total int
+	Next  *CatalogCursor
+}
+
+// ParseCatalogSort reads a sort parameter such as "name" or "-updatedAt"; a
+// leading minus sorts descending.
+func ParseCatalogSort(value string) (CatalogSort, bool, error) {
+	descending := strings.HasPrefix(value, "-")
+	sort := CatalogSort(strings.TrimPrefix(value, "-"))
+	if !sort.Valid() {
+		return "", false, errors.New("sort must be one of externalId, name, code, updatedAt or relevance, optionally prefixed with -")
+	}
+	return sort, descending, nil
+}
+
+// CatalogRef identifies a product or risk type either by our UUID or by its
+// NIC numeric ID; exactly one is set.
+type CatalogRef struct {
+	ID         uuid.UUID
+	ExternalID int
+}
+
+var ErrInvalidCatalogRef = errors.New("id must be a UUID or a NIC numeric id")
+
+func ParseCatalogRef(value string) (CatalogRef, error) {
+	value = strings.TrimSpace(value)
+	if id, err := uuid.Parse(value); err == nil {
+		return CatalogRef{ID: id}, nil
+	}
+	if id, err := strconv.Atoi(value); err == nil && id >= 0 {
+		return CatalogRef{ExternalID: id}, nil
+	}
+	return CatalogRef{}, ErrInvalidCatalogRef
+}
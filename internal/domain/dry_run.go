@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// NICCall is a request that would be made to NIC for one plate.
+type NICCall struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Plate  string `json:"plate"`
+}
+
+// NICPlan is the calls a batch would make with a NIC account, and how long
+// the account's rate limit, as it stands, would take to let them all
+// through. The time NIC takes to answer is not included.
+type NICPlan struct {
+	Calls             []NICCall
+	RateLimit         NICRateLimit
+	EstimatedDuration time.Duration
+}
+
+// InvalidPlate is a submitted plate that is answered without calling NIC.
+type InvalidPlate struct {
+	CarNumber string `json:"carNumber"`
+	Message   string `json:"message"`
+}
+
+// DryRun is what a sticker or brown card request would do, worked out
+// without calling NIC, using quota or claiming an Idempotency-Key. Plates
+// counts every plate submitted; only those in NICCalls are sent to NIC.
+// PlateRuleWarnings lists stickers whose risk type the plate rules do not
+// allow; they are only sent when the request overrides the rules.
+type DryRun struct {
+	DryRun              bool           `json:"dryRun"`
+	Plates              int            `json:"plates"`
+	Invalid             []InvalidPlate `json:"invalid"`
+	Duplicates          []string       `json:"duplicates"`
+	PlateRuleWarnings   []InvalidPlate `json:"plateRuleWarnings,omitempty"`
+	NICCalls            []NICCall      `json:"nicCalls"`
+	RateLimit           NICRateLimit   `json:"rateLimit"`
+	EstimatedDurationMs int64          `json:"estimatedDurationMs"`
+	EstimatedAmount     Money          `json:"estimatedAmount"`
+	PlateQuota          *QuotaStatus   `json:"plateQuota"`
+	IdempotentReplay    bool           `json:"idempotentReplay"`
+}
+
+// NewDryRun describes a batch of submitted plates, of which invalid and
+// duplicates are not sent, that plan would send to NIC.
+func NewDryRun(submitted int, invalid []InvalidPlate, duplicates []string, plan *NICPlan) *DryRun {
+	return &DryRun{
+		DryRun:              true,
+		Plates:              submitted,
+		Invalid:             invalid,
+		Duplicates:          duplicates,
+		NICCalls:            plan.Calls,
+		RateLimit:           plan.RateLimit,
+		EstimatedDurationMs: plan.EstimatedDuration.Milliseconds(),
+	}
+}
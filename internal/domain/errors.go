@@ -0,0 +1,7 @@
+package domain
+
+import "errors"
+
+var ErrNotFound = errors.New("not found")
+
+var ErrConflict = errors.New("already exists")
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fleet is a named list of vehicles owned by a tenant. Fleets without a
+// TenantID belong to clients without a tenant.
+type Fleet struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	TenantID    *uuid.UUID `json:"tenantId"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// FleetScope limits fleet queries to the fleets a caller may see. Unless
+// AllTenants is set, only fleets of TenantID, or without a tenant when it is
+// nil, match.
+type FleetScope struct {
+	AllTenants bool
+	TenantID   *uuid.UUID
+}
+
+// FleetScopeFor returns the scope of the fleets client may see: its tenant's,
+// or every fleet for admins.
+func FleetScopeFor(client *APIClient) FleetScope {
+	return FleetScope{AllTenants: client.Can(RoleAdmin), TenantID: client.TenantID}
+}
+
+type FleetVehicle struct {
+	ID             uuid.UUID `json:"id"`
+	FleetID        uuid.UUID `json:"fleetId"`
+	Plate          string    `json:"plate"`
+	Labels         []string  `json:"labels"`
+	OwnerReference string    `json:"ownerReference"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
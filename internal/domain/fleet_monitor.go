@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AlertType string
+
+const (
+	AlertTypeExpiring       AlertType = "expiring"
+	AlertTypeExpired        AlertType = "expired"
+	AlertTypeProductChanged AlertType = "product_changed"
+)
+
+// VehicleCheck is the stored outcome of one policy verification of a fleet
+// vehicle by the compliance monitor.
+type VehicleCheck struct {
+	FleetID         uuid.UUID       `json:"fleetId"`
+	Plate           string          `json:"plate"`
+	Success         bool            `json:"success"`
+	ProductName     string          `json:"productName"`
+	StartDate       time.Time       `json:"startDate"`
+	EndDate         time.Time       `json:"endDate"`
+	InsuranceStatus InsuranceStatus `json:"insuranceStatus"`
+	Message         string          `json:"message"`
+	CheckedAt       time.Time       `json:"checkedAt"`
+}
+
+type FleetAlert struct {
+	ID         uuid.UUID  `json:"id"`
+	FleetID    uuid.UUID  `json:"fleetId"`
+	Plate      string     `json:"plate"`
+	Type       AlertType  `json:"type"`
+	Message    string     `json:"message"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+	ResolvedAt *time.Time `json:"resolvedAt"`
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKey identifies a request sent with an Idempotency-Key header.
+// Keys are scoped to the client and endpoint, so two clients, or one client
+// on two endpoints, may use the same key.
+type IdempotencyKey struct {
+	ClientID uuid.UUID
+	Endpoint string
+	Key      string
+}
+
+// IdempotencyRecord is the first request made with a key. RequestHash
+// fingerprints its payload; StatusCode and Response are what it was answered
+// with, and are only set once it completes.
+type IdempotencyRecord struct {
+	IdempotencyKey
+	RequestHash string
+	StatusCode  int
+	Response    json.RawMessage
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+func (r IdempotencyRecord) Completed() bool {
+	return r.CompletedAt != nil
+}
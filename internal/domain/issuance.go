@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IssuanceKind is the document a bulk issuance request generates. Its values
+// are the metered services of the same documents.
+type IssuanceKind string
+
+const (
+	IssuanceSticker   IssuanceKind = "sticker"
+	IssuanceBrownCard IssuanceKind = "brown_card"
+)
+
+var IssuanceKinds = []IssuanceKind{IssuanceSticker, IssuanceBrownCard}
+
+// IssuanceStatus is where a bulk issuance request is in its review. Pending
+// requests are approved or rejected; approved ones are claimed for execution
+// against NIC and end executed or, if the batch could not be run at all or
+// was interrupted, failed.
+type IssuanceStatus string
+
+const (
+	IssuancePending   IssuanceStatus = "pending"
+	IssuanceRejected  IssuanceStatus = "rejected"
+	IssuanceApproved  IssuanceStatus = "approved"
+	IssuanceExecuting IssuanceStatus = "executing"
+	IssuanceExecuted  IssuanceStatus = "executed"
+	IssuanceFailed    IssuanceStatus = "failed"
+)
+
+var IssuanceStatuses = []IssuanceStatus{IssuancePending, IssuanceRejected, IssuanceApproved, IssuanceExecuting, IssuanceExecuted, IssuanceFailed}
+
+// IssuanceRequest is a batch of stickers or brown cards submitted by a maker
+// for approval. Plates have been validated and deduplicated, and
+// EstimatedAmount is what the batch costs if every plate is issued. Results
+// holds the per-plate outcome once the batch has been executed.
+type IssuanceRequest struct {
+	ID              uuid.UUID       `json:"id"`
+	Kind            IssuanceKind    `json:"kind"`
+	Status          IssuanceStatus  `json:"status"`
+	MakerID         uuid.UUID       `json:"makerId"`
+	TenantID        *uuid.UUID      `json:"tenantId"`
+	Plates          []string        `json:"plates"`
+	RiskTypeID      *int            `json:"riskTypeId,omitempty"`
+	Note            string          `json:"note"`
+	Branch          string          `json:"branch"`
+	EstimatedAmount Money           `json:"estimatedAmount"`
+	ApproverID      *uuid.UUID      `json:"approverId"`
+	DecisionNote    string          `json:"decisionNote"`
+	Issued          int             `json:"issued"`
+	Results         json.RawMessage `json:"results,omitempty"`
+	Error           string          `json:"error,omitempty"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	DecidedAt       *time.Time      `json:"decidedAt"`
+	StartedAt       *time.Time      `json:"startedAt"`
+	ExecutedAt      *time.Time      `json:"executedAt"`
+	Events          []IssuanceEvent `json:"events,omitempty"`
+}
+
+// IssuanceEvent records a request moving into a status. From is empty for
+// the submission.
+type IssuanceEvent struct {
+	From      IssuanceStatus `json:"from"`
+	To        IssuanceStatus `json:"to"`
+	ClientID  *uuid.UUID     `json:"clientId"`
+	Note      string         `json:"note"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+// IssuanceFilter selects issuance requests, newest first. Unless AllTenants
+// is set only requests of TenantID match, nil meaning those without a tenant.
+type IssuanceFilter struct {
+	AllTenants bool
+	TenantID   *uuid.UUID
+	Status     IssuanceStatus
+	Kind       IssuanceKind
+	Limit      int
+}
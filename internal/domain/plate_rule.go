@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PlateRiskRule allows plates of a kind to be insured under a risk category.
+// A rule with a Code applies only to plates with that region or special code
+// and overrides the kind-wide rules (empty Code) for them.
+type PlateRiskRule struct {
+	ID                uuid.UUID `json:"id"`
+	PlateKind         string    `json:"plateKind"`
+	Code              string    `json:"code"`
+	RiskCategoryID    uuid.UUID `json:"riskCategoryId"`
+	RiskCategory      string    `json:"riskCategory"`
+	DefaultRiskTypeId *int      `json:"defaultRiskTypeId"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+type PlateRiskRules []*PlateRiskRule
+
+// For returns the rules that apply to a plate: those naming its code if there
+// are any, otherwise those for its whole kind. No rules means the plate is
+// unrestricted.
+func (rs PlateRiskRules) For(kind, code string) PlateRiskRules {
+	specific := make(PlateRiskRules, 0)
+	general := make(PlateRiskRules, 0)
+	for _, r := range rs {
+		if r.PlateKind != kind {
+			continue
+		}
+		switch {
+		case r.Code == "":
+			general = append(general, r)
+		case code != "" && strings.EqualFold(r.Code, code):
+			specific = append(specific, r)
+		}
+	}
+	if len(specific) > 0 {
+		return specific
+	}
+	return general
+}
+
+func (rs PlateRiskRules) Categories() []string {
+	categories := make([]string, len(rs))
+	for i, r := range rs {
+		categories[i] = r.RiskCategory
+	}
+	return categories
+}
+
+func (rs PlateRiskRules) CategoryIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, len(rs))
+	for i, r := range rs {
+		ids[i] = r.RiskCategoryID
+	}
+	return ids
+}
+
+// DefaultRiskTypeId is the risk type to suggest for the plate, from the
+// first rule that names one.
+func (rs PlateRiskRules) DefaultRiskTypeId() *int {
+	for _, r := range rs {
+		if r.DefaultRiskTypeId != nil {
+			return r.DefaultRiskTypeId
+		}
+	}
+	return nil
+}
+
+// Allows reports whether riskType's category is one the rules allow.
+// Categories are matched by name, ignoring case and surrounding spaces, the
+// way risk types are linked to them.
+func (rs PlateRiskRules) Allows(riskType *RiskType) bool {
+	if len(rs) == 0 {
+		return true
+	}
+	category := strings.TrimSpace(riskType.RiskCategory)
+	for _, r := range rs {
+		if strings.EqualFold(r.RiskCategory, category) {
+			return true
+		}
+	}
+	return false
+}
+
+// Warning explains why riskType does not suit a plate, or is empty if it
+// does.
+func (rs PlateRiskRules) Warning(kind string, riskType *RiskType) string {
+	if rs.Allows(riskType) {
+		return ""
+	}
+	return fmt.Sprintf("risk type %d (%s, %s) is not usually used for %s plates; expected %s",
+		riskType.RiskTypeId, riskType.Name, riskType.RiskCategory, kind, strings.Join(rs.Categories(), " or "))
+}
@@ -1,11 +1,22 @@
 package domain
 
+import "time"
+
 type PolicyVerification struct {
-	ProductName        string `json:"productName"`
-	StartDate          string `json:"startDate"`
-	EndDate            string `json:"endDate"`
-	Message            string `json:"message"`
-	RegistrationNumber string `json:"registrationNumber"`
-	Status             string `json:"status"`
-	Success            bool   `json:"success"`
+	ProductName        string    `json:"productName"`
+	StartDate          time.Time `json:"startDate"`
+	EndDate            time.Time `json:"endDate"`
+	Message            string    `json:"message"`
+	RegistrationNumber string    `json:"registrationNumber"`
+	Status             string    `json:"status"`
+	Success            bool      `json:"success"`
+}
+
+// CoveredAt reports whether the policy was in force at t. Both bounds are
+// inclusive; a policy with unknown dates is never considered covering.
+func (pv PolicyVerification) CoveredAt(t time.Time) bool {
+	if !pv.Success || pv.StartDate.IsZero() || pv.EndDate.IsZero() {
+		return false
+	}
+	return !t.Before(pv.StartDate) && !t.After(pv.EndDate)
 }
@@ -12,5 +12,23 @@ type Product struct {
 	Name        string    `json:"name"`
 	ProductCode string    `json:"product_code"`
 	Description string    `json:"description"`
+	Active      bool      `json:"active"`
 	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// RiskTypeIds are the NIC risk types NIC allows for the product, nil when
+	// NIC did not say.
+	RiskTypeIds []int `json:"risk_type_ids,omitempty"`
+}
+
+// ProductVersion is the state of a product over [ValidFrom, ValidTo). The
+// current version has no ValidTo.
+type ProductVersion struct {
+	ProductId   int        `json:"product_id"`
+	Name        string     `json:"name"`
+	ProductCode string     `json:"product_code"`
+	Description string     `json:"description"`
+	Active      bool       `json:"active"`
+	SyncRunID   *uuid.UUID `json:"sync_run_id"`
+	ValidFrom   time.Time  `json:"valid_from"`
+	ValidTo     *time.Time `json:"valid_to"`
 }
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// ClientLimits caps how much one API client may use the API. Zero means
+// unlimited.
+type ClientLimits struct {
+	RequestsPerMinute int `json:"requestsPerMinute"`
+	PlatesPerDay      int `json:"platesPerDay"`
+	MaxBatchSize      int `json:"maxBatchSize"`
+}
+
+// BatchLimit is the most plates one request may carry: the batch size,
+// unless the daily quota is smaller. Zero means unlimited.
+func (l ClientLimits) BatchLimit() int {
+	if l.MaxBatchSize == 0 || (l.PlatesPerDay > 0 && l.PlatesPerDay < l.MaxBatchSize) {
+		return l.PlatesPerDay
+	}
+	return l.MaxBatchSize
+}
+
+// DefaultClientLimits are what new clients get unless others are given.
+var DefaultClientLimits = ClientLimits{RequestsPerMinute: 120, PlatesPerDay: 10000, MaxBatchSize: 500}
+
+// Quota names a usage counter kept per client and window.
+type Quota string
+
+const (
+	QuotaRequestsPerMinute Quota = "requests_per_minute"
+	QuotaPlatesPerDay      Quota = "plates_per_day"
+	// QuotaDirectIssuancePerDay counts the plates a client issues stickers or
+	// brown cards for without an issuance request.
+	QuotaDirectIssuancePerDay Quota = "direct_issuance_per_day"
+)
+
+// QuotaStatus is a client's standing in the current window of one quota.
+type QuotaStatus struct {
+	Limit     int       `json:"limit"`
+	Used      int       `json:"used"`
+	Remaining int       `json:"remaining"`
+	ResetsAt  time.Time `json:"resetsAt"`
+}
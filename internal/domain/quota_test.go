@@ -0,0 +1,26 @@
+package domain
+
+import "testing"
+
+func TestClientLimitsBatchLimit(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits ClientLimits
+		want   int
+	}{
+		{name: "batch size below the day", limits: ClientLimits{PlatesPerDay: 100, MaxBatchSize: 20}, want: 20},
+		{name: "day below the batch size", limits: ClientLimits{PlatesPerDay: 10, MaxBatchSize: 20}, want: 10},
+		{name: "equal", limits: ClientLimits{PlatesPerDay: 20, MaxBatchSize: 20}, want: 20},
+		{name: "no batch size", limits: ClientLimits{PlatesPerDay: 10}, want: 10},
+		{name: "no daily quota", limits: ClientLimits{MaxBatchSize: 20}, want: 20},
+		{name: "unlimited", limits: ClientLimits{}, want: 0},
+		{name: "defaults", limits: DefaultClientLimits, want: 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limits.BatchLimit(); got != tt.want {
+				t.Errorf("BatchLimit() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
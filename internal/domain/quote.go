@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// daysPerYear is the basis premiums are pro-rated on. Longer cover is charged
+// as a full year so leap years cost the same as any other.
+const daysPerYear = 365
+
+// Money is an amount in pesewas. It is written to and read from JSON in
+// cedis, so 1234.5 means GHS 1,234.50.
+type Money int64
+
+// String formats m in cedis with two decimals, e.g. "1234.50".
+func (m Money) String() string {
+	return strconv.FormatFloat(float64(m)/100, 'f', 2, 64)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %s", data)
+	}
+	*m = Money(math.Round(f * 100))
+	return nil
+}
+
+// Percent returns p percent of m, rounded to the nearest pesewa.
+func (m Money) Percent(p float64) Money {
+	return Money(math.Round(float64(m) * p / 100))
+}
+
+// SumInsuredBand overrides the base rate for sums insured between From and To
+// inclusive. A zero To leaves the band open ended.
+type SumInsuredBand struct {
+	From Money   `json:"from"`
+	To   Money   `json:"to"`
+	Rate float64 `json:"rate"`
+}
+
+func (b SumInsuredBand) Contains(sumInsured Money) bool {
+	return sumInsured >= b.From && (b.To == 0 || sumInsured <= b.To)
+}
+
+// VehicleClassLoading adds Loading percent of the basic premium for vehicles
+// of the class.
+type VehicleClassLoading struct {
+	VehicleClass string  `json:"vehicleClass"`
+	Loading      float64 `json:"loading"`
+}
+
+// Levy is charged on top of the premium: Rate percent of it plus Fixed.
+type Levy struct {
+	Code  string  `json:"code"`
+	Name  string  `json:"name"`
+	Rate  float64 `json:"rate"`
+	Fixed Money   `json:"fixed"`
+}
+
+// RateTable prices cover for one product and risk type. Rates and loadings
+// are percentages.
+type RateTable struct {
+	ID                   uuid.UUID             `json:"id"`
+	ProductId            int                   `json:"productId"`
+	RiskTypeId           int                   `json:"riskTypeId"`
+	Currency             string                `json:"currency"`
+	BaseRate             float64               `json:"baseRate"`
+	SumInsuredBands      []SumInsuredBand      `json:"sumInsuredBands"`
+	VehicleClassLoadings []VehicleClassLoading `json:"vehicleClassLoadings"`
+	MinimumPremium       Money                 `json:"minimumPremium"`
+	Levies               []Levy                `json:"levies"`
+	CreatedAt            time.Time             `json:"createdAt"`
+	UpdatedAt            time.Time             `json:"updatedAt"`
+}
+
+// RateFor returns the rate of the first band containing sumInsured, or the
+// base rate if none does.
+func (rt *RateTable) RateFor(sumInsured Money) float64 {
+	for _, band := range rt.SumInsuredBands {
+		if band.Contains(sumInsured) {
+			return band.Rate
+		}
+	}
+	return rt.BaseRate
+}
+
+func (rt *RateTable) loadingFor(vehicleClass string) (VehicleClassLoading, bool) {
+	for _, l := range rt.VehicleClassLoadings {
+		if strings.EqualFold(l.VehicleClass, vehicleClass) {
+			return l, true
+		}
+	}
+	return VehicleClassLoading{}, false
+}
+
+// Price fills in the rate, itemised lines and totals of q from its sum
+// insured, vehicle class and cover days. The basic premium is pro-rated by
+// cover days, loaded for the vehicle class, then raised to the minimum
+// premium; levies are charged on the result.
+func (rt *RateTable) Price(q *Quote) {
+	id := rt.ID
+	q.RateTableID = &id
+	q.Currency = rt.Currency
+	q.Rate = rt.RateFor(q.SumInsured)
+	q.Lines = q.Lines[:0]
+
+	days := min(q.CoverDays, daysPerYear)
+	basic := Money(math.Round(float64(q.SumInsured.Percent(q.Rate)) * float64(days) / daysPerYear))
+	q.addLine("BASIC", fmt.Sprintf("Basic premium at %g%% for %d days", q.Rate, q.CoverDays), basic)
+	premium := basic
+
+	if loading, ok := rt.loadingFor(q.VehicleClass); ok && loading.Loading != 0 {
+		amount := basic.Percent(loading.Loading)
+		q.addLine("LOADING", fmt.Sprintf("%s loading at %g%%", loading.VehicleClass, loading.Loading), amount)
+		premium += amount
+	}
+	if premium < rt.MinimumPremium {
+		q.addLine("MINIMUM_PREMIUM", "Adjustment to minimum premium", rt.MinimumPremium-premium)
+		premium = rt.MinimumPremium
+	}
+
+	var levies Money
+	for _, levy := range rt.Levies {
+		amount := premium.Percent(levy.Rate) + levy.Fixed
+		q.addLine(levy.Code, levy.Name, amount)
+		levies += amount
+	}
+
+	q.Premium = premium
+	q.Levies = levies
+	q.Total = premium + levies
+}
+
+type QuoteLine struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	Amount      Money  `json:"amount"`
+}
+
+type Quote struct {
+	ID           uuid.UUID   `json:"id"`
+	Plate        string      `json:"plate"`
+	ProductId    int         `json:"productId"`
+	RiskTypeId   int         `json:"riskTypeId"`
+	RateTableID  *uuid.UUID  `json:"rateTableId"`
+	VehicleClass string      `json:"vehicleClass"`
+	SumInsured   Money       `json:"sumInsured"`
+	CoverStart   time.Time   `json:"coverStart"`
+	CoverEnd     time.Time   `json:"coverEnd"`
+	CoverDays    int         `json:"coverDays"`
+	Currency     string      `json:"currency"`
+	Rate         float64     `json:"rate"`
+	Lines        []QuoteLine `json:"lines"`
+	Premium      Money       `json:"premium"`
+	Levies       Money       `json:"levies"`
+	Total        Money       `json:"total"`
+	// Warnings flag choices that look wrong, such as a risk type the plate
+	// rules do not allow, without refusing the quote.
+	Warnings  []string  `json:"warnings"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (q *Quote) addLine(code, description string, amount Money) {
+	q.Lines = append(q.Lines, QuoteLine{Code: code, Description: description, Amount: amount})
+}
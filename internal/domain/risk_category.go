@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RiskCategory struct {
+	ID              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	ActiveRiskTypes int       `json:"activeRiskTypes"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// MappingSource records who decided that a risk type is allowed for a
+// product.
+type MappingSource string
+
+const (
+	MappingSourceNIC   MappingSource = "nic"
+	MappingSourceAdmin MappingSource = "admin"
+)
+
+type ProductRiskType struct {
+	RiskType RiskType      `json:"riskType"`
+	Source   MappingSource `json:"source"`
+}
+
+var (
+	ErrUnknownProduct      = errors.New("product not found")
+	ErrUnknownRiskType     = errors.New("risk type not found")
+	ErrUnknownRiskCategory = errors.New("risk category not found")
+)
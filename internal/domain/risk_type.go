@@ -13,5 +13,21 @@ type RiskType struct {
 	Description  string    `json:"description"`
 	RiskCategory string    `json:"riskCategory"`
 	RiskTypeCode string    `json:"riskTypeCode"`
+	Active       bool      `json:"active"`
 	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// RiskTypeVersion is the state of a risk type over [ValidFrom, ValidTo). The
+// current version has no ValidTo.
+type RiskTypeVersion struct {
+	RiskTypeId   int        `json:"risk_type_id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	RiskCategory string     `json:"riskCategory"`
+	RiskTypeCode string     `json:"riskTypeCode"`
+	Active       bool       `json:"active"`
+	SyncRunID    *uuid.UUID `json:"syncRunId"`
+	ValidFrom    time.Time  `json:"validFrom"`
+	ValidTo      *time.Time `json:"validTo"`
 }
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxBranchLength bounds the branch a sticker or issuance request names.
+const MaxBranchLength = 100
+
+// StickerStatus is whether a registered sticker is still in use. Stickers
+// start active; branches mark them voided when replaced or cancelled and
+// lost when they cannot be accounted for.
+type StickerStatus string
+
+const (
+	StickerActive StickerStatus = "active"
+	StickerVoided StickerStatus = "voided"
+	StickerLost   StickerStatus = "lost"
+)
+
+var StickerStatuses = []StickerStatus{StickerActive, StickerVoided, StickerLost}
+
+// IssuedSticker is a sticker NIC generated for a plate.
+type IssuedSticker struct {
+	Plate         string
+	StickerNumber string
+	StickerLink   string
+}
+
+// StickerRecord is a sticker in the registry. ClientID is the client that
+// requested it, the maker for bulk issuance requests.
+type StickerRecord struct {
+	ID              uuid.UUID     `json:"id"`
+	Plate           string        `json:"plate"`
+	CanonicalPlate  string        `json:"canonicalPlate"`
+	StickerNumber   string        `json:"stickerNumber"`
+	StickerLink     string        `json:"stickerLink"`
+	ClientID        *uuid.UUID    `json:"clientId"`
+	TenantID        *uuid.UUID    `json:"tenantId"`
+	Branch          string        `json:"branch"`
+	Status          StickerStatus `json:"status"`
+	StatusNote      string        `json:"statusNote"`
+	IssuedAt        time.Time     `json:"issuedAt"`
+	StatusChangedAt *time.Time    `json:"statusChangedAt"`
+}
+
+// StickerFilter selects registry entries, newest first. Unless AllTenants
+// is set only entries of TenantID match, nil meaning those without a tenant.
+// Plate is in canonical form.
+type StickerFilter struct {
+	AllTenants    bool
+	TenantID      *uuid.UUID
+	Plate         string
+	StickerNumber string
+	Status        StickerStatus
+	Branch        string
+	Limit         int
+}
+
+// DuplicateStickerNumber is a sticker number registered against more than
+// one plate.
+type DuplicateStickerNumber struct {
+	StickerNumber string    `json:"stickerNumber"`
+	Plates        []string  `json:"plates"`
+	Entries       int       `json:"entries"`
+	FirstIssuedAt time.Time `json:"firstIssuedAt"`
+	LastIssuedAt  time.Time `json:"lastIssuedAt"`
+}
+
+// PlateStickers is a plate with more than one active sticker number.
+type PlateStickers struct {
+	Plate          string    `json:"plate"`
+	StickerNumbers []string  `json:"stickerNumbers"`
+	Entries        int       `json:"entries"`
+	LastIssuedAt   time.Time `json:"lastIssuedAt"`
+}
+
+// StickerReconciliation flags registry entries that need a branch to look
+// into them.
+type StickerReconciliation struct {
+	GeneratedAt      time.Time                `json:"generatedAt"`
+	DuplicateNumbers []DuplicateStickerNumber `json:"duplicateNumbers"`
+	MultipleActive   []PlateStickers          `json:"multipleActive"`
+}
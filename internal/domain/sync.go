@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Catalog string
+
+const (
+	CatalogProducts  Catalog = "products"
+	CatalogRiskTypes Catalog = "risk_types"
+)
+
+type SyncAction string
+
+const (
+	SyncActionAdded       SyncAction = "added"
+	SyncActionUpdated     SyncAction = "updated"
+	SyncActionDeactivated SyncAction = "deactivated"
+)
+
+type SyncOutcome string
+
+const (
+	SyncOutcomeRunning SyncOutcome = "running"
+	SyncOutcomeSuccess SyncOutcome = "success"
+	SyncOutcomeFailed  SyncOutcome = "failed"
+	SyncOutcomeSkipped SyncOutcome = "skipped"
+)
+
+// ErrEmptyCatalog is returned by syncs and imports that found no usable
+// records, which would otherwise deactivate the whole catalog.
+var ErrEmptyCatalog = errors.New("catalog has no usable records; force the sync to deactivate every entry")
+
+// SyncOptions controls a reference data sync. When SkipIfChecksum matches the
+// checksum of the NIC payload the catalog is left untouched. RunID is stamped
+// on the catalog versions the sync creates. With a Source the records are read
+// from that file instead of being downloaded from NIC. Unless Force is set, a
+// payload without usable records fails with ErrEmptyCatalog.
+type SyncOptions struct {
+	RunID          uuid.UUID
+	DryRun         bool
+	Force          bool
+	SkipIfChecksum string
+	Source         *CatalogFile
+}
+
+// SyncSummary reports how a reference data sync from NIC changed the local
+// catalog.
+type SyncSummary struct {
+	Added       int `json:"added"`
+	Updated     int `json:"updated"`
+	Deactivated int `json:"deactivated"`
+	Unchanged   int `json:"unchanged"`
+}
+
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// SyncChange describes what a sync did, or in a dry run would do, to one
+// catalog entry identified by its NIC ID.
+type SyncChange struct {
+	ExternalID int           `json:"externalId"`
+	Name       string        `json:"name"`
+	Action     SyncAction    `json:"action"`
+	Fields     []FieldChange `json:"fields,omitempty"`
+}
+
+// SyncRejection is an upstream record that could not be imported.
+type SyncRejection struct {
+	ExternalID string `json:"externalId"`
+	Name       string `json:"name"`
+	Reason     string `json:"reason"`
+}
+
+type SyncResult struct {
+	DryRun        bool            `json:"dryRun"`
+	Skipped       bool            `json:"skipped"`
+	Checksum      string          `json:"checksum"`
+	UpstreamCount int             `json:"upstreamCount"`
+	Summary       SyncSummary     `json:"summary"`
+	Changes       []SyncChange    `json:"changes"`
+	Rejected      []SyncRejection `json:"rejected"`
+}
+
+type SyncRun struct {
+	ID            uuid.UUID       `json:"id"`
+	Catalog       Catalog         `json:"catalog"`
+	TriggerSource string          `json:"triggerSource"`
+	DryRun        bool            `json:"dryRun"`
+	StartedAt     time.Time       `json:"startedAt"`
+	FinishedAt    *time.Time      `json:"finishedAt"`
+	Outcome       SyncOutcome     `json:"outcome"`
+	Error         string          `json:"error"`
+	UpstreamCount int             `json:"upstreamCount"`
+	Summary       SyncSummary     `json:"summary"`
+	Changes       []SyncChange    `json:"changes"`
+	Rejected      []SyncRejection `json:"rejected"`
+	Checksum      string          `json:"checksum"`
+}
+
+// Finish records the result of the sync on the run.
+func (sr *SyncRun) Finish(result *SyncResult, err error) {
+	now := time.Now()
+	sr.FinishedAt = &now
+	if err != nil {
+		sr.Outcome = SyncOutcomeFailed
+		sr.Error = err.Error()
+		return
+	}
+	sr.Outcome = SyncOutcomeSuccess
+	if result.Skipped {
+		sr.Outcome = SyncOutcomeSkipped
+	}
+	sr.Checksum = result.Checksum
+	sr.UpstreamCount = result.UpstreamCount
+	sr.Summary = result.Summary
+	sr.Changes = result.Changes
+	sr.Rejected = result.Rejected
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NICEnvironment selects which NIC deployment a tenant's calls go to.
+type NICEnvironment string
+
+const (
+	NICSandbox    NICEnvironment = "sandbox"
+	NICProduction NICEnvironment = "production"
+)
+
+var NICEnvironments = []NICEnvironment{NICSandbox, NICProduction}
+
+// NICRateLimit paces the calls made with one NIC account: at most Burst calls
+// at once, then one every RequestIntervalMs, spread over Workers concurrent
+// requests.
+type NICRateLimit struct {
+	RequestIntervalMs int `json:"requestIntervalMs"`
+	Burst             int `json:"burst"`
+	Workers           int `json:"workers"`
+}
+
+// DefaultNICRateLimit is what calls made with the configured NIC account use,
+// and what new tenants start with.
+var DefaultNICRateLimit = NICRateLimit{RequestIntervalMs: 300, Burst: 2, Workers: 5}
+
+func (l NICRateLimit) Interval() time.Duration {
+	return time.Duration(l.RequestIntervalMs) * time.Millisecond
+}
+
+// Tenant is an insurance company served by the API, with its own NIC account.
+// NICAPIKey is only held decrypted in memory and is never serialised.
+type Tenant struct {
+	ID             uuid.UUID      `json:"id"`
+	Name           string         `json:"name"`
+	NICEnvironment NICEnvironment `json:"nicEnvironment"`
+	NICAPIKey      string         `json:"-"`
+	NICRateLimit   NICRateLimit   `json:"nicRateLimit"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
+}
+
+type tenantContextKey struct{}
+
+// WithTenant returns a context whose NIC calls are made with the tenant's
+// account.
+func WithTenant(ctx context.Context, tenant *Tenant) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant set by WithTenant, or nil when calls
+// should use the configured NIC account.
+func TenantFromContext(ctx context.Context) *Tenant {
+	tenant, _ := ctx.Value(tenantContextKey{}).(*Tenant)
+	return tenant
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MeteredService is a vehicle service whose use is billed to API clients.
+type MeteredService string
+
+const (
+	ServiceBrownCard          MeteredService = "brown_card"
+	ServiceSticker            MeteredService = "sticker"
+	ServiceUSSDCheck          MeteredService = "ussd_check"
+	ServicePolicyVerification MeteredService = "policy_verification"
+	ServiceVehicleProfile     MeteredService = "vehicle_profile"
+	ServiceFleetCheck         MeteredService = "fleet_check"
+)
+
+var MeteredServices = []MeteredService{
+	ServiceBrownCard,
+	ServiceSticker,
+	ServiceUSSDCheck,
+	ServicePolicyVerification,
+	ServiceVehicleProfile,
+	ServiceFleetCheck,
+}
+
+// UsageRecord is one metered request: the plates a client submitted, the NIC
+// calls made for them and, for stickers and brown cards, how many were
+// issued. Amount is priced when the record is stored.
+type UsageRecord struct {
+	ClientID uuid.UUID
+	Service  MeteredService
+	Plates   int
+	NICCalls int
+	Issued   int
+}
+
+// ServicePrice is what one unit of each measure of a service costs. Price
+// changes apply to usage recorded after them.
+type ServicePrice struct {
+	Service      MeteredService `json:"service"`
+	PlatePrice   Money          `json:"platePrice"`
+	NICCallPrice Money          `json:"nicCallPrice"`
+	IssuedPrice  Money          `json:"issuedPrice"`
+	UpdatedAt    time.Time      `json:"updatedAt"`
+}
+
+// Estimate prices usage of the service at these prices.
+func (p ServicePrice) Estimate(plates, nicCalls, issued int) Money {
+	return Money(plates)*p.PlatePrice + Money(nicCalls)*p.NICCallPrice + Money(issued)*p.IssuedPrice
+}
+
+// StatementLine totals one client's use of one service over a statement
+// period.
+type StatementLine struct {
+	Service  MeteredService `json:"service"`
+	Requests int            `json:"requests"`
+	Plates   int            `json:"plates"`
+	NICCalls int            `json:"nicCalls"`
+	Issued   int            `json:"issued"`
+	Amount   Money          `json:"amount"`
+}
+
+// Statement is what a client owes for a month, in Accra time.
+type Statement struct {
+	ClientID   uuid.UUID       `json:"clientId"`
+	ClientName string          `json:"clientName"`
+	Month      string          `json:"month"`
+	Lines      []StatementLine `json:"lines"`
+	Total      Money           `json:"total"`
+}
+
+// NICCallCounter counts the NIC calls made under a context.
+type NICCallCounter struct {
+	calls atomic.Int64
+}
+
+func (c *NICCallCounter) Calls() int {
+	return int(c.calls.Load())
+}
+
+type nicCallCounterKey struct{}
+
+// WithNICCallCounter returns a context whose NIC calls are counted by the
+// returned counter.
+func WithNICCallCounter(ctx context.Context) (context.Context, *NICCallCounter) {
+	counter := &NICCallCounter{}
+	return context.WithValue(ctx, nicCallCounterKey{}, counter), counter
+}
+
+// CountNICCall records a NIC call against the context's counter, if it has
+// one.
+func CountNICCall(ctx context.Context) {
+	if counter, ok := ctx.Value(nicCallCounterKey{}).(*NICCallCounter); ok {
+		counter.calls.Add(1)
+	}
+}
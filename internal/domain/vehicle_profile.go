@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+type InsuranceStatus string
+
+const (
+	InsuranceStatusInsured  InsuranceStatus = "insured"
+	InsuranceStatusExpiring InsuranceStatus = "expiring"
+	InsuranceStatusExpired  InsuranceStatus = "expired"
+	InsuranceStatusUnknown  InsuranceStatus = "unknown"
+)
+
+// InsuranceStatus classifies a verified policy against now. Policies ending
+// within expiringDays are reported as expiring.
+func (pv PolicyVerification) InsuranceStatus(now time.Time, expiringDays int) (InsuranceStatus, string) {
+	if !pv.Success {
+		return InsuranceStatusUnknown, "No policy found for vehicle"
+	}
+	if pv.EndDate.IsZero() {
+		return InsuranceStatusUnknown, "Policy end date could not be read"
+	}
+	if pv.EndDate.Before(now) {
+		return InsuranceStatusExpired, "Policy has expired"
+	}
+	if pv.EndDate.Before(now.AddDate(0, 0, expiringDays)) {
+		return InsuranceStatusExpiring, "Policy expires soon"
+	}
+	return InsuranceStatusInsured, "Vehicle is insured"
+}
@@ -0,0 +1,64 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata"
+)
+
+// Accra is the timezone NIC reports policy dates in.
+var Accra = loadAccra()
+
+var dateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000Z",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"02/01/2006 15:04:05",
+	"02-01-2006 15:04:05",
+}
+
+var dateOnlyLayouts = []string{
+	"2006-01-02",
+	"02/01/2006",
+	"02-01-2006",
+	"2 Jan 2006",
+	"02 Jan 2006",
+	"Jan 2, 2006",
+}
+
+func loadAccra() *time.Location {
+	loc, err := time.LoadLocation("Africa/Accra")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ParseDate reads the date formats NIC has been seen to return. Values without
+// an offset are interpreted in Africa/Accra. dateOnly reports whether the value
+// carried no time of day, so callers can decide how to treat the whole day.
+func ParseDate(value string) (t time.Time, dateOnly bool, err error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false, fmt.Errorf("empty date")
+	}
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, Accra); err == nil {
+			return t, false, nil
+		}
+	}
+	for _, layout := range dateOnlyLayouts {
+		if t, err := time.ParseInLocation(layout, value, Accra); err == nil {
+			return t, true, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("unrecognised date: %q", value)
+}
+
+// EndOfDay returns the last instant of t's calendar day in Accra.
+func EndOfDay(t time.Time) time.Time {
+	t = t.In(Accra)
+	return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, int(time.Second-time.Nanosecond), Accra)
+}
@@ -0,0 +1,67 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value        string
+		want         time.Time
+		wantDateOnly bool
+		wantErr      bool
+	}{
+		{value: "2026-03-01T10:15:30Z", want: time.Date(2026, 3, 1, 10, 15, 30, 0, time.UTC)},
+		{value: "2026-03-01T10:15:30.250+01:00", want: time.Date(2026, 3, 1, 9, 15, 30, 250000000, time.UTC)},
+		{value: "2026-03-01T10:15:30", want: time.Date(2026, 3, 1, 10, 15, 30, 0, Accra)},
+		{value: "2026-03-01 10:15:30", want: time.Date(2026, 3, 1, 10, 15, 30, 0, Accra)},
+		{value: "01/03/2026 10:15:30", want: time.Date(2026, 3, 1, 10, 15, 30, 0, Accra)},
+		{value: "01-03-2026 10:15:30", want: time.Date(2026, 3, 1, 10, 15, 30, 0, Accra)},
+		{value: "2026-03-01", want: time.Date(2026, 3, 1, 0, 0, 0, 0, Accra), wantDateOnly: true},
+		{value: "01/03/2026", want: time.Date(2026, 3, 1, 0, 0, 0, 0, Accra), wantDateOnly: true},
+		{value: "01-03-2026", want: time.Date(2026, 3, 1, 0, 0, 0, 0, Accra), wantDateOnly: true},
+		{value: "1 Mar 2026", want: time.Date(2026, 3, 1, 0, 0, 0, 0, Accra), wantDateOnly: true},
+		{value: "01 Mar 2026", want: time.Date(2026, 3, 1, 0, 0, 0, 0, Accra), wantDateOnly: true},
+		{value: "Mar 1, 2026", want: time.Date(2026, 3, 1, 0, 0, 0, 0, Accra), wantDateOnly: true},
+		{value: "  2026-03-01  ", want: time.Date(2026, 3, 1, 0, 0, 0, 0, Accra), wantDateOnly: true},
+		{value: "", wantErr: true},
+		{value: "   ", wantErr: true},
+		{value: "2026-02-30", wantErr: true},
+		{value: "03/13/2026", wantErr: true},
+		{value: "tomorrow", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, dateOnly, err := ParseDate(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDate(%q) error = %v, want error %t", tt.value, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !got.Equal(tt.want) || dateOnly != tt.wantDateOnly {
+				t.Errorf("ParseDate(%q) = %s, %t; want %s, %t", tt.value, got, dateOnly, tt.want, tt.wantDateOnly)
+			}
+		})
+	}
+}
+
+func TestEndOfDay(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{name: "start of the day", t: time.Date(2026, 3, 1, 0, 0, 0, 0, Accra), want: time.Date(2026, 3, 1, 23, 59, 59, 999999999, Accra)},
+		{name: "last instant of the day", t: time.Date(2026, 3, 1, 23, 59, 59, 999999999, Accra), want: time.Date(2026, 3, 1, 23, 59, 59, 999999999, Accra)},
+		{name: "already the next day in Accra", t: time.Date(2026, 3, 1, 23, 30, 0, 0, time.FixedZone("west", -3600)), want: time.Date(2026, 3, 2, 23, 59, 59, 999999999, Accra)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EndOfDay(tt.t); !got.Equal(tt.want) {
+				t.Errorf("EndOfDay(%s) = %s, want %s", tt.t, got, tt.want)
+			}
+		})
+	}
+}
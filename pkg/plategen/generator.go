@@ -0,0 +1,240 @@
+package plategen
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/godsent-code/midtools/pkg"
+)
+
+type Kind string
+
+const (
+	KindOldFormat    Kind = "old"
+	KindNewFormat    Kind = "new"
+	KindDVTrade      Kind = "dv"
+	KindMotorcycle   Kind = "motorcycle"
+	KindSpecial      Kind = "special"
+	KindPersonalised Kind = "personalised"
+	KindInvalid      Kind = "invalid"
+)
+
+// AllKinds lists every valid plate format the validator accepts.
+var AllKinds = []Kind{
+	KindOldFormat,
+	KindNewFormat,
+	KindDVTrade,
+	KindMotorcycle,
+	KindSpecial,
+	KindPersonalised,
+}
+
+var regionCodes = []string{
+	"AC", "AE", "AK", "AP", "AS", "AW",
+	"BA", "BR", "BW",
+	"BE", "BT",
+	"CR", "CW", "CS",
+	"EN", "ER", "ES",
+	"GB", "GC", "GE", "GG", "GH", "GL", "GM", "GN", "GR", "GT", "GS", "GW", "GX", "GY",
+	"NR", "NW",
+	"UE", "UW", "UD",
+	"UH",
+	"VA", "VD", "VR",
+	"WR", "WT",
+}
+
+var specialCodes = []string{"GA", "GP", "FS", "PS", "FZB"}
+
+var personalisedWords = []string{
+	"SERIOUS", "KOFI", "AKWAABA", "NANA", "BLESSED", "OSAGYEFO", "KWAME",
+	"SANKOFA", "ABENA", "NYAME", "BOSS", "CHAMP", "RAPDR", "KENNY",
+}
+
+const letters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+type Options struct {
+	Count       int
+	Seed        uint64
+	InvalidRate float64
+	Kinds       []Kind
+}
+
+type Plate struct {
+	Number string `json:"plate"`
+	Kind   Kind   `json:"kind"`
+	Valid  bool   `json:"valid"`
+}
+
+func (o *Options) Validate() error {
+	if o.Count <= 0 {
+		return errors.New("count must be greater than zero")
+	}
+	if o.InvalidRate < 0 || o.InvalidRate > 1 {
+		return errors.New("invalid rate must be between 0 and 1")
+	}
+	for _, k := range o.Kinds {
+		if !isValidKind(k) {
+			return fmt.Errorf("unknown plate kind: %s", k)
+		}
+	}
+	return nil
+}
+
+// Generate returns opts.Count plates. The same options always produce the
+// same list, so runs can be replayed against the fake NIC.
+func Generate(opts Options) ([]Plate, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	kinds := opts.Kinds
+	if len(kinds) == 0 {
+		kinds = AllKinds
+	}
+
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x9e3779b97f4a7c15))
+	plates := make([]Plate, 0, opts.Count)
+	for len(plates) < opts.Count {
+		if opts.InvalidRate > 0 && rng.Float64() < opts.InvalidRate {
+			plates = append(plates, Plate{Number: invalidPlate(rng), Kind: KindInvalid, Valid: false})
+			continue
+		}
+		kind := kinds[rng.IntN(len(kinds))]
+		plates = append(plates, Plate{Number: validPlate(rng, kind), Kind: kind, Valid: true})
+	}
+	return plates, nil
+}
+
+func ParseKinds(s string) ([]Kind, error) {
+	kinds := make([]Kind, 0)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		if !isValidKind(Kind(part)) {
+			return nil, fmt.Errorf("unknown plate kind: %s", part)
+		}
+		kinds = append(kinds, Kind(part))
+	}
+	return kinds, nil
+}
+
+func isValidKind(k Kind) bool {
+	for _, known := range AllKinds {
+		if k == known {
+			return true
+		}
+	}
+	return false
+}
+
+// validPlate keeps drawing until the validator accepts the plate so the
+// generator can never drift from what the services let through.
+func validPlate(rng *rand.Rand, kind Kind) string {
+	for {
+		var plate string
+		switch kind {
+		case KindOldFormat:
+			plate = fmt.Sprintf("%s %s-%02d", pick(rng, regionCodes), digits(rng, 1, 4), rng.IntN(100))
+		case KindNewFormat:
+			plate = fmt.Sprintf("%s %s-%s", pick(rng, regionCodes), digits(rng, 1, 4), randomLetters(rng, 2))
+		case KindDVTrade:
+			plate = fmt.Sprintf("DV %04d-%02d", rng.IntN(10000), rng.IntN(100))
+		case KindMotorcycle:
+			plate = "M-" + digits(rng, 4, 5)
+		case KindSpecial:
+			code := pick(rng, specialCodes)
+			// Two letter codes followed by three or more digits read as the
+			// old format, so keep them short.
+			if len(code) == 2 {
+				plate = code + " " + digits(rng, 1, 2)
+			} else {
+				plate = code + " " + digits(rng, 1, 4)
+			}
+		case KindPersonalised:
+			plate = pick(rng, personalisedWords) + digits(rng, 1, 2)
+			if rng.IntN(2) == 0 {
+				plate += "-" + digits(rng, 2, 2)
+			}
+		}
+		if ok, _ := pkg.ValidateGhanaLicensePlate(plate); ok && !shadowed(plate, kind) {
+			return plate
+		}
+	}
+}
+
+// shadowed reports whether a personalised candidate would be classified as a
+// stricter format by the validator.
+func shadowed(plate string, kind Kind) bool {
+	if kind != KindPersonalised {
+		return false
+	}
+	_, msg := pkg.ValidateGhanaLicensePlate(plate)
+	return !strings.HasPrefix(msg, "Possible personalised plate")
+}
+
+func invalidPlate(rng *rand.Rand) string {
+	for {
+		var plate string
+		switch rng.IntN(5) {
+		case 0:
+			// Unknown region code
+			plate = fmt.Sprintf("%s %s-%02d", unknownRegion(rng), digits(rng, 1, 4), rng.IntN(100))
+		case 1:
+			// Letters only
+			plate = randomLetters(rng, 2+rng.IntN(6))
+		case 2:
+			// Digits only
+			plate = digits(rng, 5, 8)
+		case 3:
+			// Too long
+			plate = randomLetters(rng, 10) + digits(rng, 6, 8)
+		case 4:
+			// Unknown special code
+			plate = unknownRegion(rng) + randomLetters(rng, 1) + digits(rng, 1, 4)
+		}
+		if ok, _ := pkg.ValidateGhanaLicensePlate(plate); !ok {
+			return plate
+		}
+	}
+}
+
+func unknownRegion(rng *rand.Rand) string {
+	known := make(map[string]bool, len(regionCodes)+len(specialCodes))
+	for _, c := range regionCodes {
+		known[c] = true
+	}
+	for _, c := range specialCodes {
+		known[c] = true
+	}
+	for {
+		code := randomLetters(rng, 2)
+		if !known[code] && code != "DV" {
+			return code
+		}
+	}
+}
+
+func pick(rng *rand.Rand, values []string) string {
+	return values[rng.IntN(len(values))]
+}
+
+func digits(rng *rand.Rand, min, max int) string {
+	n := min + rng.IntN(max-min+1)
+	var b strings.Builder
+	b.WriteByte(byte('1' + rng.IntN(9)))
+	for i := 1; i < n; i++ {
+		b.WriteByte(byte('0' + rng.IntN(10)))
+	}
+	return b.String()
+}
+
+func randomLetters(rng *rand.Rand, n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		b.WriteByte(letters[rng.IntN(len(letters))])
+	}
+	return b.String()
+}
@@ -0,0 +1,49 @@
+package plategen
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// Write renders plates in the requested format. Text output is one plate per
+// line so it can be pasted straight into a `cars` field.
+func Write(w io.Writer, plates []Plate, format Format) error {
+	switch format {
+	case FormatText, "":
+		for _, p := range plates {
+			if _, err := fmt.Fprintln(w, p.Number); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"plate", "kind", "valid"}); err != nil {
+			return err
+		}
+		for _, p := range plates {
+			if err := cw.Write([]string{p.Number, string(p.Kind), strconv.FormatBool(p.Valid)}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plates)
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Problem is an RFC 9457 problem details body.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// WriteProblem writes an application/problem+json error for the request.
+func WriteProblem(w http.ResponseWriter, r *http.Request, code int, detail string) {
+	WriteProblemWithExtensions(w, r, code, detail, nil)
+}
+
+// WriteProblemWithExtensions writes a problem with extra members next to the
+// standard ones, as RFC 9457 allows, so clients can act on the details
+// without parsing the message.
+func WriteProblemWithExtensions(w http.ResponseWriter, r *http.Request, code int, detail string, extensions map[string]any) {
+	problem := Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(code),
+		Status:   code,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	}
+	var body any = problem
+	if len(extensions) > 0 {
+		members := make(map[string]any, len(extensions)+5)
+		for k, v := range extensions {
+			members[k] = v
+		}
+		members["type"] = problem.Type
+		members["title"] = problem.Title
+		members["status"] = problem.Status
+		members["detail"] = problem.Detail
+		members["instance"] = problem.Instance
+		body = members
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Error().Err(err).Msg("Error encoding problem")
+	}
+}
@@ -0,0 +1,63 @@
+// Package secretbox encrypts small secrets, such as NIC API keys, before they
+// are stored in the database.
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+var ErrInvalidCiphertext = errors.New("secretbox: ciphertext is invalid or was sealed with another key")
+
+// Box seals and opens secrets with AES-256-GCM. Sealed values carry their own
+// random nonce, so sealing the same secret twice gives different output.
+type Box struct {
+	aead cipher.AEAD
+}
+
+// New builds a Box from a base64 encoded 32 byte key, as produced by
+// `openssl rand -base64 32`.
+func New(encodedKey string) (*Box, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return nil, errors.New("secretbox: key must be base64 encoded")
+	}
+	if len(key) != 32 {
+		return nil, errors.New("secretbox: key must be 32 bytes")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Box{aead: aead}, nil
+}
+
+// Seal encrypts plaintext and prefixes the result with its nonce.
+func (b *Box) Seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return b.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Open decrypts a value made by Seal.
+func (b *Box) Open(sealed []byte) ([]byte, error) {
+	if len(sealed) < b.aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	nonce, ciphertext := sealed[:b.aead.NonceSize()], sealed[b.aead.NonceSize():]
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
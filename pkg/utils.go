@@ -18,11 +18,71 @@ func WriteResponse(w http.ResponseWriter, code int, response interface{}) {
 	}
 }
 
+// SplitCars splits a cars field into its plates, which may be separated by
+// commas, tabs or line breaks.
+func SplitCars(cars string) []string {
+	return strings.FieldsFunc(cars, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r' || r == '\t'
+	})
+}
+
+// CanonicalPlate returns the form plates are stored and compared in: upper
+// case with spaces and hyphens removed, so "gr 1234-22" and "GR1234-22" match.
+func CanonicalPlate(plate string) string {
+	plate = strings.ToUpper(strings.TrimSpace(plate))
+	return strings.ReplaceAll(strings.ReplaceAll(plate, " ", ""), "-", "")
+}
+
+// DedupePlates keeps the first of plates that match in canonical form and
+// returns the repeats separately, in the order they were given.
+func DedupePlates(plates []string) (unique, duplicates []string) {
+	seen := make(map[string]bool, len(plates))
+	unique = make([]string, 0, len(plates))
+	duplicates = make([]string, 0)
+	for _, plate := range plates {
+		canonical := CanonicalPlate(plate)
+		if seen[canonical] {
+			duplicates = append(duplicates, plate)
+			continue
+		}
+		seen[canonical] = true
+		unique = append(unique, plate)
+	}
+	return unique, duplicates
+}
+
+// PlateKind is the format a Ghana plate was recognised as.
+type PlateKind string
+
+const (
+	PlateKindOldFormat    PlateKind = "old"
+	PlateKindNewFormat    PlateKind = "new"
+	PlateKindDVTrade      PlateKind = "dv"
+	PlateKindMotorcycle   PlateKind = "motorcycle"
+	PlateKindSpecial      PlateKind = "special"
+	PlateKindPersonalised PlateKind = "personalised"
+)
+
+// PlateInfo describes a valid plate. Code is the region code for old and new
+// format plates, the service code (e.g. GP) for special plates, DV for trade
+// plates and M for motorcycles. Personalised plates have no code.
+type PlateInfo struct {
+	Kind PlateKind `json:"kind"`
+	Code string    `json:"code"`
+}
+
 func ValidateGhanaLicensePlate(plate string) (bool, string) {
+	_, valid, msg := ParseGhanaLicensePlate(plate)
+	return valid, msg
+}
+
+// ParseGhanaLicensePlate validates a plate like ValidateGhanaLicensePlate and
+// also reports what kind of plate it is.
+func ParseGhanaLicensePlate(plate string) (PlateInfo, bool, string) {
 	// Clean the input: remove extra spaces and convert to uppercase
 	plate = strings.ToUpper(strings.TrimSpace(plate))
 	if plate == "" {
-		return false, "Empty plate number"
+		return PlateInfo{}, false, "Empty plate number"
 	}
 
 	// Remove all spaces and hyphens for consistent validation
@@ -69,7 +129,7 @@ func ValidateGhanaLicensePlate(plate string) (bool, string) {
 	// Format: DV + 4 digits + year suffix (e.g., DV123422 or DV1234-22)
 	dvPattern := regexp.MustCompile(`^(DV)(\d{4})(\d{2})$`)
 	if matches := dvPattern.FindStringSubmatch(cleanPlate); matches != nil {
-		return true, fmt.Sprintf("Valid DV trade plate (%s, year 20%s)",
+		return PlateInfo{Kind: PlateKindDVTrade, Code: "DV"}, true, fmt.Sprintf("Valid DV trade plate (%s, year 20%s)",
 			matches[1]+matches[2], matches[3])
 	}
 
@@ -80,10 +140,10 @@ func ValidateGhanaLicensePlate(plate string) (bool, string) {
 	motorcyclePattern2 := regexp.MustCompile(`^M-(\d{4,5})$`) // With hyphen
 
 	if matches := motorcyclePattern1.FindStringSubmatch(cleanPlate); matches != nil {
-		return true, fmt.Sprintf("Valid motorcycle plate (blue background, number %s)", matches[1])
+		return PlateInfo{Kind: PlateKindMotorcycle, Code: "M"}, true, fmt.Sprintf("Valid motorcycle plate (blue background, number %s)", matches[1])
 	}
 	if matches := motorcyclePattern2.FindStringSubmatch(cleanPlate); matches != nil {
-		return true, fmt.Sprintf("Valid motorcycle plate (blue background, number %s)", matches[1])
+		return PlateInfo{Kind: PlateKindMotorcycle, Code: "M"}, true, fmt.Sprintf("Valid motorcycle plate (blue background, number %s)", matches[1])
 	}
 
 	// Also check with hyphen (using original plate for hyphen variation)
@@ -91,7 +151,7 @@ func ValidateGhanaLicensePlate(plate string) (bool, string) {
 		numberPart := strings.TrimPrefix(strings.TrimPrefix(plate, "M-"), "M ")
 		numberPart = strings.TrimSpace(numberPart)
 		if len(numberPart) >= 4 && len(numberPart) <= 5 && regexp.MustCompile(`^\d+$`).MatchString(numberPart) {
-			return true, fmt.Sprintf("Valid motorcycle plate (blue background, number %s)", numberPart)
+			return PlateInfo{Kind: PlateKindMotorcycle, Code: "M"}, true, fmt.Sprintf("Valid motorcycle plate (blue background, number %s)", numberPart)
 		}
 	}
 
@@ -104,10 +164,10 @@ func ValidateGhanaLicensePlate(plate string) (bool, string) {
 		yearCode := matches[3]
 
 		if validRegionCodes[regionCode] {
-			return true, fmt.Sprintf("Valid old format (%s region, digits %s, year 20%s)",
+			return PlateInfo{Kind: PlateKindOldFormat, Code: regionCode}, true, fmt.Sprintf("Valid old format (%s region, digits %s, year 20%s)",
 				regionCode, digits, yearCode)
 		}
-		return false, fmt.Sprintf("Invalid region code: %s", regionCode)
+		return PlateInfo{}, false, fmt.Sprintf("Invalid region code: %s", regionCode)
 	}
 
 	// ============ PATTERN 4: NEW FORMAT WITH ZONE CODE ============
@@ -119,10 +179,10 @@ func ValidateGhanaLicensePlate(plate string) (bool, string) {
 		zoneCode := matches[3]
 
 		if validRegionCodes[regionCode] {
-			return true, fmt.Sprintf("Valid new format (%s region, digits %s, zone %s)",
+			return PlateInfo{Kind: PlateKindNewFormat, Code: regionCode}, true, fmt.Sprintf("Valid new format (%s region, digits %s, zone %s)",
 				regionCode, digits, zoneCode)
 		}
-		return false, fmt.Sprintf("Invalid region code: %s", regionCode)
+		return PlateInfo{}, false, fmt.Sprintf("Invalid region code: %s", regionCode)
 	}
 
 	// ============ PATTERN 5: SPECIAL FORMATS ============
@@ -133,19 +193,23 @@ func ValidateGhanaLicensePlate(plate string) (bool, string) {
 		digits := matches[2]
 
 		if specialCodes[code] {
-			return true, fmt.Sprintf("Valid special plate (%s, digits %s)", code, digits)
+			return PlateInfo{Kind: PlateKindSpecial, Code: code}, true, fmt.Sprintf("Valid special plate (%s, digits %s)", code, digits)
 		}
 
 		// Also check if it's a valid region code without year/zone
 		if validRegionCodes[code] {
-			return true, fmt.Sprintf("Valid basic format (%s region, digits %s)", code, digits)
+			return PlateInfo{Kind: PlateKindOldFormat, Code: code}, true, fmt.Sprintf("Valid basic format (%s region, digits %s)", code, digits)
 		}
 
-		return false, fmt.Sprintf("Invalid code: %s", code)
+		return PlateInfo{}, false, fmt.Sprintf("Invalid code: %s", code)
 	}
 
 	// ============ PATTERN 6: PERSONALISED PLATES ============
-	return validatePersonalisedPlate(plate, cleanPlate)
+	valid, msg := validatePersonalisedPlate(plate, cleanPlate)
+	if !valid {
+		return PlateInfo{}, false, msg
+	}
+	return PlateInfo{Kind: PlateKindPersonalised}, true, msg
 }
 
 func validatePersonalisedPlate(original string, clean string) (bool, string) {